@@ -0,0 +1,45 @@
+package main
+
+import (
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/s3"
+)
+
+// aclStore is the part of the S3 client the ACL pass uses.
+type aclStore interface {
+	ListObjects(prefix string, fn func(s3.Object) error) error
+	MakePrivate(key string) error
+}
+
+// aclReport summarises an ACL pass.
+type aclReport struct {
+	Scanned int
+	Updated int
+	Failed  int
+}
+
+// makePostsPrivate resets the ACL of every object under posts/ to private.
+// Post media uploaded while the bucket was public got a public-read ACL,
+// which keeps the object readable by its plain URL whatever the bucket
+// policy says. Avatars stay public through the policy and aren't touched.
+func makePostsPrivate(store aclStore, dryRun bool, log *logger.Logger) (*aclReport, error) {
+	result := &aclReport{}
+	err := store.ListObjects("posts/", func(object s3.Object) error {
+		result.Scanned++
+		if dryRun {
+			return nil
+		}
+
+		if err := store.MakePrivate(object.Key); err != nil {
+			log.Error("Failed to make %s private: %v", object.Key, err)
+			result.Failed++
+			return nil
+		}
+		result.Updated++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/s3"
+
+	"gorm.io/gorm"
+)
+
+// managedPrefixes are the parts of the bucket the services write to. Objects
+// outside them are never touched.
+var managedPrefixes = []string{"posts/", s3.PublicPrefix}
+
+// objectStore is the part of the S3 client the collector uses.
+type objectStore interface {
+	ListObjects(prefix string, fn func(s3.Object) error) error
+	DeleteFile(key string) error
+}
+
+// referencedKeysQuery lists every object key the database still points at.
+// Soft-deleted rows keep their objects until they have been deleted for
+// longer than the grace period.
+const referencedKeysQuery = `
+SELECT COALESCE(media_url, '') FROM posts
+WHERE deleted_at IS NULL OR deleted_at > @cutoff
+UNION
+SELECT COALESCE(thumbnail_url, '') FROM posts
+WHERE deleted_at IS NULL OR deleted_at > @cutoff
+UNION
+SELECT COALESCE(preview_url, '') FROM posts
+WHERE deleted_at IS NULL OR deleted_at > @cutoff
+UNION
+SELECT COALESCE(post_images.image_url, '') FROM post_images
+JOIN posts ON posts.id = post_images.post_id
+WHERE (post_images.deleted_at IS NULL OR post_images.deleted_at > @cutoff)
+  AND (posts.deleted_at IS NULL OR posts.deleted_at > @cutoff)
+UNION
+SELECT COALESCE(post_images.thumbnail_url, '') FROM post_images
+JOIN posts ON posts.id = post_images.post_id
+WHERE (post_images.deleted_at IS NULL OR post_images.deleted_at > @cutoff)
+  AND (posts.deleted_at IS NULL OR posts.deleted_at > @cutoff)
+UNION
+SELECT COALESCE(post_images.preview_url, '') FROM post_images
+JOIN posts ON posts.id = post_images.post_id
+WHERE (post_images.deleted_at IS NULL OR post_images.deleted_at > @cutoff)
+  AND (posts.deleted_at IS NULL OR posts.deleted_at > @cutoff)
+UNION
+SELECT variant.value FROM post_images
+JOIN posts ON posts.id = post_images.post_id
+CROSS JOIN LATERAL jsonb_each_text(COALESCE(post_images.variants, '{}'::jsonb)) AS variant
+WHERE (post_images.deleted_at IS NULL OR post_images.deleted_at > @cutoff)
+  AND (posts.deleted_at IS NULL OR posts.deleted_at > @cutoff)
+UNION
+SELECT COALESCE(avatar_url, '') FROM users
+WHERE deleted_at IS NULL OR deleted_at > @cutoff
+`
+
+func loadReferencedKeys(db *gorm.DB, cutoff time.Time) (map[string]bool, error) {
+	rows, err := db.Raw(referencedKeysQuery, sql.Named("cutoff", cutoff)).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referenced keys: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to load referenced keys: %w", err)
+		}
+		if key := objectKey(value); key != "" {
+			referenced[key] = true
+		}
+	}
+	return referenced, rows.Err()
+}
+
+// objectKey turns a stored reference into an object key. Post media is
+// stored as keys, avatars as public URLs.
+func objectKey(value string) string {
+	if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+		return value
+	}
+	for _, prefix := range managedPrefixes {
+		if i := strings.Index(value, "/"+prefix); i >= 0 {
+			return value[i+1:]
+		}
+	}
+	return ""
+}
+
+// report summarises a collection run.
+type report struct {
+	Scanned  int
+	Orphaned []s3.Object
+	Bytes    int64
+	Deleted  int
+	Failed   int
+}
+
+// collect finds the objects under the managed prefixes that aren't
+// referenced and were last modified before the cutoff, and deletes them
+// unless dryRun is set. The age check also covers uploads that are still in
+// progress: a direct upload or a post being created has objects before it
+// has rows.
+func collect(store objectStore, referenced map[string]bool, cutoff time.Time, dryRun bool, log *logger.Logger) (*report, error) {
+	result := &report{}
+	for _, prefix := range managedPrefixes {
+		err := store.ListObjects(prefix, func(object s3.Object) error {
+			result.Scanned++
+			if referenced[object.Key] || !object.LastModified.Before(cutoff) {
+				return nil
+			}
+
+			result.Orphaned = append(result.Orphaned, object)
+			result.Bytes += object.Size
+			if dryRun {
+				return nil
+			}
+
+			if err := store.DeleteFile(object.Key); err != nil {
+				log.Error("Failed to delete %s: %v", object.Key, err)
+				result.Failed++
+				return nil
+			}
+			result.Deleted++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/s3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	objects []s3.Object
+	deleted []string
+	private []string
+}
+
+func (s *fakeStore) ListObjects(prefix string, fn func(s3.Object) error) error {
+	for _, object := range s.objects {
+		if strings.HasPrefix(object.Key, prefix) {
+			if err := fn(object); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) DeleteFile(key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *fakeStore) MakePrivate(key string) error {
+	s.private = append(s.private, key)
+	return nil
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	cutoff := now.Add(-7 * 24 * time.Hour)
+	old := cutoff.Add(-time.Hour)
+
+	store := &fakeStore{objects: []s3.Object{
+		{Key: "posts/creator/kept.jpg", Size: 10, LastModified: old},
+		{Key: "posts/creator/deleted.jpg", Size: 20, LastModified: old},
+		{Key: "posts/creator/uploading.mp4", Size: 30, LastModified: now},
+		{Key: "avatars/user/replaced.png", Size: 40, LastModified: old},
+		{Key: "avatars/user/current.png", Size: 50, LastModified: old},
+		{Key: "backups/dump.sql", Size: 60, LastModified: old},
+	}}
+	referenced := map[string]bool{
+		"posts/creator/kept.jpg":   true,
+		"avatars/user/current.png": true,
+	}
+
+	result, err := collect(store, referenced, cutoff, true, logger.New())
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Scanned)
+	require.Len(t, result.Orphaned, 2)
+	assert.Equal(t, "posts/creator/deleted.jpg", result.Orphaned[0].Key)
+	assert.Equal(t, "avatars/user/replaced.png", result.Orphaned[1].Key)
+	assert.Equal(t, int64(60), result.Bytes)
+	assert.Empty(t, store.deleted, "a dry run deletes nothing")
+
+	result, err = collect(store, referenced, cutoff, false, logger.New())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Deleted)
+	assert.Equal(t, []string{"posts/creator/deleted.jpg", "avatars/user/replaced.png"}, store.deleted)
+}
+
+func TestObjectKey(t *testing.T) {
+	assert.Equal(t, "posts/creator/photo.jpg", objectKey("posts/creator/photo.jpg"))
+	assert.Equal(t, "avatars/user/a.png", objectKey("http://localhost:9000/lick-scroll-content/avatars/user/a.png"))
+	assert.Equal(t, "avatars/user/a.png", objectKey("https://bucket.s3.us-east-1.amazonaws.com/avatars/user/a.png"))
+	assert.Empty(t, objectKey("https://example.com/elsewhere.png"))
+	assert.Empty(t, objectKey(""))
+}
+
+func TestMakePostsPrivate(t *testing.T) {
+	store := &fakeStore{objects: []s3.Object{
+		{Key: "posts/creator/photo.jpg"},
+		{Key: "posts/creator/photo_thumbnail.jpg"},
+		{Key: "avatars/user/current.png"},
+	}}
+
+	result, err := makePostsPrivate(store, true, logger.New())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Scanned)
+	assert.Empty(t, store.private, "a dry run changes nothing")
+
+	result, err = makePostsPrivate(store, false, logger.New())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Updated)
+	assert.Equal(t, []string{"posts/creator/photo.jpg", "posts/creator/photo_thumbnail.jpg"}, store.private)
+}
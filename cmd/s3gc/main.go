@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/database"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/s3"
+)
+
+// s3gc removes objects that no post, post image or user refers to anymore:
+// media of deleted posts, replaced avatars, abandoned direct uploads and
+// files left behind by failed requests. With -private-acl it instead resets
+// the ACL of all post media to private, once, after the bucket went private.
+func main() {
+	var (
+		dryRun     = flag.Bool("dry-run", true, "only report unreferenced objects, don't delete them")
+		grace      = flag.Duration("grace", 7*24*time.Hour, "keep objects of rows deleted and files uploaded within this period")
+		privateACL = flag.Bool("private-acl", false, "reset the ACL of every posts/ object to private instead of collecting")
+	)
+	flag.Parse()
+
+	if *grace < time.Hour {
+		panic("grace period must be at least 1h, direct uploads stay open for that long")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	log := logger.New()
+	s3Client, err := s3.NewClient(cfg)
+	if err != nil {
+		log.Error("Failed to create S3 client: %v", err)
+		panic(err)
+	}
+
+	if *privateACL {
+		result, err := makePostsPrivate(s3Client, *dryRun, log)
+		if err != nil {
+			log.Error("Failed to reset object ACLs: %v", err)
+			panic(err)
+		}
+		if *dryRun {
+			log.Info("Dry run: %d objects would be made private, run with -dry-run=false to update them", result.Scanned)
+			return
+		}
+		log.Info("Made %d of %d objects private, %d failed", result.Updated, result.Scanned, result.Failed)
+		return
+	}
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Error("Failed to connect to database: %v", err)
+		panic(err)
+	}
+
+	// References are loaded before listing, so an object created after this
+	// point is too new to be collected even though it isn't in the set.
+	cutoff := time.Now().Add(-*grace)
+	referenced, err := loadReferencedKeys(db, cutoff)
+	if err != nil {
+		log.Error("%v", err)
+		panic(err)
+	}
+
+	result, err := collect(s3Client, referenced, cutoff, *dryRun, log)
+	if err != nil {
+		log.Error("Failed to collect objects: %v", err)
+		panic(err)
+	}
+
+	for _, object := range result.Orphaned {
+		fmt.Printf("%s\t%d\t%s\n", object.Key, object.Size, object.LastModified.Format(time.RFC3339))
+	}
+
+	if *dryRun {
+		log.Info("Dry run: %d of %d objects are unreferenced (%d bytes), run with -dry-run=false to delete them",
+			len(result.Orphaned), result.Scanned, result.Bytes)
+		return
+	}
+	log.Info("Deleted %d of %d unreferenced objects (%d bytes) out of %d, %d failed",
+		result.Deleted, len(result.Orphaned), result.Bytes, result.Scanned, result.Failed)
+}
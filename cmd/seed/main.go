@@ -192,24 +192,23 @@ func createPostWithCatImage(db *gorm.DB, s3Client *s3.Client, redisClient *redis
 	fileKey := fmt.Sprintf("posts/%s/seed_%d.jpg", userID, index)
 	reader := bytes.NewReader(imageData)
 	log.Info("Uploading image to S3: %s", fileKey)
-	imageURL, err := s3Client.UploadFile(fileKey, reader, "image/jpeg")
-	if err != nil {
+	if err := s3Client.UploadFile(fileKey, reader, "image/jpeg"); err != nil {
 		return fmt.Errorf("failed to upload image to S3: %w", err)
 	}
 
-	log.Info("Image uploaded successfully: %s", imageURL)
+	log.Info("Image uploaded successfully: %s", fileKey)
 
 	post := &models.Post{
 		CreatorID:   userID,
 		Title:       fmt.Sprintf("Cat Post #%d by %s", index+1, username),
 		Description: fmt.Sprintf("A cute cat from CATAAS API! Post #%d", index+1),
 		Type:        models.PostTypePhoto,
-		MediaURL:    imageURL,
+		MediaURL:    fileKey,
 		Category:    "cats",
 		Status:      models.StatusApproved,
 		Images: []models.PostImage{
 			{
-				ImageURL: imageURL,
+				ImageURL: fileKey,
 				Order:    0,
 			},
 		},
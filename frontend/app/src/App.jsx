@@ -4,6 +4,9 @@ import { authService } from './services/authService';
 import { websocketService } from './services/websocketService';
 import Login from './pages/Login';
 import Register from './pages/Register';
+import VerifyEmail from './pages/VerifyEmail';
+import ForgotPassword from './pages/ForgotPassword';
+import ResetPassword from './pages/ResetPassword';
 import Feed from './pages/Feed';
 import Profile from './pages/Profile';
 import UserProfile from './pages/UserProfile';
@@ -72,8 +75,11 @@ function App() {
         />
         <Route 
           path="/register" 
-          element={<Register onRegister={setUser} />} 
+          element={<Register />} 
         />
+        <Route path="/verify-email" element={<VerifyEmail />} />
+        <Route path="/forgot-password" element={<ForgotPassword />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
         <Route
           path="/"
           element={
@@ -154,7 +154,7 @@ function Feed() {
   const [loading, setLoading] = useState(false);
   const [loadingMore, setLoadingMore] = useState(false);
   const [error, setError] = useState('');
-  const [offset, setOffset] = useState(0);
+  const [cursor, setCursor] = useState('');
   const [hasMore, setHasMore] = useState(true);
   const containerRef = useRef(null);
   const touchStartY = useRef(0);
@@ -213,7 +213,7 @@ function Feed() {
     return () => window.removeEventListener('keydown', handleKeyDown);
   }, [currentIndex, posts.length, navigate]);
 
-  const loadFeed = async (loadOffset = 0, append = false) => {
+  const loadFeed = async (loadCursor = '', append = false) => {
     // Check if user is authenticated
     if (!authService.isAuthenticated()) {
       navigate('/login', { replace: true });
@@ -227,15 +227,16 @@ function Feed() {
     }
     setError('');
     try {
-      const response = await api.get(`${API_BASE.feed}/feed?limit=100&offset=${loadOffset}`);
+      const cursorParam = loadCursor ? `&cursor=${encodeURIComponent(loadCursor)}` : '';
+      const response = await api.get(`${API_BASE.feed}/feed?limit=100${cursorParam}`);
       const postsData = response.data.posts || [];
       
       // If no new posts and we're appending, reset to beginning
       if (postsData.length === 0 && append) {
-        setOffset(0);
+        setCursor('');
         setHasMore(true);
         // Start from beginning
-        loadFeed(0, false);
+        loadFeed('', false);
         return;
       }
 
@@ -259,14 +260,14 @@ function Feed() {
           }
           return newPosts;
         });
-        setOffset(prevOffset => prevOffset + postsWithLikes.length);
       } else {
         setPosts(postsWithLikes);
-        setOffset(postsWithLikes.length);
       }
-      
-      // If we got less than requested, there are no more posts
-      setHasMore(postsWithLikes.length === 100);
+
+      // The feed returns no cursor after its last page
+      const nextCursor = response.data.next_cursor || '';
+      setCursor(nextCursor);
+      setHasMore(nextCursor !== '');
     } catch (err) {
       console.error('Failed to load feed:', err);
       if (err.response?.status === 401) {
@@ -343,7 +344,7 @@ function Feed() {
     if (currentIndex === posts.length - 1) {
       if (hasMore && !loadingMore) {
         // Load next batch - currentIndex will be updated in loadFeed
-        loadFeed(offset, true);
+        loadFeed(cursor, true);
         return;
       } else if (!hasMore && !loadingMore) {
         // No more posts, start from beginning
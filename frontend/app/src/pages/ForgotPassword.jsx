@@ -0,0 +1,61 @@
+import { useState } from 'react';
+import { Link } from 'react-router-dom';
+import { authService } from '../services/authService';
+import './Auth.css';
+
+function ForgotPassword() {
+  const [email, setEmail] = useState('');
+  const [error, setError] = useState('');
+  const [sent, setSent] = useState(false);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    setLoading(true);
+
+    try {
+      await authService.requestPasswordReset(email);
+      setSent(true);
+    } catch (err) {
+      setError(err.response?.data?.error || 'Не удалось отправить письмо');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <h1>Восстановление пароля</h1>
+        {sent ? (
+          <div className="success-message">
+            Если {email} зарегистрирован, на него придет письмо со ссылкой для сброса пароля. Ссылка действует 1 час.
+          </div>
+        ) : (
+          <form onSubmit={handleSubmit}>
+            <div className="form-group">
+              <label>Email</label>
+              <input
+                type="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                required
+                placeholder="user@example.com"
+              />
+            </div>
+            {error && <div className="error-message">{error}</div>}
+            <button type="submit" disabled={loading} className="btn-primary">
+              {loading ? 'Отправка...' : 'Отправить ссылку'}
+            </button>
+          </form>
+        )}
+        <p className="auth-link">
+          <Link to="/login">Вернуться ко входу</Link>
+        </p>
+      </div>
+    </div>
+  );
+}
+
+export default ForgotPassword;
@@ -3,33 +3,124 @@ import { useNavigate, Link, useSearchParams } from 'react-router-dom';
 import { authService } from '../services/authService';
 import './Auth.css';
 
+// Вход блокируется после нескольких неудачных попыток
+const lockoutMessage = (err) => {
+  const seconds = Number(err.response?.headers?.['retry-after'] || err.response?.data?.retry_after) || 60;
+  return `Слишком много неудачных попыток. Попробуйте через ${Math.ceil(seconds / 60)} мин.`;
+};
+
 function Login({ onLogin }) {
   const [email, setEmail] = useState('');
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
+  const [notice, setNotice] = useState('');
+  const [unverified, setUnverified] = useState(false);
+  const [mfaToken, setMfaToken] = useState('');
+  const [code, setCode] = useState('');
   const [loading, setLoading] = useState(false);
   const navigate = useNavigate();
   const [searchParams] = useSearchParams();
 
   const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    setNotice('');
+    setUnverified(false);
+    setLoading(true);
+
+    try {
+      const data = await authService.login(email, password);
+      if (data.mfa_required) {
+        // Пароль верный, осталось ввести код из приложения
+        setMfaToken(data.mfa_token);
+        return;
+      }
+      finishLogin();
+    } catch (err) {
+      if (err.response?.data?.error === 'email not verified') {
+        setUnverified(true);
+        setError('Email не подтвержден. Откройте ссылку из письма, отправленного при регистрации.');
+      } else if (err.response?.status === 429) {
+        setError(lockoutMessage(err));
+      } else {
+        setError(err.response?.data?.error || 'Ошибка входа');
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const finishLogin = () => {
+    const user = authService.getCurrentUser();
+    onLogin(user);
+    // Перенаправляем на сохранённый путь или на ленту (/)
+    const redirect = searchParams.get('redirect') || '/';
+    navigate(redirect, { replace: true });
+  };
+
+  const handleMfaSubmit = async (e) => {
     e.preventDefault();
     setError('');
     setLoading(true);
 
     try {
-      await authService.login(email, password);
-      const user = authService.getCurrentUser();
-      onLogin(user);
-      // Перенаправляем на сохранённый путь или на ленту (/)
-      const redirect = searchParams.get('redirect') || '/';
-      navigate(redirect, { replace: true });
+      await authService.completeMfaLogin(mfaToken, code);
+      finishLogin();
     } catch (err) {
-      setError(err.response?.data?.error || 'Ошибка входа');
+      if (err.response?.status === 429) {
+        setError(lockoutMessage(err));
+      } else if (err.response?.data?.error === 'invalid or expired mfa token') {
+        // Токен истек или закончились попытки - нужно снова ввести пароль
+        setMfaToken('');
+        setCode('');
+        setError('Время на ввод кода истекло. Войдите снова.');
+      } else {
+        setError('Неверный код');
+      }
     } finally {
       setLoading(false);
     }
   };
 
+  const handleResend = async () => {
+    setError('');
+    try {
+      await authService.resendVerification(email);
+      setUnverified(false);
+      setNotice(`Письмо со ссылкой отправлено на ${email}`);
+    } catch (err) {
+      setError(err.response?.data?.error || 'Не удалось отправить письмо');
+    }
+  };
+
+  if (mfaToken) {
+    return (
+      <div className="auth-container">
+        <div className="auth-card">
+          <h1>Двухфакторная аутентификация</h1>
+          <form onSubmit={handleMfaSubmit}>
+            <div className="form-group">
+              <label>Код из приложения или код восстановления</label>
+              <input
+                type="text"
+                value={code}
+                onChange={(e) => setCode(e.target.value)}
+                required
+                autoFocus
+                autoComplete="one-time-code"
+                placeholder="123456"
+              />
+            </div>
+            {error && <div className="error-message">{error}</div>}
+            <button type="submit" disabled={loading} className="btn-primary">
+              {loading ? 'Проверка...' : 'Подтвердить'}
+            </button>
+          </form>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="auth-container">
       <div className="auth-card">
@@ -56,10 +147,21 @@ function Login({ onLogin }) {
             />
           </div>
           {error && <div className="error-message">{error}</div>}
+          {unverified && (
+            <p className="auth-link">
+              <button type="button" className="btn-link" onClick={handleResend}>
+                Отправить письмо еще раз
+              </button>
+            </p>
+          )}
+          {notice && <div className="success-message">{notice}</div>}
           <button type="submit" disabled={loading} className="btn-primary">
             {loading ? 'Вход...' : 'Войти'}
           </button>
         </form>
+        <p className="auth-link">
+          <Link to="/forgot-password">Забыли пароль?</Link>
+        </p>
         <p className="auth-link">
           Нет аккаунта? <Link to="/register">Зарегистрироваться</Link>
         </p>
@@ -12,11 +12,17 @@ function Profile({ user: initialUser }) {
   const [uploading, setUploading] = useState(false);
   const [topupAmount, setTopupAmount] = useState('');
   const [topupLoading, setTopupLoading] = useState(false);
+  const [mfa, setMfa] = useState(null);
+  const [mfaSetup, setMfaSetup] = useState(null);
+  const [mfaCode, setMfaCode] = useState('');
+  const [recoveryCodes, setRecoveryCodes] = useState([]);
+  const [mfaLoading, setMfaLoading] = useState(false);
   const navigate = useNavigate();
 
   useEffect(() => {
     loadWallet();
     loadUser();
+    loadMfa();
   }, []);
 
   useEffect(() => {
@@ -92,6 +98,59 @@ function Profile({ user: initialUser }) {
     }
   };
 
+  const loadMfa = async () => {
+    try {
+      setMfa(await authService.getMfaStatus());
+    } catch (err) {
+      console.error('Ошибка загрузки настроек 2FA:', err);
+    }
+  };
+
+  const handleEnrollTotp = async () => {
+    setMfaLoading(true);
+    try {
+      setMfaSetup(await authService.enrollTotp());
+      setRecoveryCodes([]);
+    } catch (err) {
+      alert(err.response?.data?.error || 'Не удалось начать подключение 2FA');
+    } finally {
+      setMfaLoading(false);
+    }
+  };
+
+  // Включает 2FA, если 2FA выключена, иначе выключает её
+  const handleMfaCode = async (e) => {
+    e.preventDefault();
+    setMfaLoading(true);
+    try {
+      if (mfa?.enabled) {
+        await authService.disableTotp(mfaCode);
+      } else {
+        const data = await authService.confirmTotp(mfaCode);
+        setRecoveryCodes(data.recovery_codes);
+        setMfaSetup(null);
+      }
+      setMfaCode('');
+      await loadMfa();
+    } catch (err) {
+      alert(err.response?.data?.error === 'invalid code' ? 'Неверный код' : (err.response?.data?.error || 'Ошибка 2FA'));
+    } finally {
+      setMfaLoading(false);
+    }
+  };
+
+  const handleRegenerateCodes = async () => {
+    const code = prompt('Введите код из приложения, чтобы получить новые коды восстановления');
+    if (!code) return;
+    try {
+      const data = await authService.regenerateRecoveryCodes(code);
+      setRecoveryCodes(data.recovery_codes);
+      await loadMfa();
+    } catch (err) {
+      alert(err.response?.data?.error === 'invalid code' ? 'Неверный код' : (err.response?.data?.error || 'Ошибка 2FA'));
+    }
+  };
+
   const handleTopUp = async (e) => {
     e.preventDefault();
     const amount = parseInt(topupAmount);
@@ -165,6 +224,59 @@ function Profile({ user: initialUser }) {
           </form>
         </div>
       )}
+      {mfa && (
+        <div className="profile-card">
+          <h2>Двухфакторная аутентификация</h2>
+          {mfa.enabled ? (
+            <p>
+              Включена. Осталось кодов восстановления: {mfa.recovery_codes_remaining}.{' '}
+              <button type="button" className="btn-link" onClick={handleRegenerateCodes}>
+                Получить новые
+              </button>
+            </p>
+          ) : (
+            <p>
+              Выключена.
+              {mfa.required && ' Без неё нельзя выводить средства и модерировать.'}
+            </p>
+          )}
+          {mfaSetup && (
+            <div className="mfa-setup">
+              <p>Добавьте аккаунт в приложение-аутентификатор по ссылке или введите ключ вручную:</p>
+              <p><a href={mfaSetup.provisioning_uri}>Открыть в приложении</a></p>
+              <p className="mfa-secret">{mfaSetup.secret}</p>
+            </div>
+          )}
+          {recoveryCodes.length > 0 && (
+            <div className="mfa-setup">
+              <p>Сохраните коды восстановления. Каждый срабатывает один раз, и больше они показаны не будут:</p>
+              <ul className="recovery-codes">
+                {recoveryCodes.map((code) => <li key={code}>{code}</li>)}
+              </ul>
+            </div>
+          )}
+          {!mfa.enabled && !mfaSetup ? (
+            <button type="button" className="topup-btn" onClick={handleEnrollTotp} disabled={mfaLoading}>
+              Подключить
+            </button>
+          ) : (
+            <form onSubmit={handleMfaCode} className="topup-form">
+              <input
+                type="text"
+                placeholder={mfa.enabled ? 'Код для отключения' : 'Код из приложения'}
+                value={mfaCode}
+                onChange={(e) => setMfaCode(e.target.value)}
+                className="topup-input"
+                autoComplete="one-time-code"
+                disabled={mfaLoading}
+              />
+              <button type="submit" className="topup-btn" disabled={mfaLoading || !mfaCode}>
+                {mfa.enabled ? 'Отключить' : 'Включить'}
+              </button>
+            </form>
+          )}
+        </div>
+      )}
       <div className="profile-card">
         <h2>Мои посты ({posts.length})</h2>
         {posts.length === 0 ? (
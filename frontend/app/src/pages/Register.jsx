@@ -1,15 +1,15 @@
 import { useState } from 'react';
-import { useNavigate, Link } from 'react-router-dom';
+import { Link } from 'react-router-dom';
 import { authService } from '../services/authService';
 import './Auth.css';
 
-function Register({ onRegister }) {
+function Register() {
   const [email, setEmail] = useState('');
   const [username, setUsername] = useState('');
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
-  const navigate = useNavigate();
+  const [registered, setRegistered] = useState(false);
 
   const handleSubmit = async (e) => {
     e.preventDefault();
@@ -18,10 +18,8 @@ function Register({ onRegister }) {
 
     try {
       await authService.register(email, username, password);
-      const user = authService.getCurrentUser();
-      onRegister(user);
-      // После регистрации перенаправляем на ленту
-      navigate('/', { replace: true });
+      // Войти можно только после подтверждения email
+      setRegistered(true);
     } catch (err) {
       setError(err.response?.data?.error || 'Ошибка регистрации');
     } finally {
@@ -29,6 +27,22 @@ function Register({ onRegister }) {
     }
   };
 
+  if (registered) {
+    return (
+      <div className="auth-container">
+        <div className="auth-card">
+          <h1>Подтвердите email</h1>
+          <div className="success-message">
+            Мы отправили письмо на {email}. Откройте ссылку из письма, чтобы подтвердить адрес и войти.
+          </div>
+          <p className="auth-link">
+            <Link to="/login">Перейти ко входу</Link>
+          </p>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="auth-container">
       <div className="auth-card">
@@ -0,0 +1,83 @@
+import { useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { authService } from '../services/authService';
+import './Auth.css';
+
+function ResetPassword() {
+  const [searchParams] = useSearchParams();
+  const [password, setPassword] = useState('');
+  const [confirmation, setConfirmation] = useState('');
+  const [error, setError] = useState('');
+  const [done, setDone] = useState(false);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    if (password !== confirmation) {
+      setError('Пароли не совпадают');
+      return;
+    }
+    setLoading(true);
+
+    try {
+      await authService.resetPassword(searchParams.get('token') || '', password);
+      // Все сессии завершены, включая сохраненную в этом браузере
+      authService.logout();
+      setDone(true);
+    } catch (err) {
+      setError(err.response?.data?.error === 'invalid or expired token'
+        ? 'Ссылка недействительна или устарела, запросите новую'
+        : err.response?.data?.error || 'Не удалось сменить пароль');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <h1>Новый пароль</h1>
+        {done ? (
+          <div className="success-message">Пароль изменен, войдите с новым паролем.</div>
+        ) : (
+          <form onSubmit={handleSubmit}>
+            <div className="form-group">
+              <label>Новый пароль</label>
+              <input
+                type="password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                required
+                minLength={6}
+                placeholder="••••••••"
+              />
+            </div>
+            <div className="form-group">
+              <label>Повторите пароль</label>
+              <input
+                type="password"
+                value={confirmation}
+                onChange={(e) => setConfirmation(e.target.value)}
+                required
+                minLength={6}
+                placeholder="••••••••"
+              />
+            </div>
+            {error && <div className="error-message">{error}</div>}
+            <button type="submit" disabled={loading} className="btn-primary">
+              {loading ? 'Сохранение...' : 'Сменить пароль'}
+            </button>
+          </form>
+        )}
+        <p className="auth-link">
+          <Link to={done ? '/login' : '/forgot-password'}>
+            {done ? 'Перейти ко входу' : 'Запросить новую ссылку'}
+          </Link>
+        </p>
+      </div>
+    </div>
+  );
+}
+
+export default ResetPassword;
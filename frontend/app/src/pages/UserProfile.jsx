@@ -87,12 +87,6 @@ function UserProfile() {
     try {
       await api.post(`${API_BASE.auth}/users/${currentUser.id}/subscriptions/${userId}`);
       setIsSubscribed(true);
-      // Process notification queue after subscribing
-      try {
-        await api.post(`${API_BASE.notification}/notifications/process-queue`);
-      } catch (err) {
-        console.warn('Failed to process notification queue:', err);
-      }
     } catch (err) {
       console.error('Failed to subscribe:', err);
       alert(err.response?.data?.error || 'Ошибка подписки');
@@ -111,12 +105,6 @@ function UserProfile() {
       } else {
         await api.post(`${API_BASE.notification}/notifications/settings/${userId}`);
         setNotificationsEnabled(true);
-        // Process notification queue after enabling
-        try {
-          await api.post(`${API_BASE.notification}/notifications/process-queue`);
-        } catch (err) {
-          console.warn('Failed to process notification queue:', err);
-        }
       }
     } catch (err) {
       console.error('Failed to toggle notifications:', err);
@@ -127,7 +115,13 @@ function UserProfile() {
   const handleUnsubscribe = async () => {
     if (!currentUser) return;
     try {
-      await api.delete(`${API_BASE.auth}/users/${currentUser.id}/subscriptions/${userId}`);
+      const response = await api.delete(`${API_BASE.auth}/users/${currentUser.id}/subscriptions/${userId}`);
+      if (response.data?.current_period_end) {
+        // A paid subscription stays active until the end of the paid period
+        const endsAt = new Date(response.data.current_period_end).toLocaleDateString();
+        alert(`Платная подписка отменена и закончится ${endsAt}`);
+        return;
+      }
       setIsSubscribed(false);
       // Also disable notifications when unsubscribing
       setNotificationsEnabled(false);
@@ -0,0 +1,52 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { authService } from '../services/authService';
+import './Auth.css';
+
+function VerifyEmail() {
+  const [searchParams] = useSearchParams();
+  const [status, setStatus] = useState('loading');
+  const [error, setError] = useState('');
+  // Ссылка одноразовая: в StrictMode эффект вызывается дважды
+  const requested = useRef(false);
+
+  useEffect(() => {
+    if (requested.current) {
+      return;
+    }
+    requested.current = true;
+
+    const token = searchParams.get('token');
+    if (!token) {
+      setStatus('error');
+      setError('Ссылка неполная');
+      return;
+    }
+
+    authService
+      .verifyEmail(token)
+      .then(() => setStatus('done'))
+      .catch((err) => {
+        setStatus('error');
+        setError(err.response?.data?.error === 'invalid or expired token'
+          ? 'Ссылка недействительна или устарела. Войдите, чтобы получить новое письмо.'
+          : err.response?.data?.error || 'Не удалось подтвердить email');
+      });
+  }, [searchParams]);
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <h1>Подтверждение email</h1>
+        {status === 'loading' && <p className="auth-link">Проверяем ссылку...</p>}
+        {status === 'done' && <div className="success-message">Email подтвержден, теперь можно войти.</div>}
+        {status === 'error' && <div className="error-message">{error}</div>}
+        <p className="auth-link">
+          <Link to="/login">Перейти ко входу</Link>
+        </p>
+      </div>
+    </div>
+  );
+}
+
+export default VerifyEmail;
@@ -1,36 +1,113 @@
-import api, { API_BASE } from './api';
+import axios from 'axios';
+import api, { API_BASE, refreshTokens } from './api';
+
+const saveSession = (data) => {
+  localStorage.setItem('authToken', data.token);
+  localStorage.setItem('refreshToken', data.refresh_token);
+  localStorage.setItem('currentUser', JSON.stringify(data.user));
+};
 
 export const authService = {
+  // The account can log in only after the emailed link is opened
   async register(email, username, password) {
     const response = await api.post(`${API_BASE.auth}/register`, {
       email,
       username,
       password
     });
-    if (response.data.token) {
-      localStorage.setItem('authToken', response.data.token);
-      localStorage.setItem('currentUser', JSON.stringify(response.data.user));
-    }
     return response.data;
   },
 
+  async verifyEmail(token) {
+    const response = await api.post(`${API_BASE.auth}/verify-email`, { token });
+    return response.data;
+  },
+
+  async resendVerification(email) {
+    const response = await api.post(`${API_BASE.auth}/verify-email/resend`, { email });
+    return response.data;
+  },
+
+  async requestPasswordReset(email) {
+    const response = await api.post(`${API_BASE.auth}/password-reset/request`, { email });
+    return response.data;
+  },
+
+  async resetPassword(token, password) {
+    const response = await api.post(`${API_BASE.auth}/password-reset/confirm`, { token, password });
+    return response.data;
+  },
+
+  // Users with two-factor authentication get { mfa_required, mfa_token }
+  // and finish with completeMfaLogin
   async login(email, password) {
     const response = await api.post(`${API_BASE.auth}/login`, {
       email,
       password
     });
     if (response.data.token) {
-      localStorage.setItem('authToken', response.data.token);
-      localStorage.setItem('currentUser', JSON.stringify(response.data.user));
+      saveSession(response.data);
     }
     return response.data;
   },
 
+  async completeMfaLogin(mfaToken, code) {
+    const response = await api.post(`${API_BASE.auth}/login/mfa`, {
+      mfa_token: mfaToken,
+      code
+    });
+    saveSession(response.data);
+    return response.data;
+  },
+
+  async getMfaStatus() {
+    const response = await api.get(`${API_BASE.auth}/mfa`);
+    return response.data;
+  },
+
+  async enrollTotp() {
+    const response = await api.post(`${API_BASE.auth}/mfa/totp/enroll`);
+    return response.data;
+  },
+
+  // Returns the recovery codes. The session only counts as two-factor
+  // after a refresh, which this does
+  async confirmTotp(code) {
+    const response = await api.post(`${API_BASE.auth}/mfa/totp/confirm`, { code });
+    await this.refreshSession();
+    return response.data;
+  },
+
+  async disableTotp(code) {
+    const response = await api.post(`${API_BASE.auth}/mfa/totp/disable`, { code });
+    return response.data;
+  },
+
+  async regenerateRecoveryCodes(code) {
+    const response = await api.post(`${API_BASE.auth}/mfa/recovery-codes`, { code });
+    return response.data;
+  },
+
   logout() {
+    // Revoke the session on the server; local state is cleared regardless
+    const token = localStorage.getItem('authToken');
+    if (token) {
+      axios
+        .post(`${API_BASE.auth}/logout`, null, { headers: { Authorization: `Bearer ${token}` } })
+        .catch(() => {});
+    }
     localStorage.removeItem('authToken');
+    localStorage.removeItem('refreshToken');
     localStorage.removeItem('currentUser');
   },
 
+  // Re-issues the access token so it carries the user's current role,
+  // e.g. after a creator application was approved
+  async refreshSession() {
+    await refreshTokens();
+    return this.validateToken();
+  },
+
   getCurrentUser() {
     const userStr = localStorage.getItem('currentUser');
     return userStr ? JSON.parse(userStr) : null;
@@ -35,6 +35,9 @@ class WebSocketService {
       this.ws.onmessage = (event) => {
         try {
           const notification = JSON.parse(event.data);
+          if (notification.type === 'creator_application' && notification.data?.status === 'approved') {
+            authService.refreshSession().catch(() => {});
+          }
           this.notifyListeners(notification);
         } catch (err) {
           console.error('Failed to parse notification:', err);
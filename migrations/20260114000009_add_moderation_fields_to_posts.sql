@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts ADD COLUMN moderation_reason TEXT;
+ALTER TABLE posts ADD COLUMN moderated_by UUID;
+ALTER TABLE posts ADD COLUMN moderated_at TIMESTAMP;
+CREATE INDEX idx_posts_status_created_at ON posts(status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_status_created_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE posts DROP COLUMN IF EXISTS moderation_reason;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_transactions_purchase_unique ON transactions(user_id, post_id) WHERE type = 'purchase';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_purchase_unique;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subscription_tiers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    price INTEGER NOT NULL CHECK (price > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    CONSTRAINT fk_subscription_tiers_creator FOREIGN KEY (creator_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_subscription_tiers_creator_id ON subscription_tiers(creator_id);
+
+-- subscriptions.type is 'free' (follow only), 'paid' (tier access until
+-- current_period_end) or 'lapsed' (renewal failed, back to follow only)
+ALTER TABLE subscriptions ADD COLUMN tier_id UUID;
+ALTER TABLE subscriptions ADD COLUMN current_period_end TIMESTAMP;
+ALTER TABLE subscriptions ADD CONSTRAINT fk_subscriptions_tier FOREIGN KEY (tier_id) REFERENCES subscription_tiers(id) ON DELETE SET NULL;
+CREATE INDEX idx_subscriptions_type_period_end ON subscriptions(type, current_period_end);
+
+ALTER TABLE posts ADD COLUMN subscriber_only BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE posts DROP COLUMN IF EXISTS subscriber_only;
+DROP INDEX IF EXISTS idx_subscriptions_type_period_end;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS fk_subscriptions_tier;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS current_period_end;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS tier_id;
+DROP TABLE IF EXISTS subscription_tiers;
+-- +goose StatementEnd
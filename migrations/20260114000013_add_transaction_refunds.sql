@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN transfer_id UUID;
+ALTER TABLE transactions ADD COLUMN refunded_transaction_id UUID;
+ALTER TABLE transactions ADD CONSTRAINT fk_transactions_refunded_transaction FOREIGN KEY (refunded_transaction_id) REFERENCES transactions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_transfer_id ON transactions(transfer_id);
+CREATE UNIQUE INDEX idx_transactions_refund_unique ON transactions(refunded_transaction_id) WHERE refunded_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_refund_unique;
+DROP INDEX IF EXISTS idx_transactions_transfer_id;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transactions_refunded_transaction;
+ALTER TABLE transactions DROP COLUMN IF EXISTS refunded_transaction_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets ADD COLUMN held_balance INTEGER NOT NULL DEFAULT 0 CHECK (held_balance >= 0);
+
+CREATE TABLE payouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID,
+    reason TEXT,
+    provider_reference VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    CONSTRAINT fk_payouts_creator FOREIGN KEY (creator_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_payouts_reviewer FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_payouts_creator_id ON payouts(creator_id, created_at DESC);
+CREATE INDEX idx_payouts_status ON payouts(status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payouts;
+ALTER TABLE wallets DROP COLUMN IF EXISTS held_balance;
+-- +goose StatementEnd
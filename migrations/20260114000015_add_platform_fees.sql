@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- System account that owns the platform wallet. It is inactive and its
+-- password is not a valid bcrypt hash, so nobody can log in as it. Without a
+-- conflict target the insert also skips when someone already registered the
+-- email or username; the check below then stops the migration, since the
+-- wallet and fees need this exact id.
+INSERT INTO users (id, email, username, password, role, is_active)
+VALUES ('00000000-0000-0000-0000-000000000001', 'platform@lick-scroll.local', 'platform', '!', 'viewer', false)
+ON CONFLICT DO NOTHING;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM users WHERE id = '00000000-0000-0000-0000-000000000001') THEN
+        RAISE EXCEPTION 'platform account could not be created: username "platform" or email platform@lick-scroll.local belongs to another user, rename it and rerun the migration';
+    END IF;
+END
+$$;
+
+INSERT INTO wallets (user_id, balance)
+VALUES ('00000000-0000-0000-0000-000000000001', 0)
+ON CONFLICT (user_id) DO NOTHING;
+
+CREATE TABLE creator_fee_overrides (
+    creator_id UUID PRIMARY KEY,
+    fee_percent INTEGER NOT NULL CHECK (fee_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_creator_fee_overrides_creator FOREIGN KEY (creator_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS creator_fee_overrides;
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-000000000001';
+-- +goose StatementEnd
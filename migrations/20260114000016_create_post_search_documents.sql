@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Title weighs most, then the creator's username and category, then the
+-- description. The "simple" configuration doesn't stem, so posts in any
+-- language are matched word for word.
+CREATE FUNCTION post_search_vector(title TEXT, description TEXT, category TEXT, username TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+           setweight(to_tsvector('simple', COALESCE(username, '')), 'B') ||
+           setweight(to_tsvector('simple', COALESCE(category, '')), 'B') ||
+           setweight(to_tsvector('simple', COALESCE(description, '')), 'C')
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE TABLE post_search_documents (
+    post_id UUID PRIMARY KEY,
+    creator_username VARCHAR(255) NOT NULL,
+    document TSVECTOR NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_post_search_documents_post FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_search_documents_document ON post_search_documents USING GIN(document);
+CREATE INDEX idx_post_search_documents_username ON post_search_documents(LOWER(creator_username) text_pattern_ops);
+CREATE INDEX idx_users_username_prefix ON users(LOWER(username) text_pattern_ops);
+
+INSERT INTO post_search_documents (post_id, creator_username, document)
+SELECT posts.id, users.username, post_search_vector(posts.title, posts.description, posts.category, users.username)
+FROM posts
+JOIN users ON users.id = posts.creator_id
+WHERE posts.status = 'approved' AND posts.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_username_prefix;
+DROP TABLE IF EXISTS post_search_documents;
+DROP FUNCTION IF EXISTS post_search_vector(TEXT, TEXT, TEXT, TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE post_images ADD COLUMN variants JSONB;
+ALTER TABLE post_images ADD COLUMN processing_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE post_images DROP COLUMN IF EXISTS processing_status;
+ALTER TABLE post_images DROP COLUMN IF EXISTS variants;
+-- +goose StatementEnd
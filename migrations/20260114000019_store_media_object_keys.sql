@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Post media used to be stored as public URLs. The bucket is private now, so
+-- the columns hold object keys ("posts/...") that are presigned when served.
+UPDATE posts
+SET media_url = substring(media_url from position('/posts/' in media_url) + 1)
+WHERE media_url LIKE 'http%' AND position('/posts/' in media_url) > 0;
+
+UPDATE posts
+SET thumbnail_url = substring(thumbnail_url from position('/posts/' in thumbnail_url) + 1)
+WHERE thumbnail_url LIKE 'http%' AND position('/posts/' in thumbnail_url) > 0;
+
+UPDATE post_images
+SET image_url = substring(image_url from position('/posts/' in image_url) + 1)
+WHERE image_url LIKE 'http%' AND position('/posts/' in image_url) > 0;
+
+UPDATE post_images
+SET thumbnail_url = substring(thumbnail_url from position('/posts/' in thumbnail_url) + 1)
+WHERE thumbnail_url LIKE 'http%' AND position('/posts/' in thumbnail_url) > 0;
+
+UPDATE post_images
+SET variants = (
+    SELECT jsonb_object_agg(
+        variant.key,
+        CASE
+            WHEN variant.value LIKE 'http%' AND position('/posts/' in variant.value) > 0
+                THEN substring(variant.value from position('/posts/' in variant.value) + 1)
+            ELSE variant.value
+        END
+    )
+    FROM jsonb_each_text(post_images.variants) AS variant
+)
+WHERE variants IS NOT NULL AND variants <> '{}'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Keys are not converted back: the URLs depended on the endpoint the objects
+-- were uploaded through, and public URLs no longer work with a private bucket.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Viewers apply to become creators; a moderator approves or rejects each
+-- application. A user can have only one pending application at a time.
+CREATE TABLE creator_applications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP,
+    CONSTRAINT fk_creator_applications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_creator_applications_reviewer FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_creator_applications_pending ON creator_applications(user_id) WHERE status = 'pending';
+CREATE INDEX idx_creator_applications_user_id ON creator_applications(user_id, created_at DESC);
+CREATE INDEX idx_creator_applications_status ON creator_applications(status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS creator_applications;
+-- +goose StatementEnd
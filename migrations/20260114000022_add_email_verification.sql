@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- New accounts can't log in until their email address is verified. Accounts
+-- created before verification existed are treated as verified.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+UPDATE users SET email_verified_at = created_at;
+
+-- Single-use links sent by email for verifying the address and resetting
+-- the password. Only a hash of the token is stored.
+CREATE TABLE email_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_email_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_email_token_hash UNIQUE(token_hash)
+);
+
+CREATE INDEX idx_email_tokens_user_purpose ON email_tokens(user_id, purpose) WHERE used_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
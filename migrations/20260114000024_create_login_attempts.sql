@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Audit log of logins. user_id is empty for unknown emails; the record
+-- outlives the account.
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID,
+    email VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    outcome VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_login_attempts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_login_attempts_user ON login_attempts(user_id, created_at DESC);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip_address, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_attempts;
+-- +goose StatementEnd
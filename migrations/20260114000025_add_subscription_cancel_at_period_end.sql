@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Unfollowing a creator while a paid period is running only flags the
+-- subscription; the renewal job removes it once the period ends.
+ALTER TABLE subscriptions ADD COLUMN cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS cancel_at_period_end;
+-- +goose StatementEnd
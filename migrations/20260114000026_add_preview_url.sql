@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Blurred copy made by the media worker; the only image shown for paid posts
+-- the viewer hasn't unlocked. The post keeps the preview of its first image.
+ALTER TABLE post_images ADD COLUMN preview_url VARCHAR(500);
+ALTER TABLE posts ADD COLUMN preview_url VARCHAR(500);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE posts DROP COLUMN IF EXISTS preview_url;
+ALTER TABLE post_images DROP COLUMN IF EXISTS preview_url;
+-- +goose StatementEnd
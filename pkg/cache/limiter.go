@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter allows up to limit events per key in fixed windows, e.g. emails
+// sent to one address per hour.
+type Limiter struct {
+	redisClient *redis.Client
+	prefix      string
+	limit       int
+	window      time.Duration
+}
+
+func NewLimiter(redisClient *redis.Client, prefix string, limit int, window time.Duration) *Limiter {
+	return &Limiter{redisClient: redisClient, prefix: prefix, limit: limit, window: window}
+}
+
+// Allow counts one event for key and reports whether it is within the limit.
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, error) {
+	redisKey := fmt.Sprintf("%s:%s", l.prefix, key)
+	pipe := l.redisClient.TxPipeline()
+	count := pipe.Incr(ctx, redisKey)
+	// Only the first event of a window sets the expiry
+	pipe.ExpireNX(ctx, redisKey, l.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return count.Val() <= int64(l.limit), nil
+}
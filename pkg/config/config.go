@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -30,8 +31,32 @@ type Config struct {
 	RabbitMQUser     string
 	RabbitMQPassword string
 
-	// JWT
-	JWTSecret string
+	// JWT. The auth service signs tokens with the key JWTKeyID from
+	// JWTKeysDir; the other services verify them with the keys it publishes
+	// at JWKSURL.
+	JWTKeysDir string
+	JWTKeyID   string
+	JWKSURL    string
+
+	// Service-to-service auth: the callers trusted by this service, as
+	// "name:key,..." pairs of service names and shared HMAC keys.
+	ServiceKeys string
+
+	// TrustedProxies lists the proxies (IPs or CIDRs, comma separated) whose
+	// X-Forwarded-For is believed when working out the client's address.
+	// Empty trusts none: the address is the one the connection came from.
+	TrustedProxies string
+
+	// Mail. MailDriver is smtp, file (writes .eml files to MailDir) or
+	// memory. AppURL is the frontend the links in emails point to.
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	AppURL       string
 
 	// AWS S3
 	AWSRegion          string
@@ -42,6 +67,9 @@ type Config struct {
 	S3UseSSL           string
 	S3PublicURL        string
 
+	// Wallet
+	PlatformFeePercent int
+
 	// Services URLs
 	AuthServiceURL        string
 	PostServiceURL        string
@@ -51,11 +79,14 @@ type Config struct {
 	NotificationServiceURL string
 	ModerationServiceURL  string
 	AnalyticsServiceURL   string
+	SearchServiceURL      string
 }
 
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
+	authServiceURL := getEnv("AUTH_SERVICE_URL", "http://localhost:8001")
+
 	config := &Config{
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 
@@ -76,7 +107,22 @@ func Load() (*Config, error) {
 		RabbitMQUser:     getEnv("RABBITMQ_USER", "guest"),
 		RabbitMQPassword: getEnv("RABBITMQ_PASSWORD", "guest"),
 
-		JWTSecret: getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+		JWTKeysDir: getEnv("JWT_KEYS_DIR", "keys/jwt"),
+		JWTKeyID:   getEnv("JWT_KEY_ID", ""),
+		JWKSURL:    getEnv("JWKS_URL", authServiceURL+"/.well-known/jwks.json"),
+
+		ServiceKeys: getEnv("SERVICE_KEYS", ""),
+
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "Lick Scroll <no-reply@lickscroll.local>"),
+		MailDir:      getEnv("MAIL_DIR", "tmp/mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		AppURL:       getEnv("APP_URL", "http://localhost:3000"),
 
 		AWSRegion:          getEnv("AWS_REGION", "us-east-1"),
 		AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
@@ -86,14 +132,17 @@ func Load() (*Config, error) {
 		S3UseSSL:           getEnv("S3_USE_SSL", "true"),
 		S3PublicURL:        getEnv("S3_PUBLIC_URL", "http://localhost:9000"),
 
-		AuthServiceURL:        getEnv("AUTH_SERVICE_URL", "http://localhost:8001"),
+		PlatformFeePercent: getEnvInt("PLATFORM_FEE_PERCENT", 0),
+
+		AuthServiceURL:        authServiceURL,
 		PostServiceURL:        getEnv("POST_SERVICE_URL", "http://localhost:8002"),
 		FeedServiceURL:        getEnv("FEED_SERVICE_URL", "http://localhost:8003"),
 		FanoutServiceURL:      getEnv("FANOUT_SERVICE_URL", "http://localhost:8004"),
 		WalletServiceURL:      getEnv("WALLET_SERVICE_URL", "http://localhost:8005"),
 		NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8006"),
-		ModerationServiceURL:   getEnv("MODERATION_SERVICE_URL", "http://localhost:8007"),
+		ModerationServiceURL:   getEnv("MODERATION_SERVICE_URL", "http://localhost:8009"),
 		AnalyticsServiceURL:    getEnv("ANALYTICS_SERVICE_URL", "http://localhost:8008"),
+		SearchServiceURL:       getEnv("SEARCH_SERVICE_URL", "http://localhost:8010"),
 	}
 
 	return config, nil
@@ -106,3 +155,10 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
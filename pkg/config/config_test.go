@@ -17,7 +17,8 @@ func TestLoadConfig(t *testing.T) {
 	os.Setenv("DB_NAME", "testdb")
 	os.Setenv("REDIS_HOST", "localhost")
 	os.Setenv("REDIS_PORT", "6379")
-	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("JWT_KEY_ID", "test-key")
+	os.Setenv("AUTH_SERVICE_URL", "http://auth:8001")
 
 	// Load config
 	cfg, err := Load()
@@ -35,7 +36,8 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "testdb", cfg.DBName)
 	assert.Equal(t, "localhost", cfg.RedisHost)
 	assert.Equal(t, "6379", cfg.RedisPort)
-	assert.Equal(t, "test-secret", cfg.JWTSecret)
+	assert.Equal(t, "test-key", cfg.JWTKeyID)
+	assert.Equal(t, "http://auth:8001/.well-known/jwks.json", cfg.JWKSURL)
 
 	// Cleanup
 	os.Unsetenv("SERVER_PORT")
@@ -46,7 +48,8 @@ func TestLoadConfig(t *testing.T) {
 	os.Unsetenv("DB_NAME")
 	os.Unsetenv("REDIS_HOST")
 	os.Unsetenv("REDIS_PORT")
-	os.Unsetenv("JWT_SECRET")
+	os.Unsetenv("JWT_KEY_ID")
+	os.Unsetenv("AUTH_SERVICE_URL")
 }
 
 func TestLoadConfig_Defaults(t *testing.T) {
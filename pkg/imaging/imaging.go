@@ -0,0 +1,128 @@
+// Package imaging decodes uploaded images and produces resized copies. Images
+// are always re-encoded, which drops EXIF, XMP and any other metadata the
+// original carried; EXIF orientation is applied to the pixels first so
+// photos keep facing the right way.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register the WebP decoder
+)
+
+// MaxPixels bounds the size of images that are decoded, so a small file
+// claiming huge dimensions can't exhaust memory.
+const MaxPixels = 50_000_000
+
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Decode decodes a JPEG, PNG, GIF or WebP image and returns it upright along
+// with its format name. Only the first frame of an animated GIF is decoded.
+func Decode(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// Fit scales the image down to fit in a maxSize square, keeping its aspect
+// ratio. Images that already fit are returned unchanged.
+func Fit(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := fitSize(bounds.Dx(), bounds.Dy(), maxSize)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return img
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// fitSize scales width and height down to fit in a maxSize square.
+func fitSize(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+// blurSize is the longest side, in pixels, an image is reduced to before
+// Blur scales it back up. Nothing smaller than about 1/20 of the picture
+// survives it.
+const blurSize = 16
+
+// Blur returns a heavily blurred copy of the image that fits in a maxSize
+// square. The image is shrunk to a few pixels and smoothly enlarged again,
+// so the copy shows colours and rough shapes but no detail, and the
+// original can't be recovered from it.
+func Blur(img image.Image, maxSize int) image.Image {
+	width, height := fitSize(img.Bounds().Dx(), img.Bounds().Dy(), maxSize)
+	small := Fit(img, blurSize)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.BiLinear.Scale(dst, dst.Bounds(), small, small.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// OutputFormat is the format images decoded from the given format are
+// stored in: PNG and GIF keep their transparency as PNG, everything else
+// becomes JPEG.
+func OutputFormat(format string) string {
+	if format == "png" || format == "gif" {
+		return "png"
+	}
+	return "jpeg"
+}
+
+// Encode writes the image in the given output format without any metadata.
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case "png":
+		return png.Encode(w, img)
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// ContentType returns the MIME type of an output format.
+func ContentType(format string) string {
+	if format == "png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// Extension returns the file extension of an output format.
+func Extension(format string) string {
+	if format == "png" {
+		return ".png"
+	}
+	return ".jpg"
+}
@@ -0,0 +1,130 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withExif inserts an APP1 EXIF segment with the given orientation and a
+// marker string standing in for GPS data right after the JPEG SOI marker.
+func withExif(t *testing.T, jpegData []byte, orientation uint16) []byte {
+	t.Helper()
+
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("II")
+	binary.Write(tiff, binary.LittleEndian, uint16(42))
+	binary.Write(tiff, binary.LittleEndian, uint32(8))
+	binary.Write(tiff, binary.LittleEndian, uint16(1))      // one IFD entry
+	binary.Write(tiff, binary.LittleEndian, uint16(0x0112)) // orientation
+	binary.Write(tiff, binary.LittleEndian, uint16(3))      // SHORT
+	binary.Write(tiff, binary.LittleEndian, uint32(1))
+	binary.Write(tiff, binary.LittleEndian, uint32(orientation))
+	binary.Write(tiff, binary.LittleEndian, uint32(0)) // no next IFD
+	tiff.WriteString("GPSLatitude 55.7558")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	header := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, header...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+func encodeTestJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, jpeg.Encode(buf, img, nil))
+	return buf.Bytes()
+}
+
+func TestDecode_AppliesOrientationAndStripsExif(t *testing.T) {
+	data := withExif(t, encodeTestJPEG(t, 40, 20), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+
+	img, format, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds(), "rotated to portrait")
+
+	out := new(bytes.Buffer)
+	require.NoError(t, Encode(out, img, OutputFormat(format), 85))
+	assert.NotContains(t, out.String(), "Exif")
+	assert.NotContains(t, out.String(), "GPSLatitude")
+	assert.Equal(t, 1, jpegOrientation(out.Bytes()))
+}
+
+func TestDecode_RejectsGarbage(t *testing.T) {
+	_, _, err := Decode([]byte("not an image"))
+	assert.Error(t, err)
+}
+
+func TestOrient(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	// Turning clockwise puts the left pixel on top
+	rotated := orient(img, 6)
+	assert.Equal(t, image.Rect(0, 0, 1, 2), rotated.Bounds())
+	assert.Equal(t, red, rotated.At(0, 0))
+	assert.Equal(t, blue, rotated.At(0, 1))
+
+	rotated = orient(img, 8)
+	assert.Equal(t, blue, rotated.At(0, 0))
+	assert.Equal(t, red, rotated.At(0, 1))
+
+	mirrored := orient(img, 2)
+	assert.Equal(t, blue, mirrored.At(0, 0))
+	assert.Equal(t, red, mirrored.At(1, 0))
+
+	assert.Same(t, img, orient(img, 1))
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+
+	assert.Equal(t, image.Rect(0, 0, 320, 160), Fit(img, 320).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 100, 320), Fit(image.NewNRGBA(image.Rect(0, 0, 500, 1600)), 320).Bounds())
+	assert.Same(t, img, Fit(img, 2000), "smaller images are not upscaled")
+}
+
+func TestBlur(t *testing.T) {
+	// A one-pixel checkerboard is as much detail as an image can carry
+	img := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+	for y := 0; y < 500; y++ {
+		for x := 0; x < 1000; x++ {
+			if (x+y)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	blurred := Blur(img, 320)
+
+	assert.Equal(t, image.Rect(0, 0, 320, 160), blurred.Bounds())
+	// Neighbouring pixels come out as the same mid grey
+	r1, _, _, _ := blurred.At(100, 80).RGBA()
+	r2, _, _, _ := blurred.At(101, 80).RGBA()
+	assert.InDelta(t, r1, r2, 0x0200)
+	assert.InDelta(t, 0x7fff, r1, 0x1000)
+}
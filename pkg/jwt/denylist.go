@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist holds revoked sessions. Access tokens can't be recalled once
+// issued, so a revoked session is kept here until the last access token
+// issued for it has expired.
+type Denylist struct {
+	redisClient *redis.Client
+}
+
+func NewDenylist(redisClient *redis.Client) *Denylist {
+	return &Denylist{redisClient: redisClient}
+}
+
+func denylistKey(sessionID string) string {
+	return fmt.Sprintf("auth:revoked_session:%s", sessionID)
+}
+
+// Revoke rejects the access tokens of the session from now on.
+func (d *Denylist) Revoke(ctx context.Context, sessionID string) error {
+	return d.redisClient.Set(ctx, denylistKey(sessionID), 1, AccessTokenTTL).Err()
+}
+
+func (d *Denylist) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	count, err := d.redisClient.Exists(ctx, denylistKey(sessionID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,189 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksCacheTTL is how long fetched keys are trusted before the set is
+	// fetched again, so keys removed from the auth service stop verifying.
+	jwksCacheTTL = time.Hour
+	// jwksRefetchInterval throttles fetches triggered by an unknown kid, so
+	// forged tokens can't make every request hit the auth service.
+	jwksRefetchInterval = 30 * time.Second
+	jwksFetchTimeout    = 5 * time.Second
+)
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewJWK(kid string, publicKey crypto.PublicKey) (JWK, error) {
+	method, err := signingMethod(publicKey)
+	if err != nil {
+		return JWK{}, err
+	}
+
+	jwk := JWK{Kid: kid, Use: "sig", Alg: method.Alg()}
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	}
+	return jwk, nil
+}
+
+// Key decodes the public key.
+func (j JWK) Key() (crypto.PublicKey, error) {
+	var publicKey crypto.PublicKey
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid modulus: %w", j.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid exponent: %w", j.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %s: exponent too large", j.Kid)
+		}
+		publicKey = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("key %s: %w curve %s", j.Kid, ErrUnsupportedKey, j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s: invalid Ed25519 key", j.Kid)
+		}
+		publicKey = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("key %s: %w %s", j.Kid, ErrUnsupportedKey, j.Kty)
+	}
+
+	if _, err := signingMethod(publicKey); err != nil {
+		return nil, fmt.Errorf("key %s: %w", j.Kid, err)
+	}
+	return publicKey, nil
+}
+
+// RemoteKeySet verifies tokens with the keys the auth service publishes. Keys
+// are fetched on first use and cached; a token with an unknown kid triggers a
+// refetch so a rotated key is picked up without a restart.
+type RemoteKeySet struct {
+	url        string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:        url,
+		httpClient: &http.Client{Timeout: jwksFetchTimeout},
+		now:        time.Now,
+	}
+}
+
+func (r *RemoteKeySet) PublicKey(kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	key, known := r.keys[kid]
+	stale := now.Sub(r.fetchedAt) > jwksCacheTTL
+	if (known && !stale) || now.Sub(r.lastAttempt) < jwksRefetchInterval {
+		return r.cached(key, known)
+	}
+
+	r.lastAttempt = now
+	keys, err := r.fetch()
+	if err != nil {
+		// Keep verifying with the keys we have while the auth service is down
+		if r.keys == nil {
+			return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+		}
+		return r.cached(key, known)
+	}
+
+	r.keys = keys
+	r.fetchedAt = now
+	key, known = r.keys[kid]
+	return r.cached(key, known)
+}
+
+func (r *RemoteKeySet) cached(key crypto.PublicKey, known bool) (crypto.PublicKey, error) {
+	if !known {
+		if r.keys == nil {
+			return nil, ErrKeysUnavailable
+		}
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (r *RemoteKeySet) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := r.httpClient.Get(r.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %d", r.url, resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", r.url, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.Key()
+		if err != nil {
+			return nil, err
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeyDir(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "current.pem"), privatePEM, 0o600))
+
+	edKey, err := NewEd25519Key("previous")
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(edKey.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "previous.pem"), publicPEM, 0o644))
+
+	keys, err := LoadKeyDir(dir)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "current", keys[0].ID)
+	assert.True(t, keys[0].CanSign())
+	assert.Equal(t, "previous", keys[1].ID)
+	assert.False(t, keys[1].CanSign())
+
+	// The RSA key signs RS256 tokens
+	service, err := NewIssuer(keys[0], NewStaticKeySet(keys...))
+	require.NoError(t, err)
+	token, err := service.GenerateToken("user-123", "viewer", "session-1", false)
+	require.NoError(t, err)
+	_, err = service.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestParseKey_RejectsWeakRSA(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	_, err = ParseKey("weak", data)
+	assert.Error(t, err)
+}
+
+func TestJWKS_RoundTrip(t *testing.T) {
+	edKey, err := NewEd25519Key("ed")
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	set, err := NewStaticKeySet(edKey, &Key{ID: "rsa", PublicKey: &rsaKey.PublicKey}).JWKS()
+	require.NoError(t, err)
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "EdDSA", set.Keys[0].Alg)
+	assert.Equal(t, "RS256", set.Keys[1].Alg)
+
+	for i, want := range []interface{}{edKey.PublicKey, &rsaKey.PublicKey} {
+		got, err := set.Keys[i].Key()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+// jwksServer serves the public halves of keys and counts requests.
+func jwksServer(t *testing.T, keys ...*Key) (*httptest.Server, *atomic.Int32, func(...*Key)) {
+	t.Helper()
+	var requests atomic.Int32
+	var current atomic.Value
+	setKeys := func(keys ...*Key) {
+		set, err := NewStaticKeySet(keys...).JWKS()
+		require.NoError(t, err)
+		current.Store(set)
+	}
+	setKeys(keys...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(current.Load())
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, setKeys
+}
+
+func TestRemoteKeySet_CachesAndPicksUpRotatedKeys(t *testing.T) {
+	oldKey, err := NewEd25519Key("old")
+	require.NoError(t, err)
+	newKey, err := NewEd25519Key("new")
+	require.NoError(t, err)
+
+	server, requests, setKeys := jwksServer(t, oldKey)
+	now := time.Now()
+	remote := NewRemoteKeySet(server.URL)
+	remote.now = func() time.Time { return now }
+
+	oldIssuer, err := NewIssuer(oldKey, NewStaticKeySet(oldKey))
+	require.NoError(t, err)
+	newIssuer, err := NewIssuer(newKey, NewStaticKeySet(newKey))
+	require.NoError(t, err)
+	verifier := NewVerifier(remote)
+
+	oldToken, _ := oldIssuer.GenerateToken("user-123", "viewer", "session-1", false)
+	for i := 0; i < 3; i++ {
+		_, err = verifier.ValidateToken(oldToken)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+
+	// The auth service rotates; an unknown kid triggers a refetch
+	setKeys(newKey, oldKey)
+	now = now.Add(jwksRefetchInterval)
+	newToken, _ := newIssuer.GenerateToken("user-123", "viewer", "session-2", false)
+	_, err = verifier.ValidateToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Unknown kids right after a fetch don't hit the auth service again
+	strayKey, err := NewEd25519Key("stray")
+	require.NoError(t, err)
+	strayIssuer, err := NewIssuer(strayKey, NewStaticKeySet(strayKey))
+	require.NoError(t, err)
+	strayToken, _ := strayIssuer.GenerateToken("user-123", "viewer", "session-3", false)
+	_, err = verifier.ValidateToken(strayToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Keys dropped by the auth service stop verifying once the cache expires
+	setKeys(newKey)
+	now = now.Add(jwksCacheTTL + time.Second)
+	_, err = verifier.ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestRemoteKeySet_AuthServiceDown(t *testing.T) {
+	key, err := NewEd25519Key("key")
+	require.NoError(t, err)
+	issuer, err := NewIssuer(key, NewStaticKeySet(key))
+	require.NoError(t, err)
+	token, _ := issuer.GenerateToken("user-123", "viewer", "session-1", false)
+
+	server, _, _ := jwksServer(t, key)
+	now := time.Now()
+	remote := NewRemoteKeySet(server.URL)
+	remote.now = func() time.Time { return now }
+	verifier := NewVerifier(remote)
+
+	_, err = verifier.ValidateToken(token)
+	require.NoError(t, err)
+
+	// Cached keys keep working after the cache expires if the refetch fails
+	server.Close()
+	now = now.Add(jwksCacheTTL + time.Second)
+	_, err = verifier.ValidateToken(token)
+	assert.NoError(t, err)
+
+	// Without any cached keys the failure is reported as such
+	unreachable := NewRemoteKeySet(server.URL)
+	_, err = NewVerifier(unreachable).ValidateToken(token)
+	assert.ErrorIs(t, err, ErrKeysUnavailable)
+}
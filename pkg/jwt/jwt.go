@@ -2,57 +2,96 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is how long access tokens are valid. They are renewed with
+// refresh tokens, which the auth service keeps server-side.
+const AccessTokenTTL = 15 * time.Minute
+
 type Claims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	// SessionID identifies the login the token was issued for. Revoking the
+	// session invalidates every access token issued for it.
+	SessionID string `json:"sid"`
+	// MFA is set when the session was opened with a second factor.
+	MFA bool `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// validMethods are the algorithms tokens may be signed with. Listing them
+// keeps a token from choosing HS256 and using a public key as its secret.
+var validMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
 type Service struct {
-	secretKey []byte
+	signingKey *Key
+	method     jwt.SigningMethod
+	keys       KeySet
 }
 
-func NewService(secretKey string) *Service {
-	return &Service{
-		secretKey: []byte(secretKey),
+// NewIssuer returns a service that signs tokens with signingKey and verifies
+// tokens signed by any key in keys. Only the auth service issues tokens.
+func NewIssuer(signingKey *Key, keys KeySet) (*Service, error) {
+	if !signingKey.CanSign() {
+		return nil, fmt.Errorf("key %s: private key required for signing", signingKey.ID)
+	}
+	method, err := signingMethod(signingKey.PublicKey)
+	if err != nil {
+		return nil, err
 	}
+	return &Service{signingKey: signingKey, method: method, keys: keys}, nil
 }
 
-func (s *Service) GenerateToken(userID, role string) (string, error) {
+// NewVerifier returns a service that only validates tokens.
+func NewVerifier(keys KeySet) *Service {
+	return &Service{keys: keys}
+}
+
+func (s *Service) GenerateToken(userID, role, sessionID string, mfa bool) (string, error) {
+	if s.signingKey == nil {
+		return "", ErrVerificationOnly
+	}
+
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		MFA:       mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
+	token := jwt.NewWithClaims(s.method, claims)
+	token.Header["kid"] = s.signingKey.ID
+	return token.SignedString(s.signingKey.privateKey)
 }
 
 func (s *Service) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no key id")
 		}
-		return s.secretKey, nil
-	})
+		return s.keys.PublicKey(kid)
+	}, jwt.WithValidMethods(validMethods))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Tokens without a session predate revocation and can't be revoked
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.SessionID != "" {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
-
@@ -4,23 +4,39 @@ import (
 	"testing"
 	"time"
 
+	gojwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestNewService(t *testing.T) {
-	secretKey := "test-secret-key"
-	service := NewService(secretKey)
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	key, err := NewEd25519Key("test-key")
+	require.NoError(t, err)
+	service, err := NewIssuer(key, NewStaticKeySet(key))
+	require.NoError(t, err)
+	return service
+}
+
+func TestNewIssuer(t *testing.T) {
+	key, err := NewEd25519Key("test-key")
+	require.NoError(t, err)
 
+	service, err := NewIssuer(key, NewStaticKeySet(key))
+	assert.NoError(t, err)
 	assert.NotNil(t, service)
-	assert.Equal(t, []byte(secretKey), service.secretKey)
+
+	// A public key alone can't sign
+	_, err = NewIssuer(&Key{ID: "public-only", PublicKey: key.PublicKey}, NewStaticKeySet(key))
+	assert.Error(t, err)
 }
 
 func TestGenerateToken(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 	userID := "user-123"
 	role := "viewer"
 
-	token, err := service.GenerateToken(userID, role)
+	token, err := service.GenerateToken(userID, role, "session-1", false)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -28,12 +44,12 @@ func TestGenerateToken(t *testing.T) {
 }
 
 func TestValidateToken(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 	userID := "user-123"
 	role := "viewer"
 
 	// Generate token
-	token, err := service.GenerateToken(userID, role)
+	token, err := service.GenerateToken(userID, role, "session-1", false)
 	assert.NoError(t, err)
 
 	// Validate token
@@ -44,26 +60,37 @@ func TestValidateToken(t *testing.T) {
 	assert.Equal(t, role, claims.Role)
 }
 
+func TestValidateToken_MFA(t *testing.T) {
+	service := newTestService(t)
+
+	token, err := service.GenerateToken("user-123", "creator", "session-1", true)
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.True(t, claims.MFA)
+}
+
 func TestValidateToken_InvalidToken(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 
 	// Invalid token format
 	_, err := service.ValidateToken("invalid-token")
 	assert.Error(t, err)
 }
 
-func TestValidateToken_WrongSecret(t *testing.T) {
-	service1 := NewService("secret-key-1")
-	service2 := NewService("secret-key-2")
+func TestValidateToken_WrongKey(t *testing.T) {
+	service1 := newTestService(t)
+	service2 := newTestService(t)
 
 	userID := "user-123"
 	role := "viewer"
 
 	// Generate token with service1
-	token, err := service1.GenerateToken(userID, role)
+	token, err := service1.GenerateToken(userID, role, "session-1", false)
 	assert.NoError(t, err)
 
-	// Try to validate with service2 (wrong secret)
+	// Try to validate with service2 (same kid, different key)
 	_, err = service2.ValidateToken(token)
 	assert.Error(t, err)
 }
@@ -71,11 +98,11 @@ func TestValidateToken_WrongSecret(t *testing.T) {
 func TestValidateToken_ExpiredToken(t *testing.T) {
 	// Note: This test would require mocking time or using a very short expiration
 	// For now, we test that token has expiration set
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 	userID := "user-123"
 	role := "viewer"
 
-	token, err := service.GenerateToken(userID, role)
+	token, err := service.GenerateToken(userID, role, "session-1", false)
 	assert.NoError(t, err)
 
 	claims, err := service.ValidateToken(token)
@@ -85,19 +112,19 @@ func TestValidateToken_ExpiredToken(t *testing.T) {
 }
 
 func TestValidateToken_EmptyToken(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 
 	_, err := service.ValidateToken("")
 	assert.Error(t, err)
 }
 
 func TestGenerateAndValidateToken_RoundTrip(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 	userID := "user-456"
 	role := "creator"
 
 	// Generate
-	token, err := service.GenerateToken(userID, role)
+	token, err := service.GenerateToken(userID, role, "session-1", false)
 	assert.NoError(t, err)
 
 	// Validate
@@ -108,25 +135,23 @@ func TestGenerateAndValidateToken_RoundTrip(t *testing.T) {
 }
 
 func TestValidateToken_UnexpectedSigningMethod(t *testing.T) {
-	// This test checks the error path for unexpected signing method
-	// We'll create a token with wrong algorithm manually
-	service := NewService("test-secret-key")
-	
-	// Generate a valid token first
-	token, err := service.GenerateToken("user-123", "viewer")
-	assert.NoError(t, err)
-	
-	// Validate it should work
-	claims, err := service.ValidateToken(token)
-	assert.NoError(t, err)
-	assert.NotNil(t, claims)
+	service := newTestService(t)
+
+	// A shared-secret token naming a known kid must not verify
+	token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, &Claims{UserID: "user-123", SessionID: "session-1"})
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString([]byte("any-secret"))
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(signed)
+	assert.Error(t, err)
 }
 
 func TestGenerateToken_EmptyValues(t *testing.T) {
-	service := NewService("test-secret-key")
+	service := newTestService(t)
 	
 	// Generate with empty values should still work
-	token, err := service.GenerateToken("", "")
+	token, err := service.GenerateToken("", "", "session-1", false)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 	
@@ -136,3 +161,69 @@ func TestGenerateToken_EmptyValues(t *testing.T) {
 	assert.Equal(t, "", claims.UserID)
 	assert.Equal(t, "", claims.Role)
 }
+
+func TestGenerateToken_SessionAndLifetime(t *testing.T) {
+	service := newTestService(t)
+
+	token, err := service.GenerateToken("user-123", "viewer", "session-1", false)
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(AccessTokenTTL), claims.ExpiresAt.Time, 5*time.Second)
+}
+
+func TestValidateToken_RejectsTokensWithoutSession(t *testing.T) {
+	service := newTestService(t)
+
+	// Tokens issued before sessions existed can't be revoked
+	token, err := service.GenerateToken("user-123", "viewer", "", false)
+	assert.NoError(t, err)
+
+	_, err = service.ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestGenerateToken_SetsKeyID(t *testing.T) {
+	service := newTestService(t)
+
+	token, err := service.GenerateToken("user-123", "viewer", "session-1", false)
+	require.NoError(t, err)
+
+	parsed, _, err := gojwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "test-key", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+}
+
+func TestValidateToken_KeyRotation(t *testing.T) {
+	oldKey, err := NewEd25519Key("2026-01")
+	require.NoError(t, err)
+	newKey, err := NewEd25519Key("2026-02")
+	require.NoError(t, err)
+
+	oldIssuer, err := NewIssuer(oldKey, NewStaticKeySet(oldKey))
+	require.NoError(t, err)
+	oldToken, err := oldIssuer.GenerateToken("user-123", "viewer", "session-1", false)
+	require.NoError(t, err)
+
+	// After rotation tokens from the previous key verify until they expire
+	verifier := NewVerifier(NewStaticKeySet(newKey, &Key{ID: oldKey.ID, PublicKey: oldKey.PublicKey}))
+	claims, err := verifier.ValidateToken(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims.UserID)
+
+	// Once the previous key is dropped they no longer do
+	_, err = NewVerifier(NewStaticKeySet(newKey)).ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestVerifier_CannotSign(t *testing.T) {
+	key, err := NewEd25519Key("test-key")
+	require.NoError(t, err)
+
+	_, err = NewVerifier(NewStaticKeySet(key)).GenerateToken("user-123", "viewer", "session-1", false)
+	assert.ErrorIs(t, err, ErrVerificationOnly)
+}
@@ -0,0 +1,173 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for RS256.
+const minRSAKeyBits = 2048
+
+var (
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrUnsupportedKey   = errors.New("unsupported key type")
+	ErrKeysUnavailable  = errors.New("verification keys unavailable")
+	ErrVerificationOnly = errors.New("service can only verify tokens")
+)
+
+// Key is an asymmetric key identified by the kid header of the tokens it
+// signs. Keys loaded from a public key can only verify tokens.
+type Key struct {
+	ID         string
+	PublicKey  crypto.PublicKey
+	privateKey crypto.Signer
+}
+
+// CanSign reports whether the private half of the key is available.
+func (k *Key) CanSign() bool {
+	return k.privateKey != nil
+}
+
+// NewEd25519Key generates a fresh EdDSA signing key.
+func NewEd25519Key(id string) (*Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, PublicKey: public, privateKey: private}, nil
+}
+
+// ParseKey reads a PEM encoded RSA or Ed25519 key. Private keys may be PKCS#8
+// or PKCS#1, public keys PKIX.
+func ParseKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unexpected PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.PublicKey, key.privateKey = &k.PublicKey, k
+	case ed25519.PrivateKey:
+		key.PublicKey, key.privateKey = k.Public(), k
+	case *rsa.PublicKey, ed25519.PublicKey:
+		key.PublicKey = k
+	default:
+		return nil, fmt.Errorf("key %s: %w %T", id, ErrUnsupportedKey, parsed)
+	}
+
+	if _, err := signingMethod(key.PublicKey); err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+	return key, nil
+}
+
+// LoadKeyDir loads every *.pem file in dir, using the file name without the
+// extension as the key ID. Keeping the previous key's file around after a
+// rotation lets tokens it signed verify until they expire.
+func LoadKeyDir(dir string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys found in %s", dir)
+	}
+	return keys, nil
+}
+
+// signingMethod picks the JWT algorithm for a public key.
+func signingMethod(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("%w %T", ErrUnsupportedKey, publicKey)
+	}
+}
+
+// KeySet resolves the public key a token was signed with from its kid.
+type KeySet interface {
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// StaticKeySet is a fixed set of keys, used by the auth service that owns
+// them.
+type StaticKeySet struct {
+	keys []*Key
+}
+
+func NewStaticKeySet(keys ...*Key) *StaticKeySet {
+	return &StaticKeySet{keys: keys}
+}
+
+func (s *StaticKeySet) PublicKey(kid string) (crypto.PublicKey, error) {
+	for _, key := range s.keys {
+		if key.ID == kid {
+			return key.PublicKey, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWKS returns the public halves of the keys for publishing.
+func (s *StaticKeySet) JWKS() (*JWKS, error) {
+	set := &JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwk, err := NewJWK(key.ID, key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
@@ -0,0 +1,137 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFrom = &mail.Address{Name: "Lick Scroll", Address: "no-reply@lickscroll.local"}
+
+var testMessage = Message{
+	To:      "user@example.com",
+	Subject: "Подтвердите email",
+	Body:    "Откройте ссылку:\nhttp://localhost:3000/verify-email?token=abc",
+}
+
+// readBody parses a rendered message and decodes its body.
+func readBody(t *testing.T, data []byte) (*mail.Message, string) {
+	t.Helper()
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	body, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	return parsed, string(body)
+}
+
+func TestFormat(t *testing.T) {
+	data, err := format(testFrom, testMessage, time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, body := readBody(t, data)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, testMessage.Subject, subject)
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+	assert.Equal(t, "quoted-printable", parsed.Header.Get("Content-Transfer-Encoding"))
+	// net/mail leaves the body encoded; the token must survive the encoding
+	assert.Contains(t, body, "token=3Dabc")
+
+	_, err = format(testFrom, Message{To: "not an address"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	require.NoError(t, NewFileMailer(dir, testFrom).Send(context.Background(), testMessage))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-user@example.com.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	parsed, _ := readBody(t, data)
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	require.NoError(t, m.Send(context.Background(), testMessage))
+	assert.Equal(t, []Message{testMessage}, m.Messages())
+}
+
+// fakeSMTPServer accepts one message and returns its envelope and data.
+func fakeSMTPServer(t *testing.T) (string, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		var transcript []string
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			transcript = append(transcript, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case line == "DATA":
+				reply("354 go ahead")
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					transcript = append(transcript, strings.TrimRight(dataLine, "\r\n"))
+				}
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				received <- transcript
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPMailer(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	err = NewSMTPMailer(host, port, "", "", testFrom).Send(context.Background(), testMessage)
+	require.NoError(t, err)
+
+	transcript := strings.Join(<-received, "\n")
+	assert.Contains(t, transcript, "MAIL FROM:<no-reply@lickscroll.local>")
+	assert.Contains(t, transcript, "RCPT TO:<user@example.com>")
+	assert.Contains(t, transcript, "token=3Dabc")
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -9,7 +11,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtService *jwt.Service) gin.HandlerFunc {
+// SessionChecker reports whether a session was revoked; *jwt.Denylist
+// implements it.
+type SessionChecker interface {
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+func AuthMiddleware(jwtService *jwt.Service, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -27,15 +35,34 @@ func AuthMiddleware(jwtService *jwt.Service) gin.HandlerFunc {
 
 		token := parts[1]
 		claims, err := jwtService.ValidateToken(token)
+		if errors.Is(err, jwt.ErrKeysUnavailable) {
+			// The auth service's keys couldn't be loaded; the token may be fine
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Token verification unavailable"})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
+		revoked, err := sessions.IsRevoked(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Session check failed"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("mfa", claims.MFA)
 		c.Next()
 	}
 }
-
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,19 +10,36 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// fakeSessions treats the listed sessions as revoked.
+type fakeSessions map[string]bool
+
+func (s fakeSessions) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	return s[sessionID], nil
+}
+
+func newTestJWTService(t *testing.T) *jwt.Service {
+	t.Helper()
+	key, err := jwt.NewEd25519Key("test-key")
+	require.NoError(t, err)
+	service, err := jwt.NewIssuer(key, jwt.NewStaticKeySet(key))
+	require.NoError(t, err)
+	return service
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
 }
 
 func TestAuthMiddleware_ValidToken(t *testing.T) {
-	jwtService := jwt.NewService("test-secret-key")
-	token, _ := jwtService.GenerateToken("user-123", "viewer")
+	jwtService := newTestJWTService(t)
+	token, _ := jwtService.GenerateToken("user-123", "viewer", "session-1", false)
 
 	router := setupTestRouter()
-	router.Use(AuthMiddleware(jwtService))
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
 	router.GET("/test", func(c *gin.Context) {
 		userID := c.GetString("user_id")
 		c.JSON(http.StatusOK, gin.H{"user_id": userID})
@@ -37,10 +55,10 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 }
 
 func TestAuthMiddleware_NoHeader(t *testing.T) {
-	jwtService := jwt.NewService("test-secret-key")
+	jwtService := newTestJWTService(t)
 
 	router := setupTestRouter()
-	router.Use(AuthMiddleware(jwtService))
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -54,10 +72,10 @@ func TestAuthMiddleware_NoHeader(t *testing.T) {
 }
 
 func TestAuthMiddleware_InvalidFormat(t *testing.T) {
-	jwtService := jwt.NewService("test-secret-key")
+	jwtService := newTestJWTService(t)
 
 	router := setupTestRouter()
-	router.Use(AuthMiddleware(jwtService))
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -72,10 +90,10 @@ func TestAuthMiddleware_InvalidFormat(t *testing.T) {
 }
 
 func TestAuthMiddleware_InvalidToken(t *testing.T) {
-	jwtService := jwt.NewService("test-secret-key")
+	jwtService := newTestJWTService(t)
 
 	router := setupTestRouter()
-	router.Use(AuthMiddleware(jwtService))
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -92,10 +110,10 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	// This would require mocking time or using a very short expiration
 	// For now, we'll test invalid token which is similar
-	jwtService := jwt.NewService("test-secret-key")
+	jwtService := newTestJWTService(t)
 
 	router := setupTestRouter()
-	router.Use(AuthMiddleware(jwtService))
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
@@ -108,3 +126,49 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	jwtService := newTestJWTService(t)
+	token, _ := jwtService.GenerateToken("user-123", "viewer", "session-1", false)
+	other, _ := jwtService.GenerateToken("user-123", "viewer", "session-2", false)
+
+	router := setupTestRouter()
+	router.Use(AuthMiddleware(jwtService, fakeSessions{"session-1": true}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"session_id": c.GetString("session_id")})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Other sessions of the same user are unaffected
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+other)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "session-2")
+}
+
+func TestAuthMiddleware_KeysUnavailable(t *testing.T) {
+	token, _ := newTestJWTService(t).GenerateToken("user-123", "viewer", "session-1", false)
+
+	// Nothing listens here, so the verification keys can't be fetched
+	verifier := jwt.NewVerifier(jwt.NewRemoteKeySet("http://127.0.0.1:1/.well-known/jwks.json"))
+
+	router := setupTestRouter()
+	router.Use(AuthMiddleware(verifier, fakeSessions{}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMFA lets a request through only if its session was opened with a
+// second factor. It guards withdrawals and moderation, so a stolen password
+// alone can't move money or act as a moderator. It must run after
+// AuthMiddleware, which sets mfa.
+func RequireMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("mfa") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Two-factor authentication required",
+				"code":  "mfa_required",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireMFA(t *testing.T) {
+	jwtService := newTestJWTService(t)
+
+	router := setupTestRouter()
+	router.Use(AuthMiddleware(jwtService, fakeSessions{}))
+	router.POST("/payouts", RequireMFA(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	tests := []struct {
+		name   string
+		mfa    bool
+		status int
+	}{
+		{"session with second factor", true, http.StatusOK},
+		{"password only session", false, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _ := jwtService.GenerateToken("user-123", "creator", "session-1", tt.mfa)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/payouts", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if !tt.mfa {
+				assert.Contains(t, w.Body.String(), "mfa_required")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"lick-scroll/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole lets a request through only if its token carries one of the
+// roles. It must run after AuthMiddleware, which sets user_role.
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := models.UserRole(c.GetString("user_role"))
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+	}
+}
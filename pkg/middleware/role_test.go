@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lick-scroll/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func roleTestRouter(role string, roles ...models.UserRole) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		if role != "" {
+			c.Set("user_role", role)
+		}
+		c.Next()
+	})
+	router.Use(RequireRole(roles...))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		roles  []models.UserRole
+		status int
+	}{
+		{"allowed role", "creator", []models.UserRole{models.RoleCreator}, http.StatusOK},
+		{"one of several roles", "moderator", []models.UserRole{models.RoleCreator, models.RoleModerator}, http.StatusOK},
+		{"other role", "viewer", []models.UserRole{models.RoleCreator}, http.StatusForbidden},
+		{"no role in context", "", []models.UserRole{models.RoleCreator}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			roleTestRouter(tt.role, tt.roles...).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
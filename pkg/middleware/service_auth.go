@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"lick-scroll/pkg/serviceauth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceVerifier authenticates a signed service request and returns the
+// caller's name; *serviceauth.Verifier implements it.
+type ServiceVerifier interface {
+	Verify(req *http.Request) (string, error)
+}
+
+// ServiceAuthMiddleware admits only requests signed by a trusted service and
+// sets service_name for the handler. User tokens are not accepted here.
+func ServiceAuthMiddleware(verifier ServiceVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		service, err := verifier.Verify(c.Request)
+		if errors.Is(err, serviceauth.ErrUnauthenticated) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid service credentials"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Service authentication failed"})
+			c.Abort()
+			return
+		}
+
+		c.Set("service_name", service)
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/serviceauth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNonces accepts every nonce, or fails with err if set.
+type fakeNonces struct {
+	err error
+}
+
+func (n fakeNonces) Claim(ctx context.Context, service, nonce string, ttl time.Duration) (bool, error) {
+	return n.err == nil, n.err
+}
+
+func serviceAuthRouter(nonces serviceauth.NonceStore, key []byte) *gin.Engine {
+	verifier := serviceauth.NewVerifier(map[string][]byte{"wallet": key}, nonces)
+
+	router := setupTestRouter()
+	router.POST("/internal", ServiceAuthMiddleware(verifier), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"service": c.GetString("service_name")})
+	})
+	return router
+}
+
+func TestServiceAuthMiddleware(t *testing.T) {
+	key := []byte(strings.Repeat("k", serviceauth.MinKeyLength))
+	router := serviceAuthRouter(fakeNonces{}, key)
+
+	t.Run("signed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/internal", strings.NewReader("{}"))
+		require.NoError(t, serviceauth.Sign(req, "wallet", key))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"service":"wallet"`)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/internal", strings.NewReader("{}"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("user token", func(t *testing.T) {
+		jwtService := newTestJWTService(t)
+		token, _ := jwtService.GenerateToken("user-123", "moderator", "session-1", false)
+		req := httptest.NewRequest(http.MethodPost, "/internal", strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestServiceAuthMiddleware_NonceStoreDown(t *testing.T) {
+	key := []byte(strings.Repeat("k", serviceauth.MinKeyLength))
+	router := serviceAuthRouter(fakeNonces{err: errors.New("redis down")}, key)
+
+	req := httptest.NewRequest(http.MethodPost, "/internal", strings.NewReader("{}"))
+	require.NoError(t, serviceauth.Sign(req, "wallet", key))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,64 @@
+// Package pagination implements keyset pagination for lists ordered newest
+// first. A cursor points at the last item of a page by its creation time and
+// ID, so pages stay stable while new items are added on top.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque cursor handed to clients as next_cursor.
+func Encode(createdAt time.Time, id string) string {
+	raw := fmt.Sprintf("%d:%s", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor produced by Encode. An empty cursor means the first
+// page and decodes to nil.
+func Decode(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: id}, nil
+}
+
+// Scope orders a query newest first by the given columns and, when cursor is
+// not nil, continues after it. idColumn breaks ties between rows created at
+// the same time.
+func Scope(createdAtColumn, idColumn string, cursor *Cursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Order(createdAtColumn + " DESC").Order(idColumn + " DESC")
+		if cursor == nil {
+			return db
+		}
+		return db.Where(fmt.Sprintf("(%s, %s) < (?, ?)", createdAtColumn, idColumn), cursor.CreatedAt, cursor.ID)
+	}
+}
@@ -0,0 +1,32 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 1, 14, 12, 30, 0, 123456000, time.UTC)
+
+	cursor, err := Decode(Encode(createdAt, "post-1"))
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(cursor.CreatedAt))
+	assert.Equal(t, time.UTC, cursor.CreatedAt.Location())
+	assert.Equal(t, "post-1", cursor.ID)
+}
+
+func TestDecode_EmptyCursorIsFirstPage(t *testing.T) {
+	cursor, err := Decode("")
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+}
+
+func TestDecode_InvalidCursor(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "YWJjOnBvc3Q"} {
+		_, err := Decode(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
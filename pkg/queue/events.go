@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventsExchange carries domain events. Unlike notification tasks, every
+// interested service binds its own queue, so one event can reach many consumers.
+const EventsExchange = "events"
+
+// Routing keys of the events published on EventsExchange.
+const (
+	EventPostCreated         = "post.created"
+	EventPostUpdated         = "post.updated"
+	EventPostDeleted         = "post.deleted"
+	EventSubscriptionCreated = "subscription.created"
+	EventSubscriptionDeleted = "subscription.deleted"
+)
+
+// PublishEvent publishes a domain event under the given routing key.
+func (c *Client) PublishEvent(routingKey string, event map[string]interface{}) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	err = c.channel.Publish(
+		EventsExchange, // exchange
+		routingKey,     // routing key
+		false,          // mandatory
+		false,          // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         eventJSON,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+		},
+	)
+	if err != nil {
+		c.logger.Error("[RABBITMQ] Failed to publish event to exchange=%s, routing_key=%s: %v", EventsExchange, routingKey, err)
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	c.logger.Info("[RABBITMQ] Published event to exchange=%s, routing_key=%s: %s", EventsExchange, routingKey, string(eventJSON))
+	return nil
+}
+
+// ConsumeEvents declares a durable queue bound to the given routing keys and
+// passes every event to handler. Events the handler fails on are requeued.
+func (c *Client) ConsumeEvents(queueName string, routingKeys []string, handler func(routingKey string, event map[string]interface{}) error) error {
+	if _, err := c.channel.QueueDeclare(
+		queueName, // name
+		true,      // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		nil,       // arguments
+	); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	for _, routingKey := range routingKeys {
+		if err := c.channel.QueueBind(queueName, routingKey, EventsExchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue to %s: %w", routingKey, err)
+		}
+	}
+
+	msgs, err := c.channel.Consume(
+		queueName, // queue
+		"",        // consumer
+		false,     // auto-ack
+		false,     // exclusive
+		false,     // no-local
+		false,     // no-wait
+		nil,       // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	c.logger.Info("[RABBITMQ] Started consuming events from queue: %s, routing_keys=%v", queueName, routingKeys)
+
+	go func() {
+		for msg := range msgs {
+			var event map[string]interface{}
+			if err := json.Unmarshal(msg.Body, &event); err != nil {
+				c.logger.Error("[RABBITMQ] Failed to unmarshal event: %v, body=%s", err, string(msg.Body))
+				msg.Nack(false, false) // Reject and don't requeue
+				continue
+			}
+
+			if err := handler(msg.RoutingKey, event); err != nil {
+				c.logger.Error("[RABBITMQ] Handler failed to process event %s: %v, event=%+v", msg.RoutingKey, err, event)
+				msg.Nack(false, true) // Reject and requeue
+				continue
+			}
+
+			msg.Ack(false)
+		}
+	}()
+
+	return nil
+}
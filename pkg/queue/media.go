@@ -0,0 +1,86 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// MediaQueueName is the work queue of the media worker. Tasks are published
+// through the default exchange straight to the queue.
+const MediaQueueName = "media_queue"
+
+// PublishMediaTask queues an uploaded file for processing.
+func (c *Client) PublishMediaTask(task map[string]interface{}) error {
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	err = c.channel.Publish(
+		"",             // exchange
+		MediaQueueName, // routing key
+		false,          // mandatory
+		false,          // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         taskJSON,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+		},
+	)
+	if err != nil {
+		c.logger.Error("[RABBITMQ] Failed to publish media task to queue=%s: %v", MediaQueueName, err)
+		return fmt.Errorf("failed to publish media task: %w", err)
+	}
+
+	c.logger.Info("[RABBITMQ] Published media task to queue=%s: %s", MediaQueueName, string(taskJSON))
+	return nil
+}
+
+// ConsumeMediaTasks passes queued media tasks to handler one at a time.
+// Tasks the handler fails on are requeued.
+func (c *Client) ConsumeMediaTasks(handler func(task map[string]interface{}) error) error {
+	// Decoding images is memory hungry; take one task at a time
+	if err := c.channel.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	msgs, err := c.channel.Consume(
+		MediaQueueName, // queue
+		"",             // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	c.logger.Info("[RABBITMQ] Started consuming from media queue: %s", MediaQueueName)
+
+	go func() {
+		for msg := range msgs {
+			var task map[string]interface{}
+			if err := json.Unmarshal(msg.Body, &task); err != nil {
+				c.logger.Error("[RABBITMQ] Failed to unmarshal media task: %v, body=%s", err, string(msg.Body))
+				msg.Nack(false, false) // Reject and don't requeue
+				continue
+			}
+
+			if err := handler(task); err != nil {
+				c.logger.Error("[RABBITMQ] Handler failed to process media task: %v, task=%+v", err, task)
+				msg.Nack(false, true) // Reject and requeue
+				continue
+			}
+
+			msg.Ack(false)
+		}
+	}()
+
+	return nil
+}
@@ -88,6 +88,37 @@ func NewRabbitMQClient(cfg *config.Config, log *logger.Logger) (*Client, error)
 		return nil, fmt.Errorf("failed to bind queue: %w", err)
 	}
 
+	// Declare the media worker's queue (see media.go)
+	_, err = channel.QueueDeclare(
+		MediaQueueName, // name
+		true,           // durable
+		false,          // delete when unused
+		false,          // exclusive
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare media queue: %w", err)
+	}
+
+	// Declare exchange for domain events (see events.go)
+	err = channel.ExchangeDeclare(
+		EventsExchange, // name
+		"topic",        // type
+		true,           // durable
+		false,          // auto-deleted
+		false,          // internal
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare events exchange: %w", err)
+	}
+
 	log.Info("Connected to RabbitMQ at %s:%s", cfg.RabbitMQHost, cfg.RabbitMQPort)
 
 	return &Client{
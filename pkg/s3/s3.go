@@ -2,21 +2,44 @@ package s3
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"lick-scroll/pkg/config"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+// PublicPrefix is the only part of the bucket anyone can read. Everything
+// else is private and handed out as presigned URLs.
+const PublicPrefix = "avatars/"
+
+// MediaURLTTL is how long presigned media URLs given to clients stay valid.
+const MediaURLTTL = 15 * time.Minute
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object describes a stored object as returned by ListObjects.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
 type Client struct {
 	s3Client *s3.S3
-	bucket   string
+	// presigner signs URLs for clients. With a custom endpoint (MinIO) it
+	// points at the public URL, since the host is part of the signature.
+	presigner *s3.S3
+	bucket    string
 	publicURL string
 }
 
@@ -42,10 +65,17 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	}
 
 	client := &Client{
-		s3Client: s3.New(sess),
-		bucket:   cfg.S3BucketName,
+		s3Client:  s3.New(sess),
+		bucket:    cfg.S3BucketName,
 		publicURL: cfg.S3PublicURL,
 	}
+	client.presigner = client.s3Client
+	if cfg.AWSEndpoint != "" && cfg.S3PublicURL != "" {
+		client.presigner = s3.New(sess, &aws.Config{
+			Endpoint:   aws.String(cfg.S3PublicURL),
+			DisableSSL: aws.Bool(!strings.HasPrefix(cfg.S3PublicURL, "https://")),
+		})
+	}
 
 	if err := client.ensureBucketExists(); err != nil {
 		return nil, fmt.Errorf("failed to ensure bucket exists: %w", err)
@@ -54,27 +84,26 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	return client, nil
 }
 
+// ensureBucketExists creates the bucket as private. The policy is applied
+// on every start so buckets created while everything was public are locked
+// down too.
 func (c *Client) ensureBucketExists() error {
 	_, err := c.s3Client.HeadBucket(&s3.HeadBucketInput{
 		Bucket: aws.String(c.bucket),
 	})
-	if err == nil {
-		return nil
-	}
-
-	_, err = c.s3Client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(c.bucket),
-		ACL:    aws.String("public-read"),
-	})
 	if err != nil {
-		awsErr, ok := err.(interface {
-			Code() string
-			Message() string
+		_, err = c.s3Client.CreateBucket(&s3.CreateBucketInput{
+			Bucket: aws.String(c.bucket),
 		})
-		if ok && (awsErr.Code() == "BucketAlreadyOwnedByYou" || awsErr.Code() == "BucketAlreadyExists") {
-			return nil
+		if err != nil {
+			awsErr, ok := err.(interface {
+				Code() string
+				Message() string
+			})
+			if !ok || (awsErr.Code() != "BucketAlreadyOwnedByYou" && awsErr.Code() != "BucketAlreadyExists") {
+				return fmt.Errorf("failed to create bucket: %w", err)
+			}
 		}
-		return fmt.Errorf("failed to create bucket: %w", err)
 	}
 
 	_, err = c.s3Client.PutBucketPolicy(&s3.PutBucketPolicyInput{
@@ -85,9 +114,9 @@ func (c *Client) ensureBucketExists() error {
 				"Effect": "Allow",
 				"Principal": {"AWS": ["*"]},
 				"Action": ["s3:GetObject"],
-				"Resource": ["arn:aws:s3:::%s/*"]
+				"Resource": ["arn:aws:s3:::%s/%s*"]
 			}]
-		}`, c.bucket)),
+		}`, c.bucket, PublicPrefix)),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to set bucket policy: %w", err)
@@ -96,14 +125,16 @@ func (c *Client) ensureBucketExists() error {
 	return nil
 }
 
-func (c *Client) UploadFile(key string, reader io.Reader, contentType string) (string, error) {
+// UploadFile stores a private object. Callers keep the key and hand out
+// MediaURL links, or ObjectURL for objects under PublicPrefix.
+func (c *Client) UploadFile(key string, reader io.Reader, contentType string) error {
 	var body io.ReadSeeker
 	if seeker, ok := reader.(io.ReadSeeker); ok {
 		body = seeker
 	} else {
 		data, err := io.ReadAll(reader)
 		if err != nil {
-			return "", fmt.Errorf("failed to read file data: %w", err)
+			return fmt.Errorf("failed to read file data: %w", err)
 		}
 		body = bytes.NewReader(data)
 	}
@@ -113,21 +144,42 @@ func (c *Client) UploadFile(key string, reader io.Reader, contentType string) (s
 		Key:         aws.String(key),
 		Body:        body,
 		ContentType: aws.String(contentType),
-		ACL:         aws.String("public-read"),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		return fmt.Errorf("failed to upload file to S3: %w", err)
+	}
+
+	return nil
+}
+
+// MediaURL returns a presigned URL for a stored object key that is valid
+// for MediaURLTTL. Values that are already absolute URLs, such as external
+// media, are returned unchanged. Signing happens locally, so it only fails
+// on broken credentials; an empty string is returned then.
+func (c *Client) MediaURL(key string) string {
+	if key == "" || strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://") {
+		return key
+	}
+
+	url, err := c.GetPresignedURL(key, MediaURLTTL)
+	if err != nil {
+		return ""
 	}
+	return url
+}
 
+// ObjectURL returns the permanent URL of an object. Only objects under
+// PublicPrefix can be read through it.
+func (c *Client) ObjectURL(key string) string {
 	if c.publicURL != "" {
-		return fmt.Sprintf("%s/%s/%s", c.publicURL, c.bucket, key), nil
+		return fmt.Sprintf("%s/%s/%s", c.publicURL, c.bucket, key)
 	}
 
-	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", c.bucket, "us-east-1", key), nil
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", c.bucket, "us-east-1", key)
 }
 
 func (c *Client) GetPresignedURL(key string, duration time.Duration) (string, error) {
-	req, _ := c.s3Client.GetObjectRequest(&s3.GetObjectInput{
+	req, _ := c.presigner.GetObjectRequest(&s3.GetObjectInput{
 		Bucket: aws.String(c.bucket),
 		Key:    aws.String(key),
 	})
@@ -150,3 +202,127 @@ func (c *Client) DeleteFile(key string) error {
 	}
 	return nil
 }
+
+// MakePrivate resets the ACL of an object to private. Objects uploaded before
+// the bucket went private carry a public-read ACL that outlives the policy.
+func (c *Client) MakePrivate(key string) error {
+	_, err := c.s3Client.PutObjectAcl(&s3.PutObjectAclInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		ACL:    aws.String(s3.ObjectCannedACLPrivate),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set object ACL: %w", err)
+	}
+	return nil
+}
+
+// ListObjects calls fn for every object whose key starts with prefix,
+// fetching the listing page by page.
+func (c *Client) ListObjects(prefix string, fn func(Object) error) error {
+	var fnErr error
+	err := c.s3Client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			fnErr = fn(Object{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+			if fnErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list objects in S3: %w", err)
+	}
+	return fnErr
+}
+
+// GetPresignedUploadURL returns a URL the client can PUT a file of exactly
+// the given size and content type to.
+func (c *Client) GetPresignedUploadURL(key, contentType string, size int64, duration time.Duration) (string, error) {
+	req, _ := c.presigner.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(c.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	})
+
+	url, err := req.Presign(duration)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned upload URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// GetFileSize returns the size of an object, or ErrObjectNotFound.
+func (c *Client) GetFileSize(key string) (int64, error) {
+	output, err := c.s3Client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == http.StatusNotFound {
+			return 0, ErrObjectNotFound
+		}
+		return 0, fmt.Errorf("failed to get file from S3: %w", err)
+	}
+	return aws.Int64Value(output.ContentLength), nil
+}
+
+// ObjectReader reads parts of an object with ranged requests, so large
+// objects can be inspected without downloading them.
+func (c *Client) ObjectReader(key string) io.ReaderAt {
+	return &objectReader{client: c, key: key}
+}
+
+type objectReader struct {
+	client *Client
+	key    string
+}
+
+func (r *objectReader) ReadAt(p []byte, offset int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	output, err := r.client.s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.client.bucket),
+		Key:    aws.String(r.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+int64(len(p))-1)),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file from S3: %w", err)
+	}
+	defer output.Body.Close()
+
+	n, err := io.ReadFull(output.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// DownloadFile reads a whole object into memory.
+func (c *Client) DownloadFile(key string) ([]byte, error) {
+	output, err := c.s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from S3: %w", err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from S3: %w", err)
+	}
+	return data, nil
+}
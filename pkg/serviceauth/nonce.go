@@ -0,0 +1,27 @@
+package serviceauth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisNonceStore keeps used nonces in Redis so every replica of a service
+// sees them.
+type RedisNonceStore struct {
+	redisClient *redis.Client
+}
+
+func NewRedisNonceStore(redisClient *redis.Client) *RedisNonceStore {
+	return &RedisNonceStore{redisClient: redisClient}
+}
+
+func nonceKey(service, nonce string) string {
+	return fmt.Sprintf("serviceauth:nonce:%s:%s", service, nonce)
+}
+
+func (s *RedisNonceStore) Claim(ctx context.Context, service, nonce string, ttl time.Duration) (bool, error) {
+	return s.redisClient.SetNX(ctx, nonceKey(service, nonce), 1, ttl).Result()
+}
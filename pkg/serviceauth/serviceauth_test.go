@@ -0,0 +1,176 @@
+package serviceauth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte(strings.Repeat("k", MinKeyLength))
+
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (s *memoryNonceStore) Claim(ctx context.Context, service, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	key := nonceKey(service, nonce)
+	if s.seen[key] {
+		return false, nil
+	}
+	s.seen[key] = true
+	return true, nil
+}
+
+func newTestVerifier() *Verifier {
+	return NewVerifier(map[string][]byte{"wallet": testKey}, &memoryNonceStore{})
+}
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications/send?x=1", strings.NewReader(body))
+	require.NoError(t, Sign(req, "wallet", testKey))
+	return req
+}
+
+func TestParseKeys(t *testing.T) {
+	other := strings.Repeat("o", MinKeyLength)
+	keys, err := ParseKeys(" wallet:" + string(testKey) + ", post:" + other + ",")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"wallet": testKey, "post": []byte(other)}, keys)
+
+	keys, err = ParseKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, spec := range []string{"wallet", ":" + string(testKey), "wallet:short", "a:" + other + ",a:" + other} {
+		_, err := ParseKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestVerify_SignedRequest(t *testing.T) {
+	req := signedRequest(t, `{"user_id":"user-123"}`)
+
+	service, err := newTestVerifier().Verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, "wallet", service)
+
+	// The handler can still read the body
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user_id":"user-123"}`, string(body))
+}
+
+func TestVerify_Rejections(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(req *http.Request)
+		err    error
+	}{
+		{"unsigned", func(req *http.Request) { req.Header.Del(HeaderSignature) }, ErrMissingSignature},
+		{"unknown service", func(req *http.Request) { req.Header.Set(HeaderService, "post") }, ErrUnknownService},
+		{"tampered body", func(req *http.Request) { req.Body = io.NopCloser(strings.NewReader(`{"user_id":"victim"}`)) }, ErrInvalidSignature},
+		{"tampered path", func(req *http.Request) { req.URL.Path = "/api/v1/notifications/broadcast" }, ErrInvalidSignature},
+		{"tampered query", func(req *http.Request) { req.URL.RawQuery = "x=2" }, ErrInvalidSignature},
+		{"tampered nonce", func(req *http.Request) { req.Header.Set(HeaderNonce, "00") }, ErrInvalidSignature},
+		{"bad timestamp", func(req *http.Request) { req.Header.Set(HeaderTimestamp, "soon") }, ErrStaleRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signedRequest(t, `{"user_id":"user-123"}`)
+			tt.tamper(req)
+
+			_, err := newTestVerifier().Verify(req)
+			assert.ErrorIs(t, err, tt.err)
+			assert.ErrorIs(t, err, ErrUnauthenticated)
+		})
+	}
+}
+
+func TestVerify_WrongKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications/send", strings.NewReader("{}"))
+	require.NoError(t, Sign(req, "wallet", []byte(strings.Repeat("x", MinKeyLength))))
+
+	_, err := newTestVerifier().Verify(req)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerify_ClockSkew(t *testing.T) {
+	verifier := newTestVerifier()
+	now := time.Now()
+
+	verifier.now = func() time.Time { return now.Add(MaxClockSkew - time.Second) }
+	_, err := verifier.Verify(signedRequest(t, "{}"))
+	assert.NoError(t, err)
+
+	verifier.now = func() time.Time { return now.Add(MaxClockSkew + 2*time.Second) }
+	_, err = verifier.Verify(signedRequest(t, "{}"))
+	assert.ErrorIs(t, err, ErrStaleRequest)
+
+	verifier.now = func() time.Time { return now.Add(-MaxClockSkew - 2*time.Second) }
+	_, err = verifier.Verify(signedRequest(t, "{}"))
+	assert.ErrorIs(t, err, ErrStaleRequest)
+}
+
+func TestVerify_Replay(t *testing.T) {
+	verifier := newTestVerifier()
+	req := signedRequest(t, "{}")
+	replay := req.Clone(context.Background())
+	replay.Body, _ = req.GetBody()
+
+	_, err := verifier.Verify(req)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(replay)
+	assert.ErrorIs(t, err, ErrReplayedRequest)
+}
+
+func TestVerify_BodyTooLarge(t *testing.T) {
+	req := signedRequest(t, strings.Repeat("a", maxSignedBody+1))
+
+	_, err := newTestVerifier().Verify(req)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestTransport(t *testing.T) {
+	verifier := newTestVerifier()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service, err := verifier.Verify(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, service+":"+string(body))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Service: "wallet", Key: testKey}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/notifications/send", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "wallet:{}", string(body))
+
+	// The caller's request is left unsigned
+	assert.Empty(t, req.Header.Get(HeaderSignature))
+}
@@ -0,0 +1,185 @@
+// Package timeline stores each user's precomputed home feed in Redis as a
+// sorted set of post IDs scored by post creation time. The fanout service
+// writes to timelines as posts are published; the feed service reads them and
+// rebuilds a timeline from Postgres when it is missing.
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// MaxSize caps how many posts a timeline keeps; older ones are trimmed.
+	MaxSize = 1000
+	// TTL lets timelines of inactive users expire. They are rebuilt on the
+	// next read, so fan-out only has to keep live timelines up to date.
+	TTL = 7 * 24 * time.Hour
+)
+
+// Entry is a post in a timeline. CreatedAt is kept with millisecond precision.
+type Entry struct {
+	PostID    string
+	CreatedAt time.Time
+}
+
+func Key(userID string) string {
+	return fmt.Sprintf("feed:user:%s", userID)
+}
+
+// addScript only touches timelines that already exist. A missing timeline
+// means it expired or was never built; it is rebuilt in full on the next read,
+// and seeding it with a single post would hide everything older.
+var addScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+for i = 1, #ARGV - 1, 2 do
+	redis.call("ZADD", KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call("ZREMRANGEBYRANK", KEYS[1], 0, -(tonumber(ARGV[#ARGV]) + 1))
+return 1
+`)
+
+// Add inserts posts into the user's timeline if the timeline exists.
+func Add(ctx context.Context, redisClient *redis.Client, userID string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(entries)*2+1)
+	for _, entry := range entries {
+		args = append(args, score(entry.CreatedAt), entry.PostID)
+	}
+	args = append(args, MaxSize)
+
+	return addScript.Run(ctx, redisClient, []string{Key(userID)}, args...).Err()
+}
+
+// Remove drops posts from the user's timeline.
+func Remove(ctx context.Context, redisClient *redis.Client, userID string, postIDs ...string) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(postIDs))
+	for i, postID := range postIDs {
+		members[i] = postID
+	}
+	return redisClient.ZRem(ctx, Key(userID), members...).Err()
+}
+
+// Replace overwrites the user's timeline with the given posts.
+func Replace(ctx context.Context, redisClient *redis.Client, userID string, entries []Entry) error {
+	key := Key(userID)
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(entries) == 0 {
+			return nil
+		}
+
+		members := make([]redis.Z, len(entries))
+		for i, entry := range entries {
+			members[i] = redis.Z{Score: score(entry.CreatedAt), Member: entry.PostID}
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.ZRemRangeByRank(ctx, key, 0, -(MaxSize + 1))
+		pipe.Expire(ctx, key, TTL)
+		return nil
+	})
+	return err
+}
+
+// Page returns up to limit entries newest first, continuing after the given
+// entry when it is not nil. exists is false when the timeline has to be
+// rebuilt; reading a timeline extends its TTL.
+func Page(ctx context.Context, redisClient *redis.Client, userID string, after *Entry, limit int) (entries []Entry, exists bool, err error) {
+	key := Key(userID)
+
+	found, err := redisClient.Expire(ctx, key, TTL).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	max := "+inf"
+	if after != nil {
+		// Posts sharing the cursor's score are ordered by ID, like in ZREVRANGE
+		afterScore := formatScore(score(after.CreatedAt))
+		tied, err := redisClient.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: afterScore, Max: afterScore}).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		for _, member := range tied {
+			if entry := toEntry(member); entry.PostID < after.PostID && len(entries) < limit {
+				entries = append(entries, entry)
+			}
+		}
+		max = "(" + afterScore
+	}
+
+	if len(entries) < limit {
+		members, err := redisClient.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   max,
+			Count: int64(limit - len(entries)),
+		}).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		for _, member := range members {
+			entries = append(entries, toEntry(member))
+		}
+	}
+	return entries, true, nil
+}
+
+// PageEntries pages through entries that are not stored in Redis in the same
+// order Page uses.
+func PageEntries(entries []Entry, after *Entry, limit int) []Entry {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return newerThan(sorted[i], sorted[j])
+	})
+
+	start := 0
+	if after != nil {
+		for start < len(sorted) && !newerThan(*after, sorted[start]) {
+			start++
+		}
+	}
+
+	end := start + limit
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+	return sorted[start:end]
+}
+
+func newerThan(a, b Entry) bool {
+	if score(a.CreatedAt) != score(b.CreatedAt) {
+		return score(a.CreatedAt) > score(b.CreatedAt)
+	}
+	return a.PostID > b.PostID
+}
+
+func toEntry(member redis.Z) Entry {
+	postID, _ := member.Member.(string)
+	return Entry{PostID: postID, CreatedAt: time.UnixMilli(int64(member.Score)).UTC()}
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
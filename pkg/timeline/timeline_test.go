@@ -0,0 +1,138 @@
+package timeline
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The timeline scripts only run against a real Redis. Point
+// TIMELINE_TEST_REDIS_ADDR at one to run these tests, e.g.
+//
+//	TIMELINE_TEST_REDIS_ADDR=localhost:6379 make test-integration
+func setupTestRedis(t *testing.T) *redis.Client {
+	addr := os.Getenv("TIMELINE_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("Skipping timeline integration test - TIMELINE_TEST_REDIS_ADDR is not set")
+	}
+
+	redisClient := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { redisClient.Close() })
+	return redisClient
+}
+
+func TestTimeline(t *testing.T) {
+	redisClient := setupTestRedis(t)
+	ctx := context.Background()
+	userID := uuid.New().String()
+	t.Cleanup(func() { redisClient.Del(ctx, Key(userID)) })
+
+	now := time.Now()
+	entry := func(postID string, age time.Duration) Entry {
+		return Entry{PostID: postID, CreatedAt: now.Add(-age)}
+	}
+
+	// Fan-out skips timelines that were never built
+	require.NoError(t, Add(ctx, redisClient, userID, entry("new", 0)))
+	_, exists, err := Page(ctx, redisClient, userID, nil, 10)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, Replace(ctx, redisClient, userID, []Entry{entry("old", 2*time.Hour), entry("older", 3*time.Hour)}))
+	require.NoError(t, Add(ctx, redisClient, userID, entry("new", 0), entry("middle", time.Hour)))
+
+	entries, exists, err := Page(ctx, redisClient, userID, nil, 10)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []string{"new", "middle", "old", "older"}, postIDs(entries))
+
+	entries, _, err = Page(ctx, redisClient, userID, &entries[0], 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"middle", "old"}, postIDs(entries))
+
+	require.NoError(t, Remove(ctx, redisClient, userID, "middle", "missing"))
+	entries, _, err = Page(ctx, redisClient, userID, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "old", "older"}, postIDs(entries))
+}
+
+func TestPage_CursorIsStableAsPostsArrive(t *testing.T) {
+	redisClient := setupTestRedis(t)
+	ctx := context.Background()
+	userID := uuid.New().String()
+	t.Cleanup(func() { redisClient.Del(ctx, Key(userID)) })
+
+	createdAt := time.Now().Add(-time.Hour)
+	require.NoError(t, Replace(ctx, redisClient, userID, []Entry{
+		{PostID: "a", CreatedAt: createdAt},
+		{PostID: "b", CreatedAt: createdAt},
+		{PostID: "c", CreatedAt: createdAt},
+		{PostID: "d", CreatedAt: createdAt.Add(-time.Minute)},
+	}))
+
+	first, _, err := Page(ctx, redisClient, userID, nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, postIDs(first))
+
+	require.NoError(t, Add(ctx, redisClient, userID, Entry{PostID: "new", CreatedAt: time.Now()}))
+
+	second, _, err := Page(ctx, redisClient, userID, &first[1], 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "d"}, postIDs(second))
+}
+
+func TestAdd_TrimsToMaxSize(t *testing.T) {
+	redisClient := setupTestRedis(t)
+	ctx := context.Background()
+	userID := uuid.New().String()
+	t.Cleanup(func() { redisClient.Del(ctx, Key(userID)) })
+
+	now := time.Now()
+	entries := make([]Entry, MaxSize)
+	for i := range entries {
+		entries[i] = Entry{PostID: uuid.New().String(), CreatedAt: now.Add(-time.Duration(i+1) * time.Minute)}
+	}
+	require.NoError(t, Replace(ctx, redisClient, userID, entries))
+	require.NoError(t, Add(ctx, redisClient, userID, Entry{PostID: "newest", CreatedAt: now}))
+
+	size, err := redisClient.ZCard(ctx, Key(userID)).Result()
+	require.NoError(t, err)
+	assert.EqualValues(t, MaxSize, size)
+
+	oldest, err := redisClient.ZRange(ctx, Key(userID), 0, 0).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{entries[MaxSize-2].PostID}, oldest)
+}
+
+func TestPageEntries(t *testing.T) {
+	createdAt := time.Date(2026, 1, 14, 12, 0, 0, 500_000_000, time.UTC)
+	entries := []Entry{
+		{PostID: "d", CreatedAt: createdAt.Add(-time.Minute)},
+		{PostID: "a", CreatedAt: createdAt},
+		{PostID: "c", CreatedAt: createdAt},
+		{PostID: "b", CreatedAt: createdAt.Add(time.Microsecond)},
+	}
+
+	// Timestamps within the same millisecond tie and are ordered by ID
+	first := PageEntries(entries, nil, 2)
+	assert.Equal(t, []string{"c", "b"}, postIDs(first))
+
+	second := PageEntries(entries, &first[1], 2)
+	assert.Equal(t, []string{"a", "d"}, postIDs(second))
+
+	assert.Empty(t, PageEntries(entries, &second[1], 2))
+}
+
+func postIDs(entries []Entry) []string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.PostID
+	}
+	return ids
+}
@@ -0,0 +1,92 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; the last 6 digits are the 6 digit code
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for unix, want := range vectors {
+		got, err := Code(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want[2:], got, "time %d", unix)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+
+	code, err := Code(secret, now)
+	require.NoError(t, err)
+	step, ok := Match(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// One step of drift either way is accepted
+	previous, _ := Code(secret, now.Add(-Period))
+	step, ok = Match(secret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+	next, _ := Code(secret, now.Add(Period))
+	_, ok = Match(secret, next, now)
+	assert.True(t, ok)
+
+	stale, _ := Code(secret, now.Add(-2*Period))
+	_, ok = Match(secret, stale, now)
+	assert.False(t, ok)
+
+	for _, bad := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok = Match(secret, bad, now)
+		assert.False(t, ok, bad)
+	}
+	_, ok = Match("not base32!", code, now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+	assert.NotContains(t, secret, "=")
+
+	// Apps show secrets in lower case groups; both forms decode
+	lower := strings.ToLower(secret[:16]) + " " + secret[16:]
+	a, _ := Code(secret, time.Unix(0, 0))
+	b, err := Code(lower, time.Unix(0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Lick Scroll", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Lick Scroll:user@example.com", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Lick Scroll", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
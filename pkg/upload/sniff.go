@@ -0,0 +1,109 @@
+package upload
+
+import "bytes"
+
+// sniffLen is how much of a file is inspected to detect its type and
+// embedded markup; browsers look at no more than the first 1445 bytes.
+const sniffLen = 2048
+
+type fileType struct {
+	name        string
+	contentType string
+	extensions  []string
+	match       func(head []byte) bool
+}
+
+func (t *fileType) hasExtension(ext string) bool {
+	for _, allowed := range t.extensions {
+		if allowed == ext {
+			return true
+		}
+	}
+	return false
+}
+
+var fileTypes = []*fileType{
+	{
+		name:        "jpeg",
+		contentType: "image/jpeg",
+		extensions:  []string{".jpg", ".jpeg"},
+		match:       prefix("\xFF\xD8\xFF"),
+	},
+	{
+		name:        "png",
+		contentType: "image/png",
+		extensions:  []string{".png"},
+		match:       prefix("\x89PNG\r\n\x1A\n"),
+	},
+	{
+		name:        "gif",
+		contentType: "image/gif",
+		extensions:  []string{".gif"},
+		match: func(head []byte) bool {
+			return prefix("GIF87a")(head) || prefix("GIF89a")(head)
+		},
+	},
+	{
+		name:        "webp",
+		contentType: "image/webp",
+		extensions:  []string{".webp"},
+		match:       riff("WEBP"),
+	},
+	{
+		name:        "mp4",
+		contentType: "video/mp4",
+		extensions:  []string{".mp4", ".m4v"},
+		match:       ftyp("isom", "iso2", "iso4", "iso5", "iso6", "mp41", "mp42", "avc1", "M4V ", "dash"),
+	},
+	{
+		name:        "mov",
+		contentType: "video/quicktime",
+		extensions:  []string{".mov"},
+		match:       ftyp("qt  "),
+	},
+	{
+		name:        "avi",
+		contentType: "video/x-msvideo",
+		extensions:  []string{".avi"},
+		match:       riff("AVI "),
+	},
+}
+
+// sniff detects the type of a file from its first bytes. It returns nil for
+// anything that isn't a known type.
+func sniff(head []byte) *fileType {
+	for _, t := range fileTypes {
+		if t.match(head) {
+			return t
+		}
+	}
+	return nil
+}
+
+func prefix(signature string) func(head []byte) bool {
+	return func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte(signature))
+	}
+}
+
+// riff matches RIFF containers of the given form type.
+func riff(form string) func(head []byte) bool {
+	return func(head []byte) bool {
+		return len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == form
+	}
+}
+
+// ftyp matches ISO base media files whose major brand is one of brands.
+func ftyp(brands ...string) func(head []byte) bool {
+	return func(head []byte) bool {
+		if len(head) < 12 || string(head[4:8]) != "ftyp" {
+			return false
+		}
+		for _, brand := range brands {
+			if string(head[8:12]) == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
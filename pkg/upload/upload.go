@@ -0,0 +1,264 @@
+// Package upload validates files uploaded by users before they are stored.
+// The type of a file is detected from its content, never from the
+// client's Content-Type header; the filename extension has to agree with it.
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"lick-scroll/pkg/imaging"
+)
+
+// Error codes returned to clients along with the message.
+const (
+	CodeEmptyFile         = "empty_file"
+	CodeFileTooLarge      = "file_too_large"
+	CodeUnsupportedType   = "unsupported_type"
+	CodeExtensionMismatch = "extension_mismatch"
+	CodeInvalidFile       = "invalid_file"
+)
+
+// Error is a rejected upload. Status is the HTTP status to respond with.
+type Error struct {
+	Status   int
+	Code     string
+	Message  string
+	Filename string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Body is the JSON response for the rejected upload.
+func (e *Error) Body() map[string]interface{} {
+	return map[string]interface{}{
+		"error": e.Message,
+		"code":  e.Code,
+		"file":  e.Filename,
+	}
+}
+
+func reject(filename string, status int, code, format string, args ...interface{}) *Error {
+	return &Error{
+		Status:   status,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Filename: filename,
+	}
+}
+
+// Policy lists the content types accepted for one kind of upload and how
+// large the files may be.
+type Policy struct {
+	ContentTypes []string
+	MaxSize      int64
+}
+
+var (
+	Images = Policy{
+		ContentTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+		MaxSize:      20 << 20,
+	}
+	Videos = Policy{
+		ContentTypes: []string{"video/mp4", "video/quicktime", "video/x-msvideo"},
+		MaxSize:      100 << 20,
+	}
+	Avatars = Policy{
+		ContentTypes: []string{"image/jpeg", "image/png", "image/gif"},
+		MaxSize:      5 << 20,
+	}
+)
+
+func (p Policy) allows(contentType string) bool {
+	for _, allowed := range p.ContentTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// File is an upload that passed validation. Extension is the lowercased
+// extension of the original filename, including the dot.
+type File struct {
+	ContentType string
+	Extension   string
+	Size        int64
+}
+
+// Validate checks an uploaded file against the policy. The returned error is
+// an *Error whenever the file itself is at fault.
+func Validate(file *multipart.FileHeader, policy Policy) (*File, error) {
+	if err := checkSize(file.Filename, file.Size, policy); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	return ValidateContent(file.Filename, src, file.Size, policy)
+}
+
+// ValidateContent is Validate for files that are read from elsewhere, such
+// as objects uploaded straight to S3.
+func ValidateContent(filename string, src io.ReaderAt, size int64, policy Policy) (*File, error) {
+	if err := checkSize(filename, size, policy); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, min(sniffLen, size))
+	if _, err := src.ReadAt(head, 0); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	fileType := sniff(head)
+	if fileType == nil || !policy.allows(fileType.contentType) {
+		return nil, reject(filename, http.StatusUnsupportedMediaType, CodeUnsupportedType, "unsupported file type, allowed: %s", policy.describe())
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !fileType.hasExtension(ext) {
+		return nil, reject(filename, http.StatusBadRequest, CodeExtensionMismatch, "file extension does not match its content (%s)", fileType.name)
+	}
+
+	if containsMarkup(head) {
+		return nil, reject(filename, http.StatusBadRequest, CodeInvalidFile, "file contains embedded markup")
+	}
+	tail, err := readTail(src, size)
+	if err != nil {
+		return nil, err
+	}
+	if hasZipDirectory(tail) {
+		return nil, reject(filename, http.StatusBadRequest, CodeInvalidFile, "file contains an embedded archive")
+	}
+
+	if strings.HasPrefix(fileType.contentType, "image/") {
+		config, _, err := image.DecodeConfig(io.NewSectionReader(src, 0, size))
+		if err != nil {
+			return nil, reject(filename, http.StatusBadRequest, CodeInvalidFile, "image is corrupted")
+		}
+		if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > imaging.MaxPixels {
+			return nil, reject(filename, http.StatusBadRequest, CodeInvalidFile, "image dimensions are too large")
+		}
+	}
+
+	return &File{
+		ContentType: fileType.contentType,
+		Extension:   ext,
+		Size:        size,
+	}, nil
+}
+
+// CheckDeclared checks the name and size a client declares for a file it
+// is about to upload elsewhere. The content type is taken from the
+// extension; the content itself still has to pass ValidateContent.
+func CheckDeclared(filename string, size int64, policy Policy) (*File, error) {
+	if err := checkSize(filename, size, policy); err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, fileType := range fileTypes {
+		if fileType.hasExtension(ext) && policy.allows(fileType.contentType) {
+			return &File{
+				ContentType: fileType.contentType,
+				Extension:   ext,
+				Size:        size,
+			}, nil
+		}
+	}
+	return nil, reject(filename, http.StatusUnsupportedMediaType, CodeUnsupportedType, "unsupported file type, allowed: %s", policy.describe())
+}
+
+func checkSize(filename string, size int64, policy Policy) error {
+	if size <= 0 {
+		return reject(filename, http.StatusBadRequest, CodeEmptyFile, "file is empty")
+	}
+	if size > policy.MaxSize {
+		return reject(filename, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "file is larger than %d MB", policy.MaxSize>>20)
+	}
+	return nil
+}
+
+func (p Policy) describe() string {
+	var names []string
+	for _, contentType := range p.ContentTypes {
+		for _, fileType := range fileTypes {
+			if fileType.contentType == contentType {
+				names = append(names, fileType.name)
+				break
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// markupSignatures are looked for at the start of every file. Browsers sniff
+// the first bytes of a response, and a file that also parses as HTML or SVG
+// there can be rendered as a page on our domain.
+var markupSignatures = [][]byte{
+	[]byte("<!doctype"),
+	[]byte("<html"),
+	[]byte("<head"),
+	[]byte("<body"),
+	[]byte("<script"),
+	[]byte("<iframe"),
+	[]byte("<svg"),
+	[]byte("<?php"),
+}
+
+func containsMarkup(head []byte) bool {
+	lower := bytes.ToLower(head)
+	for _, signature := range markupSignatures {
+		if bytes.Contains(lower, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxZipTrailer is the size of a ZIP end of central directory record with
+// the longest possible comment.
+const maxZipTrailer = 22 + 0xFFFF
+
+func readTail(src io.ReaderAt, size int64) ([]byte, error) {
+	offset := size - maxZipTrailer
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, size-offset)
+	if _, err := src.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return tail, nil
+}
+
+// hasZipDirectory reports whether the data ends with a ZIP end of central
+// directory record, which makes the file readable as an archive (GIFAR and
+// similar polyglots). The comment length has to match so random bytes in
+// compressed media don't trigger it.
+func hasZipDirectory(tail []byte) bool {
+	signature := []byte("PK\x05\x06")
+	for i := len(tail) - 22; i >= 0; i-- {
+		if !bytes.Equal(tail[i:i+4], signature) {
+			continue
+		}
+		commentLen := int(binary.LittleEndian.Uint16(tail[i+20 : i+22]))
+		if i+22+commentLen == len(tail) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package upload
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileHeader runs the data through a multipart form so the header is the
+// same kind the HTTP handlers receive.
+func fileHeader(t *testing.T, filename string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["file"][0]
+}
+
+func encodeTestPNG(t *testing.T) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	return buf.Bytes()
+}
+
+func assertRejected(t *testing.T, err error, status int, code string) {
+	t.Helper()
+	var uploadErr *Error
+	require.ErrorAs(t, err, &uploadErr)
+	assert.Equal(t, status, uploadErr.Status)
+	assert.Equal(t, code, uploadErr.Code)
+}
+
+func TestValidate_DetectsTypeFromContent(t *testing.T) {
+	file, err := Validate(fileHeader(t, "Photo.PNG", encodeTestPNG(t)), Images)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", file.ContentType)
+	assert.Equal(t, ".png", file.Extension)
+
+	video := append([]byte("\x00\x00\x00\x18ftypmp42"), make([]byte, 64)...)
+	file, err = Validate(fileHeader(t, "clip.mp4", video), Videos)
+	require.NoError(t, err)
+	assert.Equal(t, "video/mp4", file.ContentType)
+
+	mov := append([]byte("\x00\x00\x00\x14ftypqt  "), make([]byte, 64)...)
+	file, err = Validate(fileHeader(t, "clip.mov", mov), Videos)
+	require.NoError(t, err)
+	assert.Equal(t, "video/quicktime", file.ContentType)
+}
+
+func TestValidate_RejectsDisallowedFiles(t *testing.T) {
+	_, err := Validate(fileHeader(t, "photo.jpg", nil), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeEmptyFile)
+
+	_, err = Validate(fileHeader(t, "notes.jpg", []byte("just some text")), Images)
+	assertRejected(t, err, http.StatusUnsupportedMediaType, CodeUnsupportedType)
+
+	// Known types outside the policy are rejected too
+	_, err = Validate(fileHeader(t, "photo.png", encodeTestPNG(t)), Videos)
+	assertRejected(t, err, http.StatusUnsupportedMediaType, CodeUnsupportedType)
+
+	_, err = Validate(fileHeader(t, "photo.jpg", encodeTestPNG(t)), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeExtensionMismatch)
+	_, err = Validate(fileHeader(t, "photo", encodeTestPNG(t)), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeExtensionMismatch)
+
+	large := append(encodeTestPNG(t), make([]byte, Avatars.MaxSize)...)
+	_, err = Validate(fileHeader(t, "avatar.png", large), Avatars)
+	assertRejected(t, err, http.StatusRequestEntityTooLarge, CodeFileTooLarge)
+
+	// A valid signature doesn't make a corrupted image acceptable
+	_, err = Validate(fileHeader(t, "photo.png", encodeTestPNG(t)[:20]), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeInvalidFile)
+}
+
+func TestCheckDeclared(t *testing.T) {
+	file, err := CheckDeclared("Clip.MOV", 5000, Videos)
+	require.NoError(t, err)
+	assert.Equal(t, "video/quicktime", file.ContentType)
+	assert.Equal(t, ".mov", file.Extension)
+
+	_, err = CheckDeclared("clip.mkv", 5000, Videos)
+	assertRejected(t, err, http.StatusUnsupportedMediaType, CodeUnsupportedType)
+	_, err = CheckDeclared("photo.png", 5000, Videos)
+	assertRejected(t, err, http.StatusUnsupportedMediaType, CodeUnsupportedType)
+	_, err = CheckDeclared("clip.mp4", Videos.MaxSize+1, Videos)
+	assertRejected(t, err, http.StatusRequestEntityTooLarge, CodeFileTooLarge)
+	_, err = CheckDeclared("clip.mp4", 0, Videos)
+	assertRejected(t, err, http.StatusBadRequest, CodeEmptyFile)
+}
+
+func TestValidateContent_ChecksFilesStoredElsewhere(t *testing.T) {
+	video := append([]byte("\x00\x00\x00\x18ftypisom"), make([]byte, 64)...)
+	file, err := ValidateContent("clip.mp4", bytes.NewReader(video), int64(len(video)), Videos)
+	require.NoError(t, err)
+	assert.Equal(t, "video/mp4", file.ContentType)
+
+	// The declared extension still has to match what was actually uploaded
+	_, err = ValidateContent("clip.mov", bytes.NewReader(video), int64(len(video)), Videos)
+	assertRejected(t, err, http.StatusBadRequest, CodeExtensionMismatch)
+}
+
+func TestValidate_RejectsPolyglots(t *testing.T) {
+	// An image that starts with HTML in a text chunk
+	withMarkup := encodeTestPNG(t)
+	withMarkup = append(withMarkup[:33:33], append([]byte("\x00\x00\x00\x1DtEXtc\x00<SCRIPT>alert(1)</script>\x00\x00\x00\x00"), withMarkup[33:]...)...)
+	_, err := Validate(fileHeader(t, "photo.png", withMarkup), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeInvalidFile)
+
+	// An image with a ZIP archive appended (GIFAR-style)
+	archive := []byte("PK\x03\x04payloadPK\x05\x06\x00\x00\x00\x00\x01\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02\x00hi")
+	_, err = Validate(fileHeader(t, "photo.png", append(encodeTestPNG(t), archive...)), Images)
+	assertRejected(t, err, http.StatusBadRequest, CodeInvalidFile)
+}
+
+func TestHasZipDirectory_RequiresMatchingCommentLength(t *testing.T) {
+	record := []byte("PK\x05\x06\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+	assert.True(t, hasZipDirectory(record))
+	assert.False(t, hasZipDirectory(append(record, 0x01)))
+	assert.False(t, hasZipDirectory([]byte("PK\x05\x06")))
+}
@@ -4,7 +4,7 @@
 
 export PATH=$PATH:$(go env GOPATH)/bin
 
-SERVICES=("auth" "post" "feed" "fanout" "wallet" "notification" "moderation" "analytics")
+SERVICES=("auth" "post" "feed" "fanout" "wallet" "notification" "moderation" "analytics" "search")
 
 for service in "${SERVICES[@]}"; do
     echo "Generating Swagger docs for $service service..."
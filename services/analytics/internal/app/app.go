@@ -12,6 +12,7 @@ import (
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/models"
 	analyticsHTTP "lick-scroll/services/analytics/internal/controller/http"
 	"lick-scroll/services/analytics/internal/repo/persistent"
 	"lick-scroll/services/analytics/internal/usecase"
@@ -27,7 +28,8 @@ import (
 )
 
 func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
 
 	// Initialize repositories
 	analyticsRepo := persistent.NewAnalyticsRepository(db)
@@ -60,8 +62,9 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(jwtService))
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
 	api.Use(middleware.RateLimitMiddleware(redisClient, 100, time.Minute))
+	api.Use(middleware.RequireRole(models.RoleCreator))
 
 	{
 		api.GET("/analytics/creator/stats", analyticsHandler.GetCreatorStats)
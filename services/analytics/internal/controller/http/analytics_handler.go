@@ -23,7 +23,7 @@ func NewAnalyticsHandler(analyticsUseCase usecase.AnalyticsUseCase, logger *logg
 
 // GetCreatorStats godoc
 // @Summary      Get creator statistics
-// @Description  Get overall statistics for the authenticated creator. Views are incremented when someone views a post via GET /posts/{id}. Revenue is calculated from donations received.
+// @Description  Get overall statistics for the authenticated creator. Views are incremented when someone views a post via GET /posts/{id}. Revenue is calculated from donations received. Creators only.
 // @Tags         analytics
 // @Accept       json
 // @Produce      json
@@ -46,7 +46,7 @@ func (h *AnalyticsHandler) GetCreatorStats(c *gin.Context) {
 
 // GetPostStats godoc
 // @Summary      Get post statistics
-// @Description  Get statistics for a specific post. Views are incremented when someone views the post via GET /posts/{id}. Donations are counted from TransactionTypeDonation.
+// @Description  Get statistics for a specific post. Views are incremented when someone views the post via GET /posts/{id}. Donations are counted from TransactionTypeDonation. Creators only.
 // @Tags         analytics
 // @Accept       json
 // @Produce      json
@@ -78,7 +78,7 @@ func (h *AnalyticsHandler) GetPostStats(c *gin.Context) {
 
 // GetRevenue godoc
 // @Summary      Get creator revenue
-// @Description  Get total revenue for the authenticated creator. Revenue is calculated from donations received (TransactionTypeEarn transactions with positive amounts).
+// @Description  Get total revenue for the authenticated creator. revenue/net_revenue is what the creator received (TransactionTypeEarn transactions with positive amounts), gross_revenue is what viewers paid before the platform fee, and platform_fee is the difference. Creators only.
 // @Tags         analytics
 // @Accept       json
 // @Produce      json
@@ -97,7 +97,10 @@ func (h *AnalyticsHandler) GetRevenue(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"creator_id": userID,
-		"revenue":    revenue,
+		"creator_id":    userID,
+		"revenue":       revenue.Net,
+		"gross_revenue": revenue.Gross,
+		"platform_fee":  revenue.PlatformFee,
+		"net_revenue":   revenue.Net,
 	})
 }
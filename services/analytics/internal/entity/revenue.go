@@ -0,0 +1,9 @@
+package entity
+
+// Revenue splits what viewers paid a creator (gross) into the platform fee
+// and what the creator actually received (net).
+type Revenue struct {
+	Gross       int `json:"gross"`
+	PlatformFee int `json:"platform_fee"`
+	Net         int `json:"net"`
+}
@@ -10,6 +10,7 @@ type TransactionModel struct {
 	Amount        int       `gorm:"column:amount;type:integer;not null"`
 	BalanceBefore int       `gorm:"column:balance_before;type:integer"`
 	BalanceAfter  int       `gorm:"column:balance_after;type:integer"`
+	TransferID    *string   `gorm:"column:transfer_id;type:uuid"`
 	CreatedAt     time.Time `gorm:"column:created_at;type:timestamp"`
 }
 
@@ -12,7 +12,7 @@ type AnalyticsRepository interface {
 	GetPostByID(postID string) (*entity.Post, error)
 	GetPostDonations(postID string) (int64, error)
 	GetPostDonationAmount(postID string) (int, error)
-	GetCreatorRevenue(creatorID string) (int, error)
+	GetCreatorRevenue(creatorID string) (*entity.Revenue, error)
 	GetPostLikeCount(postID string) (int64, error)
 	GetCreatorSubscriberCount(creatorID string) (int64, error)
 }
@@ -62,16 +62,54 @@ func (r *analyticsRepository) GetPostDonationAmount(postID string) (int, error)
 	return int(totalAmount), nil
 }
 
-func (r *analyticsRepository) GetCreatorRevenue(creatorID string) (int, error) {
-	var totalRevenue int64
-	err := r.db.Model(&model.TransactionModel{}).
-		Where("user_id = ? AND type = ? AND amount > 0", creatorID, "earn").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalRevenue).Error
+// paymentTypes are the payer sides of the transfers that pay a creator. Top-ups
+// are written as earnings too, but their transfer has no payer.
+var paymentTypes = []string{"donation", "purchase", "subscription"}
+
+// GetCreatorRevenue reports the creator's earnings (net) and the platform fee
+// that was taken from the same payments. Only earnings written in the same
+// transfer as a payment count, and fees are matched to them through that
+// transfer. Earnings from before transfers existed have no transfer_id; those
+// with a post are donations and count in full, with no fee, while those
+// without one are top-ups. Refunds of either are subtracted.
+func (r *analyticsRepository) GetCreatorRevenue(creatorID string) (*entity.Revenue, error) {
+	paymentTransfers := r.db.Model(&model.TransactionModel{}).
+		Select("transfer_id").
+		Where("type IN ? AND transfer_id IS NOT NULL", paymentTypes)
+	earnings := func(column string) *gorm.DB {
+		return r.db.Model(&model.TransactionModel{}).
+			Select(column).
+			Where("user_id = ? AND type = ?", creatorID, "earn").
+			Where("transfer_id IN (?) OR (transfer_id IS NULL AND post_id IS NOT NULL)", paymentTransfers)
+	}
+	fees := r.db.Model(&model.TransactionModel{}).
+		Select("id").
+		Where("type = ? AND transfer_id IN (?)", "fee", earnings("transfer_id"))
+
+	net, err := r.sumWithRefunds(earnings("id"))
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return int(totalRevenue), nil
+	fee, err := r.sumWithRefunds(fees)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Revenue{
+		Gross:       int(net + fee),
+		PlatformFee: int(fee),
+		Net:         int(net),
+	}, nil
+}
+
+// sumWithRefunds adds up the given transactions and the refunds that reverse them.
+func (r *analyticsRepository) sumWithRefunds(transactionIDs *gorm.DB) (int64, error) {
+	var sum int64
+	err := r.db.Model(&model.TransactionModel{}).
+		Where("id IN (?) OR (type = ? AND refunded_transaction_id IN (?))", transactionIDs, "refund", transactionIDs).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&sum).Error
+	return sum, err
 }
 
 func (r *analyticsRepository) GetPostLikeCount(postID string) (int64, error) {
@@ -0,0 +1,151 @@
+package persistent
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Revenue is computed in SQL, so it is tested against a real PostgreSQL
+// database. Point ANALYTICS_TEST_DSN at a migrated database to run it.
+func setupAnalyticsTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("ANALYTICS_TEST_DSN")
+	if dsn == "" {
+		t.Skip("Skipping analytics integration test - ANALYTICS_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func createTestUsers(t *testing.T, db *gorm.DB, count int) []string {
+	userIDs := make([]string, count)
+	for i := range userIDs {
+		userIDs[i] = uuid.New().String()
+		suffix := userIDs[i][:8]
+		err := db.Exec(
+			"INSERT INTO users (id, email, username, password) VALUES (?, ?, ?, ?)",
+			userIDs[i], fmt.Sprintf("analytics-%s@test.local", suffix), fmt.Sprintf("analytics-%s", suffix), "x",
+		).Error
+		require.NoError(t, err)
+	}
+
+	t.Cleanup(func() {
+		// Transactions are removed by ON DELETE CASCADE
+		db.Exec("DELETE FROM users WHERE id IN ?", userIDs)
+	})
+	return userIDs
+}
+
+// ledgerRow is a transaction as the wallet service writes it.
+type ledgerRow struct {
+	userID     string
+	txType     string
+	amount     int
+	transferID string
+	refundedID string
+}
+
+func insertTransfer(t *testing.T, db *gorm.DB, rows ...ledgerRow) []string {
+	transferID := uuid.New().String()
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = uuid.New().String()
+		var refundedID interface{}
+		if row.refundedID != "" {
+			refundedID = row.refundedID
+		}
+		err := db.Exec(
+			"INSERT INTO transactions (id, user_id, type, amount, transfer_id, refunded_transaction_id) VALUES (?, ?, ?, ?, ?, ?)",
+			ids[i], row.userID, row.txType, row.amount, transferID, refundedID,
+		).Error
+		require.NoError(t, err)
+	}
+	return ids
+}
+
+func TestGetCreatorRevenue_IgnoresTopUpsAndSubtractsRefunds(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	repo := NewAnalyticsRepository(db)
+
+	userIDs := createTestUsers(t, db, 3)
+	viewerID, creatorID, platformID := userIDs[0], userIDs[1], userIDs[2]
+
+	// A top-up is a lone earning with no payer
+	insertTransfer(t, db, ledgerRow{userID: creatorID, txType: "earn", amount: 1000})
+
+	donation := insertTransfer(t, db,
+		ledgerRow{userID: viewerID, txType: "donation", amount: -100},
+		ledgerRow{userID: creatorID, txType: "earn", amount: 90},
+		ledgerRow{userID: platformID, txType: "fee", amount: 10},
+	)
+	insertTransfer(t, db,
+		ledgerRow{userID: viewerID, txType: "purchase", amount: -50},
+		ledgerRow{userID: creatorID, txType: "earn", amount: 45},
+		ledgerRow{userID: platformID, txType: "fee", amount: 5},
+	)
+
+	// Refunding the donation reverses every row of its transfer
+	insertTransfer(t, db,
+		ledgerRow{userID: viewerID, txType: "refund", amount: 100, refundedID: donation[0]},
+		ledgerRow{userID: creatorID, txType: "refund", amount: -90, refundedID: donation[1]},
+		ledgerRow{userID: platformID, txType: "refund", amount: -10, refundedID: donation[2]},
+	)
+
+	revenue, err := repo.GetCreatorRevenue(creatorID)
+	require.NoError(t, err)
+	assert.Equal(t, 45, revenue.Net)
+	assert.Equal(t, 5, revenue.PlatformFee)
+	assert.Equal(t, 50, revenue.Gross)
+}
+
+func TestGetCreatorRevenue_CountsLegacyEarnings(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	repo := NewAnalyticsRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	viewerID, creatorID := userIDs[0], userIDs[1]
+
+	postID := uuid.New().String()
+	err := db.Exec(
+		"INSERT INTO posts (id, creator_id, title, type, media_url) VALUES (?, ?, ?, ?, ?)",
+		postID, creatorID, "legacy", "photo", "posts/legacy.jpg",
+	).Error
+	require.NoError(t, err)
+
+	// Before transfers, a donation was two rows without a transfer_id and a
+	// top-up a lone earning without a post
+	legacy := []struct {
+		userID string
+		postID interface{}
+		txType string
+		amount int
+	}{
+		{viewerID, postID, "donation", -70},
+		{creatorID, postID, "earn", 70},
+		{creatorID, nil, "earn", 500},
+	}
+	for _, row := range legacy {
+		err := db.Exec(
+			"INSERT INTO transactions (id, user_id, post_id, type, amount) VALUES (?, ?, ?, ?, ?)",
+			uuid.New().String(), row.userID, row.postID, row.txType, row.amount,
+		).Error
+		require.NoError(t, err)
+	}
+
+	revenue, err := repo.GetCreatorRevenue(creatorID)
+	require.NoError(t, err)
+	assert.Equal(t, 70, revenue.Net)
+	assert.Equal(t, 0, revenue.PlatformFee)
+	assert.Equal(t, 70, revenue.Gross)
+}
@@ -4,13 +4,14 @@ import (
 	"fmt"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/services/analytics/internal/entity"
 	"lick-scroll/services/analytics/internal/repo/persistent"
 )
 
 type AnalyticsUseCase interface {
 	GetCreatorStats(creatorID string) (map[string]interface{}, error)
 	GetPostStats(postID, creatorID string) (map[string]interface{}, error)
-	GetRevenue(creatorID string) (int, error)
+	GetRevenue(creatorID string) (*entity.Revenue, error)
 }
 
 type analyticsUseCase struct {
@@ -54,7 +55,7 @@ func (uc *analyticsUseCase) GetCreatorStats(creatorID string) (map[string]interf
 	revenue, err := uc.analyticsRepo.GetCreatorRevenue(creatorID)
 	if err != nil {
 		uc.logger.Error("Failed to get revenue: %v", err)
-		revenue = 0
+		revenue = &entity.Revenue{}
 	}
 
 	subscribers, err := uc.analyticsRepo.GetCreatorSubscriberCount(creatorID)
@@ -68,7 +69,9 @@ func (uc *analyticsUseCase) GetCreatorStats(creatorID string) (map[string]interf
 		"total_views":       totalViews,
 		"total_donations":   totalDonations,
 		"total_likes":       totalLikes,
-		"total_revenue":     revenue,
+		"total_revenue":     revenue.Net,
+		"gross_revenue":     revenue.Gross,
+		"platform_fees":     revenue.PlatformFee,
 		"total_subscribers": subscribers,
 	}, nil
 }
@@ -110,11 +113,11 @@ func (uc *analyticsUseCase) GetPostStats(postID, creatorID string) (map[string]i
 	}, nil
 }
 
-func (uc *analyticsUseCase) GetRevenue(creatorID string) (int, error) {
+func (uc *analyticsUseCase) GetRevenue(creatorID string) (*entity.Revenue, error) {
 	revenue, err := uc.analyticsRepo.GetCreatorRevenue(creatorID)
 	if err != nil {
 		uc.logger.Error("Failed to get revenue: %v", err)
-		return 0, fmt.Errorf("failed to get revenue: %w", err)
+		return nil, fmt.Errorf("failed to get revenue: %w", err)
 	}
 	return revenue, nil
 }
@@ -37,9 +37,9 @@ func main() {
 		panic(err)
 	}
 
-	// Validate JWT_SECRET for services that use JWT
-	if cfg.JWTSecret == "your-secret-key-change-in-production" || cfg.JWTSecret == "" {
-		panic("JWT_SECRET must be set in environment variables")
+	// Only the auth service holds signing keys
+	if cfg.JWTKeyID == "" {
+		panic("JWT_KEY_ID must be set in environment variables")
 	}
 
 	application, err := app.NewApp(cfg)
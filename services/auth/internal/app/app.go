@@ -2,9 +2,11 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,9 +15,13 @@ import (
 	"lick-scroll/pkg/database"
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/mailer"
+	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/models"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/pkg/s3"
 	authHTTP "lick-scroll/services/auth/internal/controller/http"
+	authCache "lick-scroll/services/auth/internal/repo/cache"
 	"lick-scroll/services/auth/internal/repo/persistent"
 	"lick-scroll/services/auth/internal/usecase"
 
@@ -29,6 +35,17 @@ import (
 	_ "lick-scroll/services/auth/docs" // Swagger docs
 )
 
+// Each address can be sent this many verification or password reset emails
+// per window.
+const (
+	emailsPerAddress = 3
+	emailWindow      = time.Hour
+)
+
+// loginRequestsPerMinute caps login requests per client address on top of
+// the lockouts for failed logins.
+const loginRequestsPerMinute = 30
+
 type App struct {
 	cfg        *config.Config
 	log        *logger.Logger
@@ -36,7 +53,10 @@ type App struct {
 	redisClient *redis.Client
 	s3Client   *s3.Client
 	jwtService *jwt.Service
+	jwks       *jwt.JWKS
+	denylist   *jwt.Denylist
 	queueClient *queue.Client
+	mailer     mailer.Mailer
 	httpServer *http.Server
 }
 
@@ -49,11 +69,11 @@ func NewApp(cfg *config.Config) (*App, error) {
 		return nil, err
 	}
 
+	// Redis holds the denylist of revoked sessions
 	redisClient, err := cache.NewRedisClient(cfg)
 	if err != nil {
 		log.Error("Failed to connect to redis: %v", err)
-		// Redis is optional for auth service
-		redisClient = nil
+		return nil, err
 	}
 
 	s3Client, err := s3.NewClient(cfg)
@@ -68,7 +88,40 @@ func NewApp(cfg *config.Config) (*App, error) {
 		queueClient = nil
 	}
 
-	jwtService := jwt.NewService(cfg.JWTSecret)
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Error("Failed to create mailer: %v", err)
+		return nil, err
+	}
+
+	keys, err := jwt.LoadKeyDir(cfg.JWTKeysDir)
+	if err != nil {
+		log.Error("Failed to load JWT keys: %v", err)
+		return nil, err
+	}
+
+	var signingKey *jwt.Key
+	for _, key := range keys {
+		if key.ID == cfg.JWTKeyID {
+			signingKey = key
+		}
+	}
+	if signingKey == nil {
+		return nil, fmt.Errorf("signing key %q not found in %s", cfg.JWTKeyID, cfg.JWTKeysDir)
+	}
+
+	keySet := jwt.NewStaticKeySet(keys...)
+	jwtService, err := jwt.NewIssuer(signingKey, keySet)
+	if err != nil {
+		log.Error("Failed to create JWT issuer: %v", err)
+		return nil, err
+	}
+
+	jwks, err := keySet.JWKS()
+	if err != nil {
+		log.Error("Failed to build JWKS: %v", err)
+		return nil, err
+	}
 
 	return &App{
 		cfg:         cfg,
@@ -77,18 +130,39 @@ func NewApp(cfg *config.Config) (*App, error) {
 		redisClient: redisClient,
 		s3Client:    s3Client,
 		jwtService:  jwtService,
+		jwks:        jwks,
+		denylist:    jwt.NewDenylist(redisClient),
 		queueClient: queueClient,
+		mailer:      mail,
 	}, nil
 }
 
 func (a *App) Run() error {
 	// Initialize repositories
 	userRepo := persistent.NewUserRepository(a.db)
+	sessionRepo := persistent.NewSessionRepository(a.db)
+	applicationRepo := persistent.NewCreatorApplicationRepository(a.db)
+	emailTokenRepo := persistent.NewEmailTokenRepository(a.db)
+	mfaRepo := persistent.NewMFARepository(a.db)
+	mfaChallengeRepo := authCache.NewMFAChallengeRepository(a.redisClient)
+	loginAttemptRepo := persistent.NewLoginAttemptRepository(a.db)
+	loginThrottleRepo := authCache.NewLoginThrottleRepository(a.redisClient)
 
 	// Initialize use cases
 	authUseCase := usecase.NewAuthUseCase(
 		userRepo,
+		sessionRepo,
+		applicationRepo,
+		emailTokenRepo,
+		mfaRepo,
+		mfaChallengeRepo,
+		loginAttemptRepo,
+		loginThrottleRepo,
 		a.jwtService,
+		a.denylist,
+		a.mailer,
+		cache.NewLimiter(a.redisClient, "auth:email_limit", emailsPerAddress, emailWindow),
+		a.cfg.AppURL,
 		a.s3Client,
 		a.queueClient,
 		a.log,
@@ -96,10 +170,17 @@ func (a *App) Run() error {
 
 	// Initialize HTTP handlers
 	authHandler := authHTTP.NewAuthHandler(authUseCase)
+	jwksHandler := authHTTP.NewJWKSHandler(a.jwks)
 
 	// Setup router
 	r := gin.Default()
 
+	// Login lockouts are counted per client address, so only configured
+	// proxies may set it through X-Forwarded-For
+	if err := r.SetTrustedProxies(parseList(a.cfg.TrustedProxies)); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
 	// CORS middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000"},
@@ -114,37 +195,43 @@ func (a *App) Run() error {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Public keys other services verify access tokens with
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
 	{
 		api.POST("/register", authHandler.Register)
-		api.POST("/login", authHandler.Login)
+		loginRateLimit := middleware.RateLimitMiddleware(a.redisClient, loginRequestsPerMinute, time.Minute)
+		api.POST("/login", loginRateLimit, authHandler.Login)
+		api.POST("/login/mfa", loginRateLimit, authHandler.CompleteMFALogin)
+		api.POST("/refresh", authHandler.Refresh)
+		api.POST("/verify-email", authHandler.VerifyEmail)
+		api.POST("/verify-email/resend", authHandler.ResendVerification)
+		api.POST("/password-reset/request", authHandler.RequestPasswordReset)
+		api.POST("/password-reset/confirm", authHandler.ResetPassword)
 
 		// Protected routes
 		protected := api.Group("")
-		protected.Use(func(c *gin.Context) {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader == "" {
-				c.JSON(401, gin.H{"error": "Authorization header required"})
-				c.Abort()
-				return
-			}
-
-			claims, err := a.jwtService.ValidateToken(authHeader[7:]) // Remove "Bearer "
-			if err != nil {
-				c.JSON(401, gin.H{"error": "Invalid token"})
-				c.Abort()
-				return
-			}
-
-			c.Set("user_id", claims.UserID)
-			c.Set("user_role", claims.Role)
-			c.Next()
-		})
+		protected.Use(middleware.AuthMiddleware(a.jwtService, a.denylist))
+
+		creatorOnly := middleware.RequireRole(models.RoleCreator)
+		moderatorOnly := middleware.RequireRole(models.RoleModerator)
+		// Moderators act only from sessions opened with a second factor
+		requireMFA := middleware.RequireMFA()
 		{
+			protected.POST("/logout", authHandler.Logout)
+			protected.POST("/logout/all", authHandler.LogoutAll)
+			protected.POST("/users/:user_id/deactivate", moderatorOnly, requireMFA, authHandler.DeactivateUser)
 			protected.GET("/me", authHandler.Me)
+			// Two-factor authentication
+			protected.GET("/mfa", authHandler.GetMFAStatus)
+			protected.POST("/mfa/totp/enroll", authHandler.EnrollTOTP)
+			protected.POST("/mfa/totp/confirm", authHandler.ConfirmTOTP)
+			protected.POST("/mfa/totp/disable", authHandler.DisableTOTP)
+			protected.POST("/mfa/recovery-codes", authHandler.RegenerateRecoveryCodes)
 			protected.GET("/user/:id", authHandler.GetUser)
 			protected.POST("/avatar", authHandler.UploadAvatar)
 			// Subscription endpoints
@@ -152,6 +239,16 @@ func (a *App) Run() error {
 			protected.POST("/users/:user_id/subscriptions/:creator_id", authHandler.Subscribe)
 			protected.DELETE("/users/:user_id/subscriptions/:creator_id", authHandler.Unsubscribe)
 			protected.GET("/users/:user_id/subscriptions/:creator_id/status", authHandler.GetSubscriptionStatus)
+			// Subscription tier endpoints
+			protected.POST("/tiers", creatorOnly, authHandler.CreateTier)
+			protected.DELETE("/tiers/:tier_id", authHandler.DeleteTier)
+			protected.GET("/users/:user_id/tiers", authHandler.GetCreatorTiers)
+			// Creator onboarding
+			protected.POST("/creator-applications", authHandler.ApplyForCreator)
+			protected.GET("/creator-applications/me", authHandler.GetMyCreatorApplication)
+			protected.GET("/creator-applications", moderatorOnly, requireMFA, authHandler.ListCreatorApplications)
+			protected.POST("/creator-applications/:id/approve", moderatorOnly, requireMFA, authHandler.ApproveCreatorApplication)
+			protected.POST("/creator-applications/:id/reject", moderatorOnly, requireMFA, authHandler.RejectCreatorApplication)
 		}
 	}
 
@@ -173,6 +270,17 @@ func (a *App) Run() error {
 	return nil
 }
 
+// parseList splits a comma separated setting, dropping empty entries.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func (a *App) Wait() {
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -195,10 +303,8 @@ func (a *App) Shutdown() error {
 	}
 
 	// Close Redis connection
-	if a.redisClient != nil {
-		if err := a.redisClient.Close(); err != nil {
-			a.log.Error("Error closing Redis: %v", err)
-		}
+	if err := a.redisClient.Close(); err != nil {
+		a.log.Error("Error closing Redis: %v", err)
 	}
 
 	// Shutdown server
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
-	"path/filepath"
+	"strconv"
 
+	"lick-scroll/pkg/upload"
 	"lick-scroll/services/auth/internal/entity"
 	"lick-scroll/services/auth/internal/usecase"
 
@@ -33,19 +36,85 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type RegisterResponse struct {
+	Message string       `json:"message"`
+	User    *entity.User `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type CreateTierRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Price       int    `json:"price" binding:"required,min=1"`
+}
+
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  *entity.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int          `json:"expires_in"`
+	User         *entity.User `json:"user"`
+}
+
+// MFAChallengeResponse is returned by login for users with two-factor
+// authentication; the session is opened by POST /login/mfa.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// clientInfo describes where the request comes from, for login lockouts
+// and audit.
+func clientInfo(c *gin.Context) entity.ClientInfo {
+	return entity.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// respondLoginLocked answers 429 with Retry-After if err is a login
+// lockout, and reports whether it was.
+func respondLoginLocked(c *gin.Context, err error) bool {
+	var locked *usecase.LoginLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": retryAfter})
+	return true
+}
+
+func newAuthResponse(user *entity.User, tokens *entity.Tokens) AuthResponse {
+	return AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	}
 }
 
 // Register godoc
 // @Summary      Register a new user
-// @Description  Register a new user with email, username, password and role
+// @Description  Register a new viewer account and email a verification link. The account can log in once the email is verified.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body RegisterRequest true "Registration data"
-// @Success      201  {object}  AuthResponse
+// @Success      201  {object}  RegisterResponse
 // @Failure      400  {object}  map[string]string
 // @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
@@ -57,7 +126,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authUseCase.Register(req.Email, req.Username, req.Password)
+	user, err := h.authUseCase.Register(req.Email, req.Username, req.Password)
 	if err != nil {
 		if err.Error() == "user with this email already exists" || err.Error() == "username already taken" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -67,23 +136,26 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, AuthResponse{
-		Token: token,
-		User:  user,
+	c.JSON(http.StatusCreated, RegisterResponse{
+		Message: "Check your email to verify the account",
+		User:    user,
 	})
 }
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user and return JWT token
+// @Description  Authenticate user and return a short-lived access token with a refresh token. Users with two-factor authentication get an MFA token instead, to exchange with a code at /login/mfa. Accounts with an unverified email get 403. Repeated failures lock out the account and the client's address with growing delays (429 with Retry-After).
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequest true "Login credentials"
 // @Success      200  {object}  AuthResponse
+// @Success      202  {object}  MFAChallengeResponse
 // @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
+// @Failure      429  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
 // @Router       /login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -92,20 +164,132 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authUseCase.Login(req.Email, req.Password)
+	result, err := h.authUseCase.Login(req.Email, req.Password, clientInfo(c))
+	if err != nil {
+		if respondLoginLocked(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "account is deactivated", "email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "failed to log in", "failed to generate token":
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if result.MFAToken != "" {
+		c.JSON(http.StatusAccepted, MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAToken,
+			ExpiresIn:   result.MFAExpiresIn,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result.User, result.Tokens))
+}
+
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Exchange a refresh token for a new access token and refresh token. Each refresh token works once; reusing one revokes the session.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "Refresh token"
+// @Success      200  {object}  entity.Tokens
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.authUseCase.Refresh(req.RefreshToken)
 	if err != nil {
-		if err.Error() == "account is deactivated" {
+		switch err.Error() {
+		case "invalid refresh token", "refresh token reuse detected":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case "account is deactivated":
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary      Log out
+// @Description  Revoke the current session. Its refresh token stops working and its access tokens are rejected immediately.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authUseCase.Logout(c.GetString("session_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutAll godoc
+// @Summary      Log out on all devices
+// @Description  Revoke every session of the current user, including this one
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.authUseCase.LogoutAll(c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out on all devices"})
+}
+
+// DeactivateUser godoc
+// @Summary      Deactivate a user
+// @Description  Block a user account and end all of its sessions. Moderators only.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id path string true "User ID"
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /users/{user_id}/deactivate [post]
+func (h *AuthHandler) DeactivateUser(c *gin.Context) {
+	if err := h.authUseCase.DeactivateUser(c.Param("user_id")); err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{
-		Token: token,
-		User:  user,
-	})
+	c.JSON(http.StatusOK, gin.H{"message": "User deactivated successfully"})
 }
 
 // Me godoc
@@ -137,15 +321,17 @@ func (h *AuthHandler) Me(c *gin.Context) {
 
 // UploadAvatar godoc
 // @Summary      Upload user avatar
-// @Description  Upload avatar image for the current user
+// @Description  Upload avatar image for the current user. The file type is detected from its content (jpg, png or gif, up to 5 MB) and must match the file extension.
 // @Tags         auth
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
 // @Param        avatar formData file true "Avatar image file"
 // @Success      200  {object}  entity.User
-// @Failure      400  {object}  map[string]string
+// @Failure      400  {object}  map[string]interface{}
 // @Failure      401  {object}  map[string]string
+// @Failure      413  {object}  map[string]interface{}
+// @Failure      415  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]string
 // @Router       /avatar [post]
 func (h *AuthHandler) UploadAvatar(c *gin.Context) {
@@ -161,9 +347,14 @@ func (h *AuthHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	ext := filepath.Ext(file.Filename)
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image format. Only jpg, jpeg, png, gif are allowed"})
+	avatar, err := upload.Validate(file, upload.Avatars)
+	if err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			c.JSON(uploadErr.Status, uploadErr.Body())
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process file"})
 		return
 	}
 
@@ -174,13 +365,8 @@ func (h *AuthHandler) UploadAvatar(c *gin.Context) {
 	}
 	defer src.Close()
 
-	fileKey := fmt.Sprintf("avatars/%s/%s%s", userID.(string), uuid.New().String(), ext)
-	contentType := file.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "image/jpeg"
-	}
-
-	user, err := h.authUseCase.UploadAvatar(userID.(string), src, fileKey, contentType)
+	fileKey := fmt.Sprintf("avatars/%s/%s%s", userID.(string), uuid.New().String(), avatar.Extension)
+	user, err := h.authUseCase.UploadAvatar(userID.(string), src, fileKey, avatar.ContentType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -261,9 +447,13 @@ func (h *AuthHandler) Subscribe(c *gin.Context) {
 	}
 
 	if err := h.authUseCase.Subscribe(userID, creatorID); err != nil {
-		if err.Error() == "already subscribed" {
+		switch err.Error() {
+		case "already subscribed":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
+		case "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -274,6 +464,7 @@ func (h *AuthHandler) Subscribe(c *gin.Context) {
 
 // Unsubscribe godoc
 // @Summary      Unsubscribe from a creator
+// @Description  Unfollow a creator. An active paid subscription is cancelled instead and ends with its current period.
 // @Tags         auth
 // @Security     BearerAuth
 // @Param        user_id path string true "User ID"
@@ -289,10 +480,18 @@ func (h *AuthHandler) Unsubscribe(c *gin.Context) {
 		return
 	}
 
-	if err := h.authUseCase.Unsubscribe(userID, creatorID); err != nil {
+	cancelled, err := h.authUseCase.Unsubscribe(userID, creatorID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if cancelled != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":            "Paid subscription cancelled, it ends with the current period",
+			"current_period_end": cancelled.CurrentPeriodEnd,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
 }
@@ -322,3 +521,83 @@ func (h *AuthHandler) GetSubscriptionStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"subscribed": subscribed})
 }
+
+// CreateTier godoc
+// @Summary      Create a subscription tier
+// @Description  Create a paid subscription tier with a monthly price in coins. Only creators can define tiers.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateTierRequest true "Tier data"
+// @Success      201  {object}  entity.SubscriptionTier
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /tiers [post]
+func (h *AuthHandler) CreateTier(c *gin.Context) {
+	var req CreateTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tier, err := h.authUseCase.CreateTier(c.GetString("user_id"), req.Name, req.Description, req.Price)
+	if err != nil {
+		if err.Error() == "tier price must be positive" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tier)
+}
+
+// GetCreatorTiers godoc
+// @Summary      Get creator subscription tiers
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id path string true "Creator ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /users/{user_id}/tiers [get]
+func (h *AuthHandler) GetCreatorTiers(c *gin.Context) {
+	tiers, err := h.authUseCase.GetCreatorTiers(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscription tiers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tiers": tiers, "count": len(tiers)})
+}
+
+// DeleteTier godoc
+// @Summary      Delete a subscription tier
+// @Description  Delete one of your subscription tiers. Existing subscribers are not charged again and lapse at the end of the paid period.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        tier_id path string true "Tier ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /tiers/{tier_id} [delete]
+func (h *AuthHandler) DeleteTier(c *gin.Context) {
+	if err := h.authUseCase.DeleteTier(c.Param("tier_id"), c.GetString("user_id")); err != nil {
+		switch err.Error() {
+		case "subscription tier not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "you can only delete your own subscription tiers":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subscription tier deleted successfully"})
+}
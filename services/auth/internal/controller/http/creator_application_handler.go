@@ -0,0 +1,187 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreatorApplicationRequest struct {
+	Message string `json:"message" binding:"max=2000"`
+}
+
+type RejectCreatorApplicationRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ApplyForCreator godoc
+// @Summary      Apply to become a creator
+// @Description  Submit a creator application for moderator review. Viewers only; one pending application at a time. Once approved, refresh the access token to get the creator role.
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreatorApplicationRequest false "Application"
+// @Success      201  {object}  entity.CreatorApplication
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /creator-applications [post]
+func (h *AuthHandler) ApplyForCreator(c *gin.Context) {
+	var req CreatorApplicationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	application, err := h.authUseCase.ApplyForCreator(c.GetString("user_id"), req.Message)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "only viewers can apply to become creators":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "creator application already pending":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, application)
+}
+
+// GetMyCreatorApplication godoc
+// @Summary      Get my creator application
+// @Description  Get the current user's most recent creator application and its status
+// @Tags         creators
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  entity.CreatorApplication
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /creator-applications/me [get]
+func (h *AuthHandler) GetMyCreatorApplication(c *gin.Context) {
+	application, err := h.authUseCase.GetCreatorApplication(c.GetString("user_id"))
+	if err != nil {
+		if err.Error() == "creator application not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// ListCreatorApplications godoc
+// @Summary      List pending creator applications
+// @Description  Get the creator applications waiting for review, oldest first. Moderators only.
+// @Tags         creators
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Number of applications to return (max 100)"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /creator-applications [get]
+func (h *AuthHandler) ListCreatorApplications(c *gin.Context) {
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	applications, err := h.authUseCase.ListCreatorApplications(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"applications": applications, "count": len(applications)})
+}
+
+// ApproveCreatorApplication godoc
+// @Summary      Approve a creator application
+// @Description  Approve a pending application; the applicant becomes a creator and is notified. Moderators only.
+// @Tags         creators
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Application ID"
+// @Success      200  {object}  entity.CreatorApplication
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /creator-applications/{id}/approve [post]
+func (h *AuthHandler) ApproveCreatorApplication(c *gin.Context) {
+	application, err := h.authUseCase.ApproveCreatorApplication(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		respondCreatorApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// RejectCreatorApplication godoc
+// @Summary      Reject a creator application
+// @Description  Reject a pending application with a reason. The applicant is notified and can apply again. Moderators only.
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Application ID"
+// @Param        request body RejectCreatorApplicationRequest true "Rejection reason"
+// @Success      200  {object}  entity.CreatorApplication
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /creator-applications/{id}/reject [post]
+func (h *AuthHandler) RejectCreatorApplication(c *gin.Context) {
+	var req RejectCreatorApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	application, err := h.authUseCase.RejectCreatorApplication(c.Param("id"), c.GetString("user_id"), req.Reason)
+	if err != nil {
+		respondCreatorApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func respondCreatorApplicationError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "creator application not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "creator application is not pending":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "rejection reason is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// emailSentMessage is the same whether or not the address is registered.
+const emailSentMessage = "If the address belongs to an account, an email is on its way"
+
+// ResendVerification godoc
+// @Summary      Resend the verification email
+// @Description  Email a new verification link; earlier links stop working. The response doesn't reveal whether the address is registered. Limited per address.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body EmailRequest true "Email address"
+// @Success      202  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUseCase.ResendVerification(req.Email); err != nil {
+		respondEmailError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": emailSentMessage})
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirm the email address with the token from the verification link. Each token works once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyEmailRequest true "Verification token"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUseCase.VerifyEmail(req.Token); err != nil {
+		respondEmailError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// RequestPasswordReset godoc
+// @Summary      Request a password reset
+// @Description  Email a password reset link valid for one hour. The response doesn't reveal whether the address is registered. Limited per address.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body EmailRequest true "Email address"
+// @Success      202  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /password-reset/request [post]
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUseCase.RequestPasswordReset(req.Email); err != nil {
+		respondEmailError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": emailSentMessage})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token from the reset link. Each token works once; every session of the user is logged out.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResetPasswordRequest true "Reset token and new password"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /password-reset/confirm [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUseCase.ResetPassword(req.Token, req.Password); err != nil {
+		respondEmailError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset, log in with the new password"})
+}
+
+func respondEmailError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid or expired token":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "too many emails requested, try again later":
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case "account is deactivated":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"lick-scroll/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge lets clients and proxies cache the key set briefly. Services
+// refetch it on their own when they see an unknown kid.
+const jwksMaxAge = "public, max-age=300"
+
+type JWKSHandler struct {
+	jwks *jwt.JWKS
+}
+
+func NewJWKSHandler(jwks *jwt.JWKS) *JWKSHandler {
+	return &JWKSHandler{jwks: jwks}
+}
+
+// GetJWKS serves the public keys for verifying access tokens. It is mounted
+// at /.well-known/jwks.json, outside the documented /api/v1 base path.
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, h.jwks)
+}
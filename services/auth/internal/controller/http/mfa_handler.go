@@ -0,0 +1,198 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// CompleteMFALogin godoc
+// @Summary      Finish login with a second factor
+// @Description  Exchange the MFA token from /login and a code from the authenticator app, or a recovery code, for a session. The MFA token lives 5 minutes and allows 5 wrong codes. Wrong codes count towards the same lockout as wrong passwords.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body MFALoginRequest true "MFA token and code"
+// @Success      200  {object}  AuthResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      429  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /login/mfa [post]
+func (h *AuthHandler) CompleteMFALogin(c *gin.Context) {
+	var req MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authUseCase.CompleteMFALogin(req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		if respondLoginLocked(c, err) {
+			return
+		}
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result.User, result.Tokens))
+}
+
+// GetMFAStatus godoc
+// @Summary      Get two-factor status
+// @Description  Whether two-factor authentication is on, whether the user's role requires it, and how many recovery codes are left
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  entity.MFAStatus
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /mfa [get]
+func (h *AuthHandler) GetMFAStatus(c *gin.Context) {
+	status, err := h.authUseCase.GetMFAStatus(c.GetString("user_id"))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollTOTP godoc
+// @Summary      Start authenticator enrollment
+// @Description  Generate a secret for an authenticator app. Two-factor authentication turns on once the secret is confirmed with a code; enrolling again before that replaces the secret.
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  entity.TOTPSetup
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /mfa/totp/enroll [post]
+func (h *AuthHandler) EnrollTOTP(c *gin.Context) {
+	setup, err := h.authUseCase.EnrollTOTP(c.GetString("user_id"))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// ConfirmTOTP godoc
+// @Summary      Confirm authenticator enrollment
+// @Description  Turn two-factor authentication on with a code from the authenticator app. Returns recovery codes, shown only once. Refresh the access token afterwards to use routes that require two-factor authentication.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body MFACodeRequest true "Authenticator code"
+// @Success      200  {object}  RecoveryCodesResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /mfa/totp/confirm [post]
+func (h *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.authUseCase.ConfirmTOTP(c.GetString("user_id"), c.GetString("session_id"), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary      Turn two-factor authentication off
+// @Description  Turn two-factor authentication off with a current authenticator or recovery code. Sessions lose access to routes that require two-factor authentication.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body MFACodeRequest true "Authenticator or recovery code"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /mfa/totp/disable [post]
+func (h *AuthHandler) DisableTOTP(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUseCase.DisableTOTP(c.GetString("user_id"), req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes with new ones, using a current authenticator or recovery code. The new codes are shown only once.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body MFACodeRequest true "Authenticator or recovery code"
+// @Success      200  {object}  RecoveryCodesResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /mfa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.authUseCase.RegenerateRecoveryCodes(c.GetString("user_id"), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func respondMFAError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid code", "invalid or expired mfa token":
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case "account is deactivated":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "two-factor authentication already enabled":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "two-factor authentication not enrolled", "two-factor authentication not enabled":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type CreatorApplicationStatus string
+
+const (
+	CreatorApplicationPending  CreatorApplicationStatus = "pending"
+	CreatorApplicationApproved CreatorApplicationStatus = "approved"
+	CreatorApplicationRejected CreatorApplicationStatus = "rejected"
+)
+
+// CreatorApplication is a viewer's request to become a creator. Approving it
+// changes the user's role; the new role reaches the access token on the next
+// refresh.
+type CreatorApplication struct {
+	ID         string                   `json:"id"`
+	UserID     string                   `json:"user_id"`
+	Message    string                   `json:"message"`
+	Status     CreatorApplicationStatus `json:"status"`
+	ReviewerID string                   `json:"reviewer_id,omitempty"`
+	Reason     string                   `json:"reason,omitempty"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+	ReviewedAt *time.Time               `json:"reviewed_at,omitempty"`
+}
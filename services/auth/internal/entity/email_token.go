@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type EmailTokenPurpose string
+
+const (
+	EmailTokenVerifyEmail   EmailTokenPurpose = "verify_email"
+	EmailTokenResetPassword EmailTokenPurpose = "reset_password"
+)
+
+// EmailToken backs a link sent by email. It works once, until it expires or
+// a newer token for the same purpose is sent. Only a hash of the token is
+// stored.
+type EmailToken struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"user_id"`
+	Purpose   EmailTokenPurpose `json:"purpose"`
+	TokenHash string            `json:"-"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	UsedAt    *time.Time        `json:"used_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type LoginOutcome string
+
+const (
+	LoginSucceeded          LoginOutcome = "success"
+	LoginMFARequired        LoginOutcome = "mfa_required"
+	LoginInvalidCredentials LoginOutcome = "invalid_credentials"
+	LoginInvalidMFACode     LoginOutcome = "invalid_mfa_code"
+	LoginLocked             LoginOutcome = "locked"
+	LoginDeactivated        LoginOutcome = "deactivated"
+	LoginEmailNotVerified   LoginOutcome = "email_not_verified"
+)
+
+// ClientInfo identifies where a login comes from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginAttempt is an audit record of one login step. UserID is empty when
+// the email doesn't belong to an account.
+type LoginAttempt struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"user_id,omitempty"`
+	Email     string       `json:"email"`
+	IPAddress string       `json:"ip_address"`
+	UserAgent string       `json:"user_agent"`
+	Outcome   LoginOutcome `json:"outcome"`
+	CreatedAt time.Time    `json:"created_at"`
+}
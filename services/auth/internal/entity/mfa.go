@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// TOTPEnrollment is a user's authenticator secret. Two-factor authentication
+// is on once the enrollment is confirmed with a valid code.
+type TOTPEnrollment struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (e *TOTPEnrollment) Confirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+// TOTPSetup is shown once when enrolling: the secret for manual entry and
+// the otpauth:// URI for QR codes.
+type TOTPSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAStatus describes a user's two-factor settings. Required is set for
+// roles that need a second factor to withdraw funds or moderate.
+type MFAStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// LoginResult is either a session or, for users with two-factor
+// authentication, a pending login to finish with a code.
+type LoginResult struct {
+	User         *User
+	Tokens       *Tokens
+	MFAToken     string
+	MFAExpiresIn int
+}
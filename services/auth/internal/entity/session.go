@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// RefreshToken is one link in the rotation chain of a session. Only a hash
+// of the token is stored.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	SessionID string     `json:"session_id"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	MFA       bool       `json:"mfa"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Tokens are issued on login and rotated on every refresh. ExpiresIn is the
+// lifetime of the access token in seconds.
+type Tokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
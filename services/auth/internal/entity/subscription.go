@@ -2,10 +2,36 @@ package entity
 
 import "time"
 
+type SubscriptionType string
+
+const (
+	SubscriptionTypeFree   SubscriptionType = "free"
+	SubscriptionTypePaid   SubscriptionType = "paid"
+	SubscriptionTypeLapsed SubscriptionType = "lapsed"
+)
+
+// Subscription is a follow, optionally paid. CancelAtPeriodEnd marks a paid
+// subscription the viewer unfollowed; it is removed when its period ends.
 type Subscription struct {
-	ID        string    `json:"id"`
-	ViewerID  string    `json:"viewer_id"`
-	CreatorID string    `json:"creator_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                string           `json:"id"`
+	ViewerID          string           `json:"viewer_id"`
+	CreatorID         string           `json:"creator_id"`
+	Type              SubscriptionType `json:"type"`
+	TierID            string           `json:"tier_id,omitempty"`
+	CurrentPeriodEnd  *time.Time       `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd bool             `json:"cancel_at_period_end"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// SubscriptionTier is a paid plan offered by a creator. Price is charged in
+// coins once per monthly period.
+type SubscriptionTier struct {
+	ID          string    `json:"id"`
+	CreatorID   string    `json:"creator_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       int       `json:"price"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
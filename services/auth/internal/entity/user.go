@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type UserRole string
 
@@ -10,14 +13,36 @@ const (
 	RoleModerator UserRole = "moderator"
 )
 
+// PlatformUserID is the system account that owns the platform fee wallet. It
+// is created by the add_platform_fees migration, can't log in and is never
+// shown as a user.
+const PlatformUserID = "00000000-0000-0000-0000-000000000001"
+
+// reservedUsernames belong to system accounts and can't be registered.
+var reservedUsernames = map[string]bool{
+	"platform": true,
+}
+
+// IsReservedUsername reports whether username is kept for a system account.
+func IsReservedUsername(username string) bool {
+	return reservedUsernames[strings.ToLower(username)]
+}
+
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	Password  string    `json:"-"`
-	AvatarURL string    `json:"avatar_url"`
-	Role      UserRole  `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	Password        string     `json:"-"`
+	AvatarURL       string     `json:"avatar_url"`
+	Role            UserRole   `json:"role"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// EmailVerified reports whether the user confirmed they own their email
+// address.
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreatorApplicationModel struct {
+	ID         string     `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Message    string     `gorm:"type:text;not null" json:"message"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ReviewerID *string    `gorm:"type:uuid" json:"reviewer_id,omitempty"`
+	Reason     string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+func (CreatorApplicationModel) TableName() string {
+	return "creator_applications"
+}
+
+func (a *CreatorApplicationModel) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
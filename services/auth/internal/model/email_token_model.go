@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmailTokenModel struct {
+	ID        string     `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   string     `gorm:"type:varchar(20);not null" json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (EmailTokenModel) TableName() string {
+	return "email_tokens"
+}
+
+func (t *EmailTokenModel) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
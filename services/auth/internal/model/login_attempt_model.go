@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptModel struct {
+	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    *string   `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Email     string    `gorm:"type:varchar(255);not null" json:"email"`
+	IPAddress string    `gorm:"type:varchar(45);not null" json:"ip_address"`
+	UserAgent string    `gorm:"type:varchar(512);not null" json:"user_agent"`
+	Outcome   string    `gorm:"type:varchar(32);not null" json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (LoginAttemptModel) TableName() string {
+	return "login_attempts"
+}
+
+func (a *LoginAttemptModel) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
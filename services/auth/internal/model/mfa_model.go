@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TOTPModel struct {
+	UserID       string     `gorm:"type:uuid;primary_key" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (TOTPModel) TableName() string {
+	return "user_totp"
+}
+
+type RecoveryCodeModel struct {
+	ID        string     `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (RecoveryCodeModel) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+func (c *RecoveryCodeModel) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenModel struct {
+	ID        string     `gorm:"type:uuid;primary_key" json:"id"`
+	SessionID string     `gorm:"type:uuid;not null;index" json:"session_id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	MFA       bool       `gorm:"not null;default:false" json:"mfa"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+func (t *RefreshTokenModel) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
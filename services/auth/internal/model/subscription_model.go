@@ -8,12 +8,16 @@ import (
 )
 
 type SubscriptionModel struct {
-	ID        string         `gorm:"type:uuid;primary_key" json:"id"`
-	ViewerID  string         `gorm:"type:uuid;not null;index" json:"viewer_id"`
-	CreatorID string         `gorm:"type:uuid;not null;index" json:"creator_id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                string         `gorm:"type:uuid;primary_key" json:"id"`
+	ViewerID          string         `gorm:"type:uuid;not null;index" json:"viewer_id"`
+	CreatorID         string         `gorm:"type:uuid;not null;index" json:"creator_id"`
+	Type              string         `gorm:"type:varchar(10);default:'free'" json:"type"`
+	TierID            *string        `gorm:"type:uuid" json:"tier_id,omitempty"`
+	CurrentPeriodEnd  *time.Time     `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd bool           `gorm:"not null;default:false" json:"cancel_at_period_end"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (SubscriptionModel) TableName() string {
@@ -26,3 +30,25 @@ func (s *SubscriptionModel) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+type SubscriptionTierModel struct {
+	ID          string         `gorm:"type:uuid;primary_key" json:"id"`
+	CreatorID   string         `gorm:"type:uuid;not null;index" json:"creator_id"`
+	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	Price       int            `gorm:"not null" json:"price"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (SubscriptionTierModel) TableName() string {
+	return "subscription_tiers"
+}
+
+func (t *SubscriptionTierModel) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
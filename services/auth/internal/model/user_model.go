@@ -8,16 +8,17 @@ import (
 )
 
 type UserModel struct {
-	ID        string         `gorm:"type:uuid;primary_key" json:"id"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Username  string         `gorm:"uniqueIndex;not null" json:"username"`
-	Password  string         `gorm:"not null" json:"-"`
-	AvatarURL string         `gorm:"type:varchar(500)" json:"avatar_url"`
-	Role      string         `gorm:"type:varchar(20);default:'viewer'" json:"role"`
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              string         `gorm:"type:uuid;primary_key" json:"id"`
+	Email           string         `gorm:"uniqueIndex;not null" json:"email"`
+	Username        string         `gorm:"uniqueIndex;not null" json:"username"`
+	Password        string         `gorm:"not null" json:"-"`
+	AvatarURL       string         `gorm:"type:varchar(500)" json:"avatar_url"`
+	Role            string         `gorm:"type:varchar(20);default:'viewer'" json:"role"`
+	IsActive        bool           `gorm:"default:true" json:"is_active"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (UserModel) TableName() string {
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginThrottleRepository counts failed logins per key (an account or a
+// client address) and remembers until when a key is locked out. The caller
+// decides when to lock and for how long.
+type LoginThrottleRepository interface {
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	Fail(ctx context.Context, key string, ttl time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time, ttl time.Duration) error
+	Reset(ctx context.Context, key string) error
+}
+
+type loginThrottleRepository struct {
+	redisClient *redis.Client
+}
+
+func NewLoginThrottleRepository(redisClient *redis.Client) LoginThrottleRepository {
+	return &loginThrottleRepository{redisClient: redisClient}
+}
+
+func loginThrottleKey(key string) string {
+	return fmt.Sprintf("auth:login_throttle:%s", key)
+}
+
+// LockedUntil returns the end of the key's lockout, or the zero time if it
+// was never locked.
+func (r *loginThrottleRepository) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	until, err := r.redisClient.HGet(ctx, loginThrottleKey(key), "locked_until").Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(until), nil
+}
+
+// Fail counts a failure and returns the failures so far. The count is
+// forgotten ttl after the latest failure.
+func (r *loginThrottleRepository) Fail(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	redisKey := loginThrottleKey(key)
+	pipe := r.redisClient.TxPipeline()
+	failures := pipe.HIncrBy(ctx, redisKey, "failures", 1)
+	pipe.Expire(ctx, redisKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(failures.Val()), nil
+}
+
+func (r *loginThrottleRepository) Lock(ctx context.Context, key string, until time.Time, ttl time.Duration) error {
+	redisKey := loginThrottleKey(key)
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, redisKey, "locked_until", until.UnixMilli())
+	pipe.Expire(ctx, redisKey, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Reset forgets the key's failures and lockout.
+func (r *loginThrottleRepository) Reset(ctx context.Context, key string) error {
+	return r.redisClient.Del(ctx, loginThrottleKey(key)).Err()
+}
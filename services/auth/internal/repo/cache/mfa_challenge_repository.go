@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrMFAChallengeNotFound = errors.New("mfa challenge not found")
+
+// failScript only counts failures of a live challenge; a plain HINCRBY would
+// recreate an expired one without a TTL.
+var failScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "failures", 1)
+`)
+
+// MFAChallengeRepository keeps password logins that still need a second
+// factor. A challenge is keyed by the hash of the token handed to the client
+// and expires on its own.
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, tokenHash, userID string, ttl time.Duration) error
+	Get(ctx context.Context, tokenHash string) (string, error)
+	Fail(ctx context.Context, tokenHash string) (int, error)
+	Delete(ctx context.Context, tokenHash string) (bool, error)
+}
+
+type mfaChallengeRepository struct {
+	redisClient *redis.Client
+}
+
+func NewMFAChallengeRepository(redisClient *redis.Client) MFAChallengeRepository {
+	return &mfaChallengeRepository{redisClient: redisClient}
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("auth:mfa_pending:%s", tokenHash)
+}
+
+func (r *mfaChallengeRepository) Create(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	key := mfaChallengeKey(tokenHash)
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", userID, "failures", 0)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Get returns the ID of the user the challenge was issued to.
+func (r *mfaChallengeRepository) Get(ctx context.Context, tokenHash string) (string, error) {
+	userID, err := r.redisClient.HGet(ctx, mfaChallengeKey(tokenHash), "user_id").Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrMFAChallengeNotFound
+	}
+	return userID, err
+}
+
+// Fail counts a wrong code and returns the number of failures so far.
+func (r *mfaChallengeRepository) Fail(ctx context.Context, tokenHash string) (int, error) {
+	failures, err := failScript.Run(ctx, r.redisClient, []string{mfaChallengeKey(tokenHash)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if failures < 0 {
+		return 0, ErrMFAChallengeNotFound
+	}
+	return failures, nil
+}
+
+// Delete ends the challenge and reports whether it still existed, so only one
+// of two concurrent completions wins.
+func (r *mfaChallengeRepository) Delete(ctx context.Context, tokenHash string) (bool, error) {
+	deleted, err := r.redisClient.Del(ctx, mfaChallengeKey(tokenHash)).Result()
+	return deleted > 0, err
+}
@@ -0,0 +1,139 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCreatorApplicationNotFound = errors.New("creator application not found")
+	ErrCreatorApplicationPending  = errors.New("creator application already pending")
+	ErrCreatorApplicationChanged  = errors.New("creator application changed")
+)
+
+type CreatorApplicationRepository interface {
+	Create(application *entity.CreatorApplication) error
+	Get(applicationID string) (*entity.CreatorApplication, error)
+	GetLatestByUser(userID string) (*entity.CreatorApplication, error)
+	ListByStatus(status entity.CreatorApplicationStatus, limit, offset int) ([]*entity.CreatorApplication, error)
+	Approve(applicationID, reviewerID string) (*entity.CreatorApplication, error)
+	Reject(applicationID, reviewerID, reason string) (*entity.CreatorApplication, error)
+}
+
+type creatorApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewCreatorApplicationRepository(db *gorm.DB) CreatorApplicationRepository {
+	return &creatorApplicationRepository{db: db}
+}
+
+// Create stores a pending application. The partial unique index on pending
+// applications turns a second concurrent one into ErrCreatorApplicationPending.
+func (r *creatorApplicationRepository) Create(application *entity.CreatorApplication) error {
+	applicationModel := ToCreatorApplicationModel(application)
+	if err := r.db.Create(applicationModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrCreatorApplicationPending
+		}
+		return err
+	}
+	*application = *ToCreatorApplicationEntity(applicationModel)
+	return nil
+}
+
+func (r *creatorApplicationRepository) Get(applicationID string) (*entity.CreatorApplication, error) {
+	return getCreatorApplication(r.db.Where("id = ?", applicationID))
+}
+
+func (r *creatorApplicationRepository) GetLatestByUser(userID string) (*entity.CreatorApplication, error) {
+	return getCreatorApplication(r.db.Where("user_id = ?", userID).Order("created_at DESC"))
+}
+
+// ListByStatus returns the oldest applications first so moderators work
+// through the queue in the order users applied.
+func (r *creatorApplicationRepository) ListByStatus(status entity.CreatorApplicationStatus, limit, offset int) ([]*entity.CreatorApplication, error) {
+	var applicationModels []model.CreatorApplicationModel
+	query := r.db.Where("status = ?", string(status)).Order("created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Find(&applicationModels).Error; err != nil {
+		return nil, err
+	}
+
+	applications := make([]*entity.CreatorApplication, len(applicationModels))
+	for i := range applicationModels {
+		applications[i] = ToCreatorApplicationEntity(&applicationModels[i])
+	}
+	return applications, nil
+}
+
+// Approve accepts a pending application and makes the applicant a creator in
+// the same transaction.
+func (r *creatorApplicationRepository) Approve(applicationID, reviewerID string) (*entity.CreatorApplication, error) {
+	var application *entity.CreatorApplication
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := reviewCreatorApplication(tx, applicationID, entity.CreatorApplicationApproved, reviewerID, "")
+		if err != nil {
+			return err
+		}
+		if application, err = getCreatorApplication(tx.Where("id = ?", applicationID)); err != nil {
+			return err
+		}
+		return tx.Model(&model.UserModel{}).
+			Where("id = ?", application.UserID).
+			Update("role", string(entity.RoleCreator)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (r *creatorApplicationRepository) Reject(applicationID, reviewerID, reason string) (*entity.CreatorApplication, error) {
+	if err := reviewCreatorApplication(r.db, applicationID, entity.CreatorApplicationRejected, reviewerID, reason); err != nil {
+		return nil, err
+	}
+	return r.Get(applicationID)
+}
+
+func getCreatorApplication(query *gorm.DB) (*entity.CreatorApplication, error) {
+	var applicationModel model.CreatorApplicationModel
+	if err := query.First(&applicationModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCreatorApplicationNotFound
+		}
+		return nil, err
+	}
+	return ToCreatorApplicationEntity(&applicationModel), nil
+}
+
+// reviewCreatorApplication decides a pending application. Only one review can
+// win; the others get ErrCreatorApplicationChanged.
+func reviewCreatorApplication(tx *gorm.DB, applicationID string, status entity.CreatorApplicationStatus, reviewerID, reason string) error {
+	now := time.Now()
+	result := tx.Model(&model.CreatorApplicationModel{}).
+		Where("id = ? AND status = ?", applicationID, string(entity.CreatorApplicationPending)).
+		Updates(map[string]interface{}{
+			"status":      string(status),
+			"reviewer_id": reviewerID,
+			"reason":      reason,
+			"reviewed_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCreatorApplicationChanged
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrEmailTokenNotFound = errors.New("email token not found")
+
+type EmailTokenRepository interface {
+	Create(token *entity.EmailToken) error
+	Get(tokenHash string) (*entity.EmailToken, error)
+	Use(tokenID string) (bool, error)
+}
+
+type emailTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailTokenRepository(db *gorm.DB) EmailTokenRepository {
+	return &emailTokenRepository{db: db}
+}
+
+// Create stores a new token and uses up the user's earlier unused tokens for
+// the same purpose, so only the latest link in their inbox works.
+func (r *emailTokenRepository) Create(token *entity.EmailToken) error {
+	tokenModel := ToEmailTokenModel(token)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.EmailTokenModel{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, string(token.Purpose)).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(tokenModel).Error
+	})
+	if err != nil {
+		return err
+	}
+	*token = *ToEmailTokenEntity(tokenModel)
+	return nil
+}
+
+func (r *emailTokenRepository) Get(tokenHash string) (*entity.EmailToken, error) {
+	var tokenModel model.EmailTokenModel
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&tokenModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEmailTokenNotFound
+		}
+		return nil, err
+	}
+	return ToEmailTokenEntity(&tokenModel), nil
+}
+
+// Use claims the token. It reports false when the token was already used, so
+// two concurrent requests with the same link can't both succeed.
+func (r *emailTokenRepository) Use(tokenID string) (bool, error) {
+	result := r.db.Model(&model.EmailTokenModel{}).
+		Where("id = ? AND used_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
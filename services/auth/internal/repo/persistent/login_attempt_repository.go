@@ -0,0 +1,28 @@
+package persistent
+
+import (
+	"lick-scroll/services/auth/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Create(attempt *entity.LoginAttempt) error
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(attempt *entity.LoginAttempt) error {
+	attemptModel := ToLoginAttemptModel(attempt)
+	if err := r.db.Create(attemptModel).Error; err != nil {
+		return err
+	}
+	attempt.ID = attemptModel.ID
+	return nil
+}
@@ -11,15 +11,16 @@ func ToUserEntity(m *model.UserModel) *entity.User {
 	}
 
 	return &entity.User{
-		ID:        m.ID,
-		Email:     m.Email,
-		Username:  m.Username,
-		Password:  m.Password,
-		AvatarURL: m.AvatarURL,
-		Role:      entity.UserRole(m.Role),
-		IsActive:  m.IsActive,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:              m.ID,
+		Email:           m.Email,
+		Username:        m.Username,
+		Password:        m.Password,
+		AvatarURL:       m.AvatarURL,
+		Role:            entity.UserRole(m.Role),
+		IsActive:        m.IsActive,
+		EmailVerifiedAt: m.EmailVerifiedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
 	}
 }
 
@@ -29,42 +30,252 @@ func ToUserModel(e *entity.User) *model.UserModel {
 	}
 
 	return &model.UserModel{
+		ID:              e.ID,
+		Email:           e.Email,
+		Username:        e.Username,
+		Password:        e.Password,
+		AvatarURL:       e.AvatarURL,
+		Role:            string(e.Role),
+		IsActive:        e.IsActive,
+		EmailVerifiedAt: e.EmailVerifiedAt,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+}
+
+func ToSubscriptionEntity(m *model.SubscriptionModel) *entity.Subscription {
+	if m == nil {
+		return nil
+	}
+
+	subscription := &entity.Subscription{
+		ID:                m.ID,
+		ViewerID:          m.ViewerID,
+		CreatorID:         m.CreatorID,
+		Type:              entity.SubscriptionType(m.Type),
+		CurrentPeriodEnd:  m.CurrentPeriodEnd,
+		CancelAtPeriodEnd: m.CancelAtPeriodEnd,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+	if m.TierID != nil {
+		subscription.TierID = *m.TierID
+	}
+	return subscription
+}
+
+func ToSubscriptionModel(e *entity.Subscription) *model.SubscriptionModel {
+	if e == nil {
+		return nil
+	}
+
+	subscriptionModel := &model.SubscriptionModel{
+		ID:                e.ID,
+		ViewerID:          e.ViewerID,
+		CreatorID:         e.CreatorID,
+		Type:              string(e.Type),
+		CurrentPeriodEnd:  e.CurrentPeriodEnd,
+		CancelAtPeriodEnd: e.CancelAtPeriodEnd,
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
+	}
+	if e.TierID != "" {
+		tierID := e.TierID
+		subscriptionModel.TierID = &tierID
+	}
+	return subscriptionModel
+}
+
+func ToSubscriptionTierEntity(m *model.SubscriptionTierModel) *entity.SubscriptionTier {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.SubscriptionTier{
+		ID:          m.ID,
+		CreatorID:   m.CreatorID,
+		Name:        m.Name,
+		Description: m.Description,
+		Price:       m.Price,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func ToSubscriptionTierModel(e *entity.SubscriptionTier) *model.SubscriptionTierModel {
+	if e == nil {
+		return nil
+	}
+
+	return &model.SubscriptionTierModel{
+		ID:          e.ID,
+		CreatorID:   e.CreatorID,
+		Name:        e.Name,
+		Description: e.Description,
+		Price:       e.Price,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func ToRefreshTokenEntity(m *model.RefreshTokenModel) *entity.RefreshToken {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.RefreshToken{
+		ID:        m.ID,
+		SessionID: m.SessionID,
+		UserID:    m.UserID,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		RevokedAt: m.RevokedAt,
+		MFA:       m.MFA,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func ToRefreshTokenModel(e *entity.RefreshToken) *model.RefreshTokenModel {
+	if e == nil {
+		return nil
+	}
+
+	return &model.RefreshTokenModel{
 		ID:        e.ID,
-		Email:     e.Email,
-		Username:  e.Username,
-		Password:  e.Password,
-		AvatarURL: e.AvatarURL,
-		Role:      string(e.Role),
-		IsActive:  e.IsActive,
+		SessionID: e.SessionID,
+		UserID:    e.UserID,
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		RevokedAt: e.RevokedAt,
+		MFA:       e.MFA,
 		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
 	}
 }
 
-func ToSubscriptionEntity(m *model.SubscriptionModel) *entity.Subscription {
+func ToCreatorApplicationEntity(m *model.CreatorApplicationModel) *entity.CreatorApplication {
+	if m == nil {
+		return nil
+	}
+
+	application := &entity.CreatorApplication{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Message:    m.Message,
+		Status:     entity.CreatorApplicationStatus(m.Status),
+		Reason:     m.Reason,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+		ReviewedAt: m.ReviewedAt,
+	}
+	if m.ReviewerID != nil {
+		application.ReviewerID = *m.ReviewerID
+	}
+	return application
+}
+
+func ToCreatorApplicationModel(e *entity.CreatorApplication) *model.CreatorApplicationModel {
+	if e == nil {
+		return nil
+	}
+
+	applicationModel := &model.CreatorApplicationModel{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		Message:    e.Message,
+		Status:     string(e.Status),
+		Reason:     e.Reason,
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+		ReviewedAt: e.ReviewedAt,
+	}
+	if e.ReviewerID != "" {
+		reviewerID := e.ReviewerID
+		applicationModel.ReviewerID = &reviewerID
+	}
+	return applicationModel
+}
+
+func ToEmailTokenEntity(m *model.EmailTokenModel) *entity.EmailToken {
 	if m == nil {
 		return nil
 	}
 
-	return &entity.Subscription{
+	return &entity.EmailToken{
 		ID:        m.ID,
-		ViewerID:  m.ViewerID,
-		CreatorID: m.CreatorID,
+		UserID:    m.UserID,
+		Purpose:   entity.EmailTokenPurpose(m.Purpose),
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
 		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
 	}
 }
 
-func ToSubscriptionModel(e *entity.Subscription) *model.SubscriptionModel {
+func ToEmailTokenModel(e *entity.EmailToken) *model.EmailTokenModel {
 	if e == nil {
 		return nil
 	}
 
-	return &model.SubscriptionModel{
+	return &model.EmailTokenModel{
 		ID:        e.ID,
-		ViewerID:  e.ViewerID,
-		CreatorID: e.CreatorID,
+		UserID:    e.UserID,
+		Purpose:   string(e.Purpose),
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func ToTOTPEnrollmentEntity(m *model.TOTPModel) *entity.TOTPEnrollment {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.TOTPEnrollment{
+		UserID:       m.UserID,
+		Secret:       m.Secret,
+		ConfirmedAt:  m.ConfirmedAt,
+		LastUsedStep: m.LastUsedStep,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func ToTOTPModel(e *entity.TOTPEnrollment) *model.TOTPModel {
+	if e == nil {
+		return nil
+	}
+
+	return &model.TOTPModel{
+		UserID:       e.UserID,
+		Secret:       e.Secret,
+		ConfirmedAt:  e.ConfirmedAt,
+		LastUsedStep: e.LastUsedStep,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func ToLoginAttemptModel(e *entity.LoginAttempt) *model.LoginAttemptModel {
+	if e == nil {
+		return nil
+	}
+
+	var userID *string
+	if e.UserID != "" {
+		userID = &e.UserID
+	}
+
+	return &model.LoginAttemptModel{
+		ID:        e.ID,
+		UserID:    userID,
+		Email:     e.Email,
+		IPAddress: e.IPAddress,
+		UserAgent: e.UserAgent,
+		Outcome:   string(e.Outcome),
 		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
 	}
 }
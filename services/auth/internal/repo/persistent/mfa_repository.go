@@ -0,0 +1,158 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTOTPNotFound = errors.New("totp enrollment not found")
+	ErrTOTPEnabled  = errors.New("totp already enabled")
+)
+
+type MFARepository interface {
+	SavePendingTOTP(userID, secret string) error
+	GetTOTP(userID string) (*entity.TOTPEnrollment, error)
+	ConfirmTOTP(userID string, step int64, codeHashes []string) (bool, error)
+	UseTOTPStep(userID string, step int64) (bool, error)
+	DeleteTOTP(userID string) error
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	UseRecoveryCode(userID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID string) (int, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// SavePendingTOTP starts an enrollment, replacing an unconfirmed one. A
+// confirmed enrollment has to be deleted first.
+func (r *mfaRepository) SavePendingTOTP(userID, secret string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.TOTPModel
+		err := tx.Where("user_id = ?", userID).First(&existing).Error
+		if err == nil && existing.ConfirmedAt != nil {
+			return ErrTOTPEnabled
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return tx.Save(ToTOTPModel(&entity.TOTPEnrollment{
+			UserID:    userID,
+			Secret:    secret,
+			CreatedAt: time.Now(),
+		})).Error
+	})
+}
+
+func (r *mfaRepository) GetTOTP(userID string) (*entity.TOTPEnrollment, error) {
+	var totpModel model.TOTPModel
+	if err := r.db.Where("user_id = ?", userID).First(&totpModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTOTPNotFound
+		}
+		return nil, err
+	}
+	return ToTOTPEnrollmentEntity(&totpModel), nil
+}
+
+// ConfirmTOTP turns a pending enrollment on and stores a fresh set of
+// recovery codes. It reports false if the enrollment was confirmed
+// concurrently or the code's step was already used.
+func (r *mfaRepository) ConfirmTOTP(userID string, step int64, codeHashes []string) (bool, error) {
+	confirmed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.TOTPModel{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND last_used_step < ?", userID, step).
+			Updates(map[string]interface{}{
+				"confirmed_at":   now,
+				"last_used_step": step,
+				"updated_at":     now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		confirmed = true
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	if err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// UseTOTPStep records that the code for step was used. It reports false for
+// a step at or before the last one used, so a code works once.
+func (r *mfaRepository) UseTOTPStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&model.TOTPModel{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteTOTP turns two-factor authentication off and drops the recovery
+// codes.
+func (r *mfaRepository) DeleteTOTP(userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.TOTPModel{}).Error
+	})
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode spends one recovery code. It reports false for unknown and
+// already used codes.
+func (r *mfaRepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	result := r.db.Model(&model.RecoveryCodeModel{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *mfaRepository) CountRecoveryCodes(userID string) (int, error) {
+	var count int64
+	err := r.db.Model(&model.RecoveryCodeModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCodeModel{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]model.RecoveryCodeModel, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = model.RecoveryCodeModel{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
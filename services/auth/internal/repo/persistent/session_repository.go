@@ -0,0 +1,108 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+type SessionRepository interface {
+	CreateRefreshToken(token *entity.RefreshToken) error
+	GetRefreshToken(tokenHash string) (*entity.RefreshToken, error)
+	MarkRefreshTokenUsed(tokenID string) (bool, error)
+	RevokeSession(sessionID string) error
+	RevokeUserSessions(userID string) ([]string, error)
+	SetSessionMFA(sessionID string) error
+	ClearUserMFA(userID string) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) CreateRefreshToken(token *entity.RefreshToken) error {
+	tokenModel := ToRefreshTokenModel(token)
+	if err := r.db.Create(tokenModel).Error; err != nil {
+		return err
+	}
+	*token = *ToRefreshTokenEntity(tokenModel)
+	return nil
+}
+
+func (r *sessionRepository) GetRefreshToken(tokenHash string) (*entity.RefreshToken, error) {
+	var tokenModel model.RefreshTokenModel
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&tokenModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return ToRefreshTokenEntity(&tokenModel), nil
+}
+
+// MarkRefreshTokenUsed claims the token for one rotation. It reports false
+// when the token was already used or revoked, so two concurrent refreshes
+// with the same token can't both succeed.
+func (r *sessionRepository) MarkRefreshTokenUsed(tokenID string) (bool, error) {
+	result := r.db.Model(&model.RefreshTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) RevokeSession(sessionID string) error {
+	return r.db.Model(&model.RefreshTokenModel{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions revokes every session of the user and returns the IDs
+// of the ones that were still active.
+func (r *sessionRepository) RevokeUserSessions(userID string) ([]string, error) {
+	var sessionIDs []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.RefreshTokenModel{}).
+			Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+			Distinct("session_id").
+			Pluck("session_id", &sessionIDs).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.RefreshTokenModel{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
+
+// SetSessionMFA marks the session as opened with a second factor; tokens it
+// refreshes from now on carry the mfa claim.
+func (r *sessionRepository) SetSessionMFA(sessionID string) error {
+	return r.db.Model(&model.RefreshTokenModel{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("mfa", true).Error
+}
+
+// ClearUserMFA drops the mfa claim from every session of the user.
+func (r *sessionRepository) ClearUserMFA(userID string) error {
+	return r.db.Model(&model.RefreshTokenModel{}).
+		Where("user_id = ? AND mfa", userID).
+		Update("mfa", false).Error
+}
@@ -1,6 +1,8 @@
 package persistent
 
 import (
+	"time"
+
 	"lick-scroll/services/auth/internal/entity"
 	"lick-scroll/services/auth/internal/model"
 
@@ -16,8 +18,13 @@ type UserRepository interface {
 	Update(user *entity.User) error
 	GetSubscriptions(userID string) ([]*entity.Subscription, error)
 	CreateSubscription(viewerID, creatorID string) error
-	DeleteSubscription(viewerID, creatorID string) error
+	DeleteSubscription(viewerID, creatorID string, now time.Time) error
+	CancelPaidSubscription(viewerID, creatorID string, now time.Time) (*entity.Subscription, error)
 	GetSubscription(viewerID, creatorID string) (*entity.Subscription, error)
+	CreateTier(tier *entity.SubscriptionTier) error
+	GetTier(tierID string) (*entity.SubscriptionTier, error)
+	GetTiersByCreator(creatorID string) ([]*entity.SubscriptionTier, error)
+	DeleteTier(tierID string) error
 }
 
 type userRepository struct {
@@ -99,12 +106,39 @@ func (r *userRepository) CreateSubscription(viewerID, creatorID string) error {
 		ID:        uuid.New().String(),
 		ViewerID:  viewerID,
 		CreatorID: creatorID,
+		Type:      string(entity.SubscriptionTypeFree),
 	}
 	return r.db.Create(subscriptionModel).Error
 }
 
-func (r *userRepository) DeleteSubscription(viewerID, creatorID string) error {
-	return r.db.Unscoped().Where("viewer_id = ? AND creator_id = ?", viewerID, creatorID).Delete(&model.SubscriptionModel{}).Error
+// DeleteSubscription removes the follow unless it is a paid subscription
+// whose period is still running; those are cancelled with
+// CancelPaidSubscription instead.
+func (r *userRepository) DeleteSubscription(viewerID, creatorID string, now time.Time) error {
+	return r.db.Unscoped().
+		Where("viewer_id = ? AND creator_id = ?", viewerID, creatorID).
+		Where("NOT (type = ? AND current_period_end > ?)", string(entity.SubscriptionTypePaid), now).
+		Delete(&model.SubscriptionModel{}).Error
+}
+
+// CancelPaidSubscription flags an active paid subscription to end with its
+// current period. It returns nil if the viewer has no active paid subscription
+// to the creator.
+func (r *userRepository) CancelPaidSubscription(viewerID, creatorID string, now time.Time) (*entity.Subscription, error) {
+	result := r.db.Model(&model.SubscriptionModel{}).
+		Where("viewer_id = ? AND creator_id = ? AND type = ? AND current_period_end > ?",
+			viewerID, creatorID, string(entity.SubscriptionTypePaid), now).
+		Updates(map[string]interface{}{
+			"cancel_at_period_end": true,
+			"updated_at":           now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return r.GetSubscription(viewerID, creatorID)
 }
 
 func (r *userRepository) GetSubscription(viewerID, creatorID string) (*entity.Subscription, error) {
@@ -115,3 +149,39 @@ func (r *userRepository) GetSubscription(viewerID, creatorID string) (*entity.Su
 	}
 	return ToSubscriptionEntity(&subscriptionModel), nil
 }
+
+func (r *userRepository) CreateTier(tier *entity.SubscriptionTier) error {
+	tierModel := ToSubscriptionTierModel(tier)
+	if err := r.db.Create(tierModel).Error; err != nil {
+		return err
+	}
+	*tier = *ToSubscriptionTierEntity(tierModel)
+	return nil
+}
+
+func (r *userRepository) GetTier(tierID string) (*entity.SubscriptionTier, error) {
+	var tierModel model.SubscriptionTierModel
+	if err := r.db.Where("id = ?", tierID).First(&tierModel).Error; err != nil {
+		return nil, err
+	}
+	return ToSubscriptionTierEntity(&tierModel), nil
+}
+
+func (r *userRepository) GetTiersByCreator(creatorID string) ([]*entity.SubscriptionTier, error) {
+	var tierModels []model.SubscriptionTierModel
+	if err := r.db.Where("creator_id = ?", creatorID).Order("price ASC").Find(&tierModels).Error; err != nil {
+		return nil, err
+	}
+
+	tiers := make([]*entity.SubscriptionTier, len(tierModels))
+	for i := range tierModels {
+		tiers[i] = ToSubscriptionTierEntity(&tierModels[i])
+	}
+	return tiers, nil
+}
+
+// DeleteTier soft-deletes the tier so existing subscriptions keep their
+// history; they lapse at the next renewal instead of being charged.
+func (r *userRepository) DeleteTier(tierID string) error {
+	return r.db.Delete(&model.SubscriptionTierModel{}, "id = ?", tierID).Error
+}
@@ -1,69 +1,155 @@
 package usecase
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"time"
 
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/mailer"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/pkg/s3"
 	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/repo/cache"
 	"lick-scroll/services/auth/internal/repo/persistent"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// refreshTokenTTL is how long a session can stay idle. Every refresh issues
+// a new token with a fresh lifetime.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// RateLimiter counts attempts per key; *cache.Limiter implements it.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// SessionDenylist rejects the access tokens of revoked sessions;
+// *jwt.Denylist implements it.
+type SessionDenylist interface {
+	Revoke(ctx context.Context, sessionID string) error
+}
+
 type AuthUseCase interface {
-	Register(email, username, password string) (*entity.User, string, error)
-	Login(email, password string) (*entity.User, string, error)
+	Register(email, username, password string) (*entity.User, error)
+	Login(email, password string, client entity.ClientInfo) (*entity.LoginResult, error)
+	CompleteMFALogin(mfaToken, code string, client entity.ClientInfo) (*entity.LoginResult, error)
+	EnrollTOTP(userID string) (*entity.TOTPSetup, error)
+	ConfirmTOTP(userID, sessionID, code string) ([]string, error)
+	DisableTOTP(userID, code string) error
+	RegenerateRecoveryCodes(userID, code string) ([]string, error)
+	GetMFAStatus(userID string) (*entity.MFAStatus, error)
+	ResendVerification(email string) error
+	VerifyEmail(token string) error
+	RequestPasswordReset(email string) error
+	ResetPassword(token, password string) error
+	Refresh(refreshToken string) (*entity.Tokens, error)
+	Logout(sessionID string) error
+	LogoutAll(userID string) error
+	DeactivateUser(userID string) error
 	GetUser(userID string) (*entity.User, error)
 	UploadAvatar(userID string, fileReader io.Reader, fileKey string, contentType string) (*entity.User, error)
 	GetSubscriptions(userID string) ([]*entity.Subscription, error)
 	Subscribe(viewerID, creatorID string) error
-	Unsubscribe(viewerID, creatorID string) error
+	Unsubscribe(viewerID, creatorID string) (*entity.Subscription, error)
 	GetSubscriptionStatus(viewerID, creatorID string) (bool, error)
+	CreateTier(creatorID, name, description string, price int) (*entity.SubscriptionTier, error)
+	GetCreatorTiers(creatorID string) ([]*entity.SubscriptionTier, error)
+	DeleteTier(tierID, creatorID string) error
+	ApplyForCreator(userID, message string) (*entity.CreatorApplication, error)
+	GetCreatorApplication(userID string) (*entity.CreatorApplication, error)
+	ListCreatorApplications(limit, offset int) ([]*entity.CreatorApplication, error)
+	ApproveCreatorApplication(applicationID, moderatorID string) (*entity.CreatorApplication, error)
+	RejectCreatorApplication(applicationID, moderatorID, reason string) (*entity.CreatorApplication, error)
 }
 
 type authUseCase struct {
-	userRepo   persistent.UserRepository
-	jwtService *jwt.Service
-	s3Client   *s3.Client
-	queueClient *queue.Client
-	logger     *logger.Logger
+	userRepo          persistent.UserRepository
+	sessionRepo       persistent.SessionRepository
+	applicationRepo   persistent.CreatorApplicationRepository
+	emailTokenRepo    persistent.EmailTokenRepository
+	mfaRepo           persistent.MFARepository
+	mfaChallengeRepo  cache.MFAChallengeRepository
+	loginAttemptRepo  persistent.LoginAttemptRepository
+	loginThrottleRepo cache.LoginThrottleRepository
+	jwtService        *jwt.Service
+	denylist          SessionDenylist
+	mailer            mailer.Mailer
+	emailLimiter      RateLimiter
+	appURL            string
+	s3Client          *s3.Client
+	queueClient       *queue.Client
+	logger            *logger.Logger
+	now               func() time.Time
 }
 
 func NewAuthUseCase(
 	userRepo persistent.UserRepository,
+	sessionRepo persistent.SessionRepository,
+	applicationRepo persistent.CreatorApplicationRepository,
+	emailTokenRepo persistent.EmailTokenRepository,
+	mfaRepo persistent.MFARepository,
+	mfaChallengeRepo cache.MFAChallengeRepository,
+	loginAttemptRepo persistent.LoginAttemptRepository,
+	loginThrottleRepo cache.LoginThrottleRepository,
 	jwtService *jwt.Service,
+	denylist SessionDenylist,
+	mailer mailer.Mailer,
+	emailLimiter RateLimiter,
+	appURL string,
 	s3Client *s3.Client,
 	queueClient *queue.Client,
 	logger *logger.Logger,
 ) AuthUseCase {
 	return &authUseCase{
-		userRepo:    userRepo,
-		jwtService:  jwtService,
-		s3Client:    s3Client,
-		queueClient: queueClient,
-		logger:      logger,
+		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
+		applicationRepo:   applicationRepo,
+		emailTokenRepo:    emailTokenRepo,
+		mfaRepo:           mfaRepo,
+		mfaChallengeRepo:  mfaChallengeRepo,
+		loginAttemptRepo:  loginAttemptRepo,
+		loginThrottleRepo: loginThrottleRepo,
+		jwtService:        jwtService,
+		denylist:          denylist,
+		mailer:            mailer,
+		emailLimiter:      emailLimiter,
+		appURL:            strings.TrimRight(appURL, "/"),
+		s3Client:          s3Client,
+		queueClient:       queueClient,
+		logger:            logger,
+		now:               time.Now,
 	}
 }
 
-func (uc *authUseCase) Register(email, username, password string) (*entity.User, string, error) {
+// Register creates an account and emails a verification link. The account
+// can log in once the address is verified.
+func (uc *authUseCase) Register(email, username, password string) (*entity.User, error) {
 	_, err := uc.userRepo.GetByEmail(email)
 	if err == nil {
-		return nil, "", fmt.Errorf("user with this email already exists")
+		return nil, fmt.Errorf("user with this email already exists")
 	}
 
 	_, err = uc.userRepo.GetByUsername(username)
-	if err == nil {
-		return nil, "", fmt.Errorf("username already taken")
+	if err == nil || entity.IsReservedUsername(username) {
+		return nil, fmt.Errorf("username already taken")
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		uc.logger.Error("Failed to hash password: %v", err)
-		return nil, "", fmt.Errorf("failed to process registration")
+		return nil, fmt.Errorf("failed to process registration")
 	}
 
 	user := &entity.User{
@@ -76,44 +162,226 @@ func (uc *authUseCase) Register(email, username, password string) (*entity.User,
 
 	if err := uc.userRepo.Create(user); err != nil {
 		uc.logger.Error("Failed to create user: %v", err)
-		return nil, "", fmt.Errorf("failed to create user")
+		return nil, fmt.Errorf("failed to create user")
 	}
 
-	token, err := uc.jwtService.GenerateToken(user.ID, string(user.Role))
-	if err != nil {
-		uc.logger.Error("Failed to generate token: %v", err)
-		return nil, "", fmt.Errorf("failed to generate token")
+	// The account exists either way; a failed email can be resent
+	if err := uc.sendEmailToken(user, entity.EmailTokenVerifyEmail); err != nil {
+		uc.logger.Error("Failed to send verification email to user %s: %v", user.ID, err)
 	}
 
 	user.Password = ""
-	return user, token, nil
+	return user, nil
 }
 
-func (uc *authUseCase) Login(email, password string) (*entity.User, string, error) {
+// Login checks the password. Users with two-factor authentication get an
+// MFA token instead of a session and finish with CompleteMFALogin. Repeated
+// failures lock out the account and the client's address for a while.
+func (uc *authUseCase) Login(email, password string, client entity.ClientInfo) (*entity.LoginResult, error) {
+	if err := uc.checkLoginLock(loginThrottleKeys(email, client)); err != nil {
+		uc.recordLoginAttempt(nil, email, client, entity.LoginLocked)
+		return nil, err
+	}
+
 	user, err := uc.userRepo.GetByEmail(email)
 	if err != nil {
-		return nil, "", fmt.Errorf("invalid credentials")
+		// Take as long as a wrong password would
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		uc.loginFailed(nil, email, client, entity.LoginInvalidCredentials)
+		return nil, fmt.Errorf("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", fmt.Errorf("invalid credentials")
+		uc.loginFailed(user, email, client, entity.LoginInvalidCredentials)
+		return nil, fmt.Errorf("invalid credentials")
 	}
 
 	if !user.IsActive {
-		return nil, "", fmt.Errorf("account is deactivated")
+		uc.recordLoginAttempt(user, email, client, entity.LoginDeactivated)
+		return nil, fmt.Errorf("account is deactivated")
 	}
 
-	token, err := uc.jwtService.GenerateToken(user.ID, string(user.Role))
+	if !user.EmailVerified() {
+		uc.recordLoginAttempt(user, email, client, entity.LoginEmailNotVerified)
+		return nil, fmt.Errorf("email not verified")
+	}
+
+	enrollment, err := uc.getTOTP(user.ID)
 	if err != nil {
-		uc.logger.Error("Failed to generate token: %v", err)
-		return nil, "", fmt.Errorf("failed to generate token")
+		return nil, fmt.Errorf("failed to log in")
+	}
+	if enrollment != nil && enrollment.Confirmed() {
+		// Failures stay counted until the second factor is right too
+		uc.recordLoginAttempt(user, email, client, entity.LoginMFARequired)
+		return uc.startMFALogin(user)
+	}
+
+	tokens, err := uc.issueTokens(user, uuid.New().String(), false)
+	if err != nil {
+		return nil, err
 	}
+	uc.loginSucceeded(user, email, client)
 
 	user.Password = ""
-	return user, token, nil
+	return &entity.LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// dummyPasswordHash is what passwords for unknown emails are checked
+// against.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Refresh rotates a refresh token: the token is used up and a new pair is
+// issued for the same session. Presenting a token that was already used
+// means it leaked, so the whole session is revoked.
+func (uc *authUseCase) Refresh(refreshToken string) (*entity.Tokens, error) {
+	stored, err := uc.sessionRepo.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, persistent.ErrRefreshTokenNotFound) {
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		uc.logger.Error("Failed to get refresh token: %v", err)
+		return nil, fmt.Errorf("failed to refresh token")
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	claimed := false
+	if stored.UsedAt == nil {
+		claimed, err = uc.sessionRepo.MarkRefreshTokenUsed(stored.ID)
+		if err != nil {
+			uc.logger.Error("Failed to use refresh token: %v", err)
+			return nil, fmt.Errorf("failed to refresh token")
+		}
+	}
+	if !claimed {
+		uc.logger.Warn("Refresh token reuse detected, revoking session %s of user %s", stored.SessionID, stored.UserID)
+		if err := uc.Logout(stored.SessionID); err != nil {
+			uc.logger.Error("Failed to revoke session %s: %v", stored.SessionID, err)
+		}
+		return nil, fmt.Errorf("refresh token reuse detected")
+	}
+
+	user, err := uc.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if !user.IsActive {
+		if err := uc.LogoutAll(user.ID); err != nil {
+			uc.logger.Error("Failed to revoke sessions of user %s: %v", user.ID, err)
+		}
+		return nil, fmt.Errorf("account is deactivated")
+	}
+
+	return uc.issueTokens(user, stored.SessionID, stored.MFA)
+}
+
+// Logout revokes one session: its refresh tokens can no longer be used and
+// its access tokens are rejected until they expire.
+func (uc *authUseCase) Logout(sessionID string) error {
+	if err := uc.sessionRepo.RevokeSession(sessionID); err != nil {
+		uc.logger.Error("Failed to revoke session %s: %v", sessionID, err)
+		return fmt.Errorf("failed to revoke session")
+	}
+	if err := uc.denylist.Revoke(context.Background(), sessionID); err != nil {
+		uc.logger.Error("Failed to add session %s to denylist: %v", sessionID, err)
+		return fmt.Errorf("failed to revoke session")
+	}
+	return nil
+}
+
+// LogoutAll revokes every session of the user.
+func (uc *authUseCase) LogoutAll(userID string) error {
+	sessionIDs, err := uc.sessionRepo.RevokeUserSessions(userID)
+	if err != nil {
+		uc.logger.Error("Failed to revoke sessions of user %s: %v", userID, err)
+		return fmt.Errorf("failed to revoke sessions")
+	}
+
+	ctx := context.Background()
+	for _, sessionID := range sessionIDs {
+		if err := uc.denylist.Revoke(ctx, sessionID); err != nil {
+			uc.logger.Error("Failed to add session %s to denylist: %v", sessionID, err)
+			return fmt.Errorf("failed to revoke sessions")
+		}
+	}
+	return nil
+}
+
+// DeactivateUser blocks the account and ends all of its sessions.
+func (uc *authUseCase) DeactivateUser(userID string) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	if user.IsActive {
+		user.IsActive = false
+		if err := uc.userRepo.Update(user); err != nil {
+			uc.logger.Error("Failed to deactivate user %s: %v", userID, err)
+			return fmt.Errorf("failed to update user")
+		}
+	}
+
+	return uc.LogoutAll(userID)
+}
+
+// issueTokens opens or continues a session. mfa records whether the session
+// was opened with a second factor.
+func (uc *authUseCase) issueTokens(user *entity.User, sessionID string, mfa bool) (*entity.Tokens, error) {
+	accessToken, err := uc.jwtService.GenerateToken(user.ID, string(user.Role), sessionID, mfa)
+	if err != nil {
+		uc.logger.Error("Failed to generate token: %v", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	refreshToken, err := newToken()
+	if err != nil {
+		uc.logger.Error("Failed to generate refresh token: %v", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	err = uc.sessionRepo.CreateRefreshToken(&entity.RefreshToken{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+		MFA:       mfa,
+	})
+	if err != nil {
+		uc.logger.Error("Failed to store refresh token: %v", err)
+		return nil, fmt.Errorf("failed to generate token")
+	}
+
+	return &entity.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(jwt.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// newToken returns a random opaque token for refresh tokens and email links.
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 func (uc *authUseCase) GetUser(userID string) (*entity.User, error) {
+	if userID == entity.PlatformUserID {
+		return nil, fmt.Errorf("user not found")
+	}
 	user, err := uc.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, err
@@ -123,23 +391,33 @@ func (uc *authUseCase) GetUser(userID string) (*entity.User, error) {
 }
 
 func (uc *authUseCase) UploadAvatar(userID string, fileReader io.Reader, fileKey string, contentType string) (*entity.User, error) {
-	avatarURL, err := uc.s3Client.UploadFile(fileKey, fileReader, contentType)
-	if err != nil {
+	if err := uc.s3Client.UploadFile(fileKey, fileReader, contentType); err != nil {
 		uc.logger.Error("Failed to upload avatar: %v", err)
 		return nil, fmt.Errorf("failed to upload avatar")
 	}
+	// Avatars live under the bucket's public prefix, so their URL is permanent
+	avatarURL := uc.s3Client.ObjectURL(fileKey)
 
 	user, err := uc.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
 
+	previousURL := user.AvatarURL
 	user.AvatarURL = avatarURL
 	if err := uc.userRepo.Update(user); err != nil {
 		uc.logger.Error("Failed to update user: %v", err)
 		return nil, fmt.Errorf("failed to update user")
 	}
 
+	// The replaced avatar isn't referenced anywhere else. Avatars from another
+	// endpoint are left to cmd/s3gc.
+	if previousKey := strings.TrimPrefix(previousURL, uc.s3Client.ObjectURL("")); previousKey != previousURL && strings.HasPrefix(previousKey, s3.PublicPrefix) {
+		if err := uc.s3Client.DeleteFile(previousKey); err != nil {
+			uc.logger.Error("Failed to delete previous avatar %s: %v", previousKey, err)
+		}
+	}
+
 	user.Password = ""
 	return user, nil
 }
@@ -148,7 +426,13 @@ func (uc *authUseCase) GetSubscriptions(userID string) ([]*entity.Subscription,
 	return uc.userRepo.GetSubscriptions(userID)
 }
 
+// Subscribe creates a free subscription (follow). Paid tiers are subscribed to
+// through the wallet service, which charges the viewer.
 func (uc *authUseCase) Subscribe(viewerID, creatorID string) error {
+	if creatorID == entity.PlatformUserID {
+		return fmt.Errorf("user not found")
+	}
+
 	existing, err := uc.userRepo.GetSubscription(viewerID, creatorID)
 	if err == nil && existing != nil && existing.ID != "" {
 		return fmt.Errorf("already subscribed")
@@ -176,17 +460,49 @@ func (uc *authUseCase) Subscribe(viewerID, creatorID string) error {
 				uc.logger.Info("[NOTIFICATION QUEUE] Successfully published subscription notification task to RabbitMQ")
 			}
 		}()
+		go uc.publishSubscriptionEvent(queue.EventSubscriptionCreated, viewerID, creatorID)
 	}
 
 	return nil
 }
 
-func (uc *authUseCase) Unsubscribe(viewerID, creatorID string) error {
-	if err := uc.userRepo.DeleteSubscription(viewerID, creatorID); err != nil {
+// Unsubscribe unfollows a creator. A paid subscription that has already been
+// charged for the current period is not removed: it is cancelled at the end of
+// the period, when the wallet service's renewal job removes it instead of
+// charging again. The cancelled subscription is returned in that case.
+func (uc *authUseCase) Unsubscribe(viewerID, creatorID string) (*entity.Subscription, error) {
+	now := uc.now()
+	cancelled, err := uc.userRepo.CancelPaidSubscription(viewerID, creatorID, now)
+	if err != nil {
+		uc.logger.Error("Failed to cancel paid subscription: %v", err)
+		return nil, fmt.Errorf("failed to unsubscribe")
+	}
+	if cancelled != nil {
+		return cancelled, nil
+	}
+
+	if err := uc.userRepo.DeleteSubscription(viewerID, creatorID, now); err != nil {
 		uc.logger.Error("Failed to delete subscription: %v", err)
-		return fmt.Errorf("failed to unsubscribe")
+		return nil, fmt.Errorf("failed to unsubscribe")
+	}
+
+	if uc.queueClient != nil {
+		go uc.publishSubscriptionEvent(queue.EventSubscriptionDeleted, viewerID, creatorID)
+	}
+	return nil, nil
+}
+
+// publishSubscriptionEvent lets the fanout service add or remove the
+// creator's posts in the viewer's timeline.
+func (uc *authUseCase) publishSubscriptionEvent(routingKey, viewerID, creatorID string) {
+	event := map[string]interface{}{
+		"viewer_id":  viewerID,
+		"creator_id": creatorID,
+	}
+
+	if err := uc.queueClient.PublishEvent(routingKey, event); err != nil {
+		uc.logger.Error("Failed to publish %s event: %v (viewer_id=%s, creator_id=%s)", routingKey, err, viewerID, creatorID)
 	}
-	return nil
 }
 
 func (uc *authUseCase) GetSubscriptionStatus(viewerID, creatorID string) (bool, error) {
@@ -196,3 +512,164 @@ func (uc *authUseCase) GetSubscriptionStatus(viewerID, creatorID string) (bool,
 	}
 	return subscription != nil && subscription.ID != "", nil
 }
+
+func (uc *authUseCase) CreateTier(creatorID, name, description string, price int) (*entity.SubscriptionTier, error) {
+	if price <= 0 {
+		return nil, fmt.Errorf("tier price must be positive")
+	}
+
+	tier := &entity.SubscriptionTier{
+		CreatorID:   creatorID,
+		Name:        name,
+		Description: description,
+		Price:       price,
+	}
+	if err := uc.userRepo.CreateTier(tier); err != nil {
+		uc.logger.Error("Failed to create subscription tier: %v", err)
+		return nil, fmt.Errorf("failed to create subscription tier")
+	}
+	return tier, nil
+}
+
+func (uc *authUseCase) GetCreatorTiers(creatorID string) ([]*entity.SubscriptionTier, error) {
+	return uc.userRepo.GetTiersByCreator(creatorID)
+}
+
+func (uc *authUseCase) DeleteTier(tierID, creatorID string) error {
+	tier, err := uc.userRepo.GetTier(tierID)
+	if err != nil {
+		return fmt.Errorf("subscription tier not found")
+	}
+
+	if tier.CreatorID != creatorID {
+		return fmt.Errorf("you can only delete your own subscription tiers")
+	}
+
+	if err := uc.userRepo.DeleteTier(tierID); err != nil {
+		uc.logger.Error("Failed to delete subscription tier: %v", err)
+		return fmt.Errorf("failed to delete subscription tier")
+	}
+	return nil
+}
+
+// ApplyForCreator files a viewer's request to become a creator for a
+// moderator to review.
+func (uc *authUseCase) ApplyForCreator(userID, message string) (*entity.CreatorApplication, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if user.Role != entity.RoleViewer {
+		return nil, fmt.Errorf("only viewers can apply to become creators")
+	}
+
+	application := &entity.CreatorApplication{
+		UserID:  userID,
+		Message: strings.TrimSpace(message),
+		Status:  entity.CreatorApplicationPending,
+	}
+	if err := uc.applicationRepo.Create(application); err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationPending) {
+			return nil, fmt.Errorf("creator application already pending")
+		}
+		uc.logger.Error("Failed to create creator application for user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to submit creator application")
+	}
+	return application, nil
+}
+
+// GetCreatorApplication returns the user's most recent application.
+func (uc *authUseCase) GetCreatorApplication(userID string) (*entity.CreatorApplication, error) {
+	application, err := uc.applicationRepo.GetLatestByUser(userID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationNotFound) {
+			return nil, fmt.Errorf("creator application not found")
+		}
+		uc.logger.Error("Failed to get creator application of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to get creator application")
+	}
+	return application, nil
+}
+
+// ListCreatorApplications returns the pending applications, oldest first.
+func (uc *authUseCase) ListCreatorApplications(limit, offset int) ([]*entity.CreatorApplication, error) {
+	applications, err := uc.applicationRepo.ListByStatus(entity.CreatorApplicationPending, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list creator applications: %v", err)
+		return nil, fmt.Errorf("failed to list creator applications")
+	}
+	return applications, nil
+}
+
+// ApproveCreatorApplication makes the applicant a creator. Their current
+// access token still carries the viewer role; the next refresh issues one
+// with the creator role.
+func (uc *authUseCase) ApproveCreatorApplication(applicationID, moderatorID string) (*entity.CreatorApplication, error) {
+	if _, err := uc.applicationRepo.Get(applicationID); err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationNotFound) {
+			return nil, fmt.Errorf("creator application not found")
+		}
+		uc.logger.Error("Failed to get creator application %s: %v", applicationID, err)
+		return nil, fmt.Errorf("failed to approve creator application")
+	}
+
+	application, err := uc.applicationRepo.Approve(applicationID, moderatorID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationChanged) {
+			return nil, fmt.Errorf("creator application is not pending")
+		}
+		uc.logger.Error("Failed to approve creator application %s: %v", applicationID, err)
+		return nil, fmt.Errorf("failed to approve creator application")
+	}
+
+	uc.logger.Info("Creator application %s approved by %s, user %s is now a creator", applicationID, moderatorID, application.UserID)
+	if uc.queueClient != nil {
+		go uc.publishCreatorApplicationNotification(application)
+	}
+	return application, nil
+}
+
+func (uc *authUseCase) RejectCreatorApplication(applicationID, moderatorID, reason string) (*entity.CreatorApplication, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("rejection reason is required")
+	}
+
+	if _, err := uc.applicationRepo.Get(applicationID); err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationNotFound) {
+			return nil, fmt.Errorf("creator application not found")
+		}
+		uc.logger.Error("Failed to get creator application %s: %v", applicationID, err)
+		return nil, fmt.Errorf("failed to reject creator application")
+	}
+
+	application, err := uc.applicationRepo.Reject(applicationID, moderatorID, reason)
+	if err != nil {
+		if errors.Is(err, persistent.ErrCreatorApplicationChanged) {
+			return nil, fmt.Errorf("creator application is not pending")
+		}
+		uc.logger.Error("Failed to reject creator application %s: %v", applicationID, err)
+		return nil, fmt.Errorf("failed to reject creator application")
+	}
+
+	if uc.queueClient != nil {
+		go uc.publishCreatorApplicationNotification(application)
+	}
+	return application, nil
+}
+
+func (uc *authUseCase) publishCreatorApplicationNotification(application *entity.CreatorApplication) {
+	task := map[string]interface{}{
+		"type":           "creator_application",
+		"user_id":        application.UserID,
+		"application_id": application.ID,
+		"status":         string(application.Status),
+		"reason":         application.Reason,
+		"priority":       5,
+	}
+
+	uc.logger.Info("[NOTIFICATION QUEUE] Publishing creator_application task to RabbitMQ: application_id=%s, status=%s", application.ID, application.Status)
+	if err := uc.queueClient.PublishNotificationTask(task); err != nil {
+		uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish creator_application task to RabbitMQ: %v (application_id=%s)", err, application.ID)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"lick-scroll/services/auth/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func subscriptionKey(viewerID, creatorID string) string {
+	return viewerID + ":" + creatorID
+}
+
+func isActivePaid(subscription *entity.Subscription, now time.Time) bool {
+	return subscription.Type == entity.SubscriptionTypePaid && subscription.CurrentPeriodEnd != nil && subscription.CurrentPeriodEnd.After(now)
+}
+
+func (r *fakeUserRepository) DeleteSubscription(viewerID, creatorID string, now time.Time) error {
+	key := subscriptionKey(viewerID, creatorID)
+	if subscription, ok := r.subscriptions[key]; ok && !isActivePaid(subscription, now) {
+		delete(r.subscriptions, key)
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) CancelPaidSubscription(viewerID, creatorID string, now time.Time) (*entity.Subscription, error) {
+	subscription, ok := r.subscriptions[subscriptionKey(viewerID, creatorID)]
+	if !ok || !isActivePaid(subscription, now) {
+		return nil, nil
+	}
+	subscription.CancelAtPeriodEnd = true
+	return subscription, nil
+}
+
+func TestUnsubscribe(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	env.setClock(now)
+	periodEnd := now.AddDate(0, 0, 10)
+	ended := now.Add(-time.Minute)
+	env.users.subscriptions = map[string]*entity.Subscription{
+		subscriptionKey("viewer", "paid"):   {ViewerID: "viewer", CreatorID: "paid", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &periodEnd},
+		subscriptionKey("viewer", "ended"):  {ViewerID: "viewer", CreatorID: "ended", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &ended},
+		subscriptionKey("viewer", "free"):   {ViewerID: "viewer", CreatorID: "free", Type: entity.SubscriptionTypeFree},
+		subscriptionKey("viewer", "lapsed"): {ViewerID: "viewer", CreatorID: "lapsed", Type: entity.SubscriptionTypeLapsed, CurrentPeriodEnd: &ended},
+	}
+
+	// A running paid period is kept and only flagged for the renewal job
+	cancelled, err := env.uc.Unsubscribe("viewer", "paid")
+	require.NoError(t, err)
+	require.NotNil(t, cancelled)
+	assert.Equal(t, periodEnd, *cancelled.CurrentPeriodEnd)
+	require.Contains(t, env.users.subscriptions, subscriptionKey("viewer", "paid"))
+	assert.True(t, env.users.subscriptions[subscriptionKey("viewer", "paid")].CancelAtPeriodEnd)
+
+	for _, creatorID := range []string{"ended", "free", "lapsed"} {
+		cancelled, err := env.uc.Unsubscribe("viewer", creatorID)
+		require.NoError(t, err)
+		assert.Nil(t, cancelled, creatorID)
+		assert.NotContains(t, env.users.subscriptions, subscriptionKey("viewer", creatorID))
+	}
+}
+
+func TestPlatformAccountIsHidden(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.uc.Register("someone@example.com", "Platform", "secret1")
+	assert.EqualError(t, err, "username already taken")
+
+	_, err = env.uc.GetUser(entity.PlatformUserID)
+	assert.EqualError(t, err, "user not found")
+
+	err = env.uc.Subscribe("viewer", entity.PlatformUserID)
+	assert.EqualError(t, err, "user not found")
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lick-scroll/pkg/mailer"
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/repo/persistent"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	resetPasswordTokenTTL = time.Hour
+)
+
+// emailTemplates describe the email sent for each kind of token. The body
+// takes the user's name and the link, which opens path on the frontend.
+var emailTemplates = map[entity.EmailTokenPurpose]struct {
+	subject string
+	body    string
+	path    string
+	ttl     time.Duration
+}{
+	entity.EmailTokenVerifyEmail: {
+		subject: "Подтвердите email в Lick Scroll",
+		body:    "Здравствуйте, %s!\n\nЧтобы подтвердить адрес и войти в Lick Scroll, откройте ссылку:\n%s\n\nСсылка действует 24 часа. Если вы не регистрировались в Lick Scroll, просто проигнорируйте это письмо.\n",
+		path:    "/verify-email",
+		ttl:     verifyEmailTokenTTL,
+	},
+	entity.EmailTokenResetPassword: {
+		subject: "Сброс пароля в Lick Scroll",
+		body:    "Здравствуйте, %s!\n\nЧтобы задать новый пароль, откройте ссылку:\n%s\n\nСсылка действует 1 час и сработает один раз. Если вы не запрашивали сброс пароля, проигнорируйте это письмо - пароль останется прежним.\n",
+		path:    "/reset-password",
+		ttl:     resetPasswordTokenTTL,
+	},
+}
+
+// ResendVerification emails a new verification link. It doesn't reveal
+// whether the address is registered: unknown and already verified addresses
+// get no email and no error.
+func (uc *authUseCase) ResendVerification(email string) error {
+	if err := uc.allowEmail(entity.EmailTokenVerifyEmail, email); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByEmail(email)
+	if err != nil || user.EmailVerified() {
+		return nil
+	}
+
+	if err := uc.sendEmailToken(user, entity.EmailTokenVerifyEmail); err != nil {
+		uc.logger.Error("Failed to send verification email to user %s: %v", user.ID, err)
+		return fmt.Errorf("failed to send email")
+	}
+	return nil
+}
+
+func (uc *authUseCase) VerifyEmail(token string) error {
+	user, err := uc.useEmailToken(token, entity.EmailTokenVerifyEmail)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := uc.userRepo.Update(user); err != nil {
+		uc.logger.Error("Failed to verify email of user %s: %v", user.ID, err)
+		return fmt.Errorf("failed to update user")
+	}
+	return nil
+}
+
+// RequestPasswordReset emails a password reset link. Like
+// ResendVerification it doesn't reveal whether the address is registered.
+func (uc *authUseCase) RequestPasswordReset(email string) error {
+	if err := uc.allowEmail(entity.EmailTokenResetPassword, email); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByEmail(email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	if err := uc.sendEmailToken(user, entity.EmailTokenResetPassword); err != nil {
+		uc.logger.Error("Failed to send password reset email to user %s: %v", user.ID, err)
+		return fmt.Errorf("failed to send email")
+	}
+	return nil
+}
+
+// ResetPassword sets a new password and ends every session of the user, in
+// case the old password was how someone else got in. Following the link
+// also proves the user owns the address.
+func (uc *authUseCase) ResetPassword(token, password string) error {
+	user, err := uc.useEmailToken(token, entity.EmailTokenResetPassword)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return fmt.Errorf("account is deactivated")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		uc.logger.Error("Failed to hash password: %v", err)
+		return fmt.Errorf("failed to reset password")
+	}
+
+	user.Password = string(hashedPassword)
+	if !user.EmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := uc.userRepo.Update(user); err != nil {
+		uc.logger.Error("Failed to reset password of user %s: %v", user.ID, err)
+		return fmt.Errorf("failed to reset password")
+	}
+
+	// Whoever reset the password owns the account, so a lockout no longer
+	// protects it
+	uc.resetAccountLockout(user.Email)
+	return uc.LogoutAll(user.ID)
+}
+
+// allowEmail limits how many emails of one kind an address can be sent, so
+// the endpoints can't be used to flood someone's inbox.
+func (uc *authUseCase) allowEmail(purpose entity.EmailTokenPurpose, email string) error {
+	key := fmt.Sprintf("%s:%s", purpose, emailKey(email))
+	allowed, err := uc.emailLimiter.Allow(context.Background(), key)
+	if err != nil {
+		uc.logger.Error("Failed to check email rate limit: %v", err)
+		return fmt.Errorf("failed to send email")
+	}
+	if !allowed {
+		return fmt.Errorf("too many emails requested, try again later")
+	}
+	return nil
+}
+
+// emailKey identifies an address however it was typed.
+func emailKey(email string) string {
+	return hashToken(strings.ToLower(strings.TrimSpace(email)))
+}
+
+// sendEmailToken creates a token for the purpose and emails the link to
+// the user. Earlier links for the same purpose stop working.
+func (uc *authUseCase) sendEmailToken(user *entity.User, purpose entity.EmailTokenPurpose) error {
+	template := emailTemplates[purpose]
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	err = uc.emailTokenRepo.Create(&entity.EmailToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(template.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", uc.appURL, template.path, token)
+	return uc.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: template.subject,
+		Body:    fmt.Sprintf(template.body, user.Username, link),
+	})
+}
+
+// useEmailToken redeems a token sent for the purpose and returns its user.
+func (uc *authUseCase) useEmailToken(token string, purpose entity.EmailTokenPurpose) (*entity.User, error) {
+	stored, err := uc.emailTokenRepo.Get(hashToken(token))
+	if err != nil {
+		if errors.Is(err, persistent.ErrEmailTokenNotFound) {
+			return nil, fmt.Errorf("invalid or expired token")
+		}
+		uc.logger.Error("Failed to get email token: %v", err)
+		return nil, fmt.Errorf("failed to check token")
+	}
+
+	if stored.Purpose != purpose || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
+
+	used, err := uc.emailTokenRepo.Use(stored.ID)
+	if err != nil {
+		uc.logger.Error("Failed to use email token: %v", err)
+		return nil, fmt.Errorf("failed to check token")
+	}
+	if !used {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
+
+	user, err := uc.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
+	return user, nil
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/jwt"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/mailer"
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/repo/persistent"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserRepository keeps users in memory. Methods the tests don't need
+// panic through the nil embedded interface.
+type fakeUserRepository struct {
+	persistent.UserRepository
+	users         map[string]entity.User
+	subscriptions map[string]*entity.Subscription // viewer ID + creator ID -> subscription
+}
+
+func (r *fakeUserRepository) Create(user *entity.User) error {
+	user.ID = uuid.New().String()
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *fakeUserRepository) GetByID(id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &user, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeUserRepository) GetByUsername(username string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeUserRepository) Update(user *entity.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+// fakeSessionRepository tracks which sessions hold live refresh tokens.
+type fakeSessionRepository struct {
+	persistent.SessionRepository
+	sessions map[string]string // session ID -> user ID
+	mfa      map[string]bool   // session ID -> opened with a second factor
+}
+
+func (r *fakeSessionRepository) CreateRefreshToken(token *entity.RefreshToken) error {
+	r.sessions[token.SessionID] = token.UserID
+	r.mfa[token.SessionID] = token.MFA
+	return nil
+}
+
+func (r *fakeSessionRepository) SetSessionMFA(sessionID string) error {
+	r.mfa[sessionID] = true
+	return nil
+}
+
+func (r *fakeSessionRepository) ClearUserMFA(userID string) error {
+	for sessionID, owner := range r.sessions {
+		if owner == userID {
+			r.mfa[sessionID] = false
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeUserSessions(userID string) ([]string, error) {
+	var revoked []string
+	for sessionID, owner := range r.sessions {
+		if owner == userID {
+			revoked = append(revoked, sessionID)
+			delete(r.sessions, sessionID)
+		}
+	}
+	return revoked, nil
+}
+
+type fakeEmailTokenRepository struct {
+	tokens map[string]*entity.EmailToken // hash -> token
+}
+
+func (r *fakeEmailTokenRepository) Create(token *entity.EmailToken) error {
+	now := time.Now()
+	for _, existing := range r.tokens {
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			existing.UsedAt = &now
+		}
+	}
+	token.ID = uuid.New().String()
+	stored := *token
+	r.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (r *fakeEmailTokenRepository) Get(tokenHash string) (*entity.EmailToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, persistent.ErrEmailTokenNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeEmailTokenRepository) Use(tokenID string) (bool, error) {
+	for _, token := range r.tokens {
+		if token.ID == tokenID && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeDenylist map[string]bool
+
+func (d fakeDenylist) Revoke(ctx context.Context, sessionID string) error {
+	d[sessionID] = true
+	return nil
+}
+
+// fakeLimiter allows limit events per key.
+type fakeLimiter struct {
+	limit  int
+	counts map[string]int
+}
+
+func (l *fakeLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	l.counts[key]++
+	return l.counts[key] <= l.limit, nil
+}
+
+type testEnv struct {
+	uc         *authUseCase
+	users      *fakeUserRepository
+	sessions   *fakeSessionRepository
+	tokens     *fakeEmailTokenRepository
+	denylist   fakeDenylist
+	mailer     *mailer.MemoryMailer
+	mfa        *fakeMFARepository
+	challenges fakeMFAChallengeRepository
+	attempts   *fakeLoginAttemptRepository
+	throttle   fakeLoginThrottleRepository
+	jwt        *jwt.Service
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	key, err := jwt.NewEd25519Key("test-key")
+	require.NoError(t, err)
+	jwtService, err := jwt.NewIssuer(key, jwt.NewStaticKeySet(key))
+	require.NoError(t, err)
+
+	env := &testEnv{
+		users:      &fakeUserRepository{users: map[string]entity.User{}, subscriptions: map[string]*entity.Subscription{}},
+		sessions:   &fakeSessionRepository{sessions: map[string]string{}, mfa: map[string]bool{}},
+		tokens:     &fakeEmailTokenRepository{tokens: map[string]*entity.EmailToken{}},
+		denylist:   fakeDenylist{},
+		mailer:     mailer.NewMemoryMailer(),
+		mfa:        newFakeMFARepository(),
+		challenges: fakeMFAChallengeRepository{},
+		attempts:   &fakeLoginAttemptRepository{},
+		throttle:   fakeLoginThrottleRepository{},
+	}
+	env.uc = NewAuthUseCase(
+		env.users,
+		env.sessions,
+		nil,
+		env.tokens,
+		env.mfa,
+		env.challenges,
+		env.attempts,
+		env.throttle,
+		jwtService,
+		env.denylist,
+		env.mailer,
+		&fakeLimiter{limit: 3, counts: map[string]int{}},
+		"http://localhost:3000/",
+		nil,
+		nil,
+		logger.New(),
+	).(*authUseCase)
+	env.jwt = jwtService
+	return env
+}
+
+var linkPattern = regexp.MustCompile(`http://localhost:3000(/[a-z-]+)\?token=([A-Za-z0-9_-]+)`)
+
+// lastLink returns the path and token of the link in the latest email.
+func (env *testEnv) lastLink(t *testing.T) (string, string) {
+	t.Helper()
+	messages := env.mailer.Messages()
+	require.NotEmpty(t, messages)
+	match := linkPattern.FindStringSubmatch(messages[len(messages)-1].Body)
+	require.NotNil(t, match, "no link in email")
+	return match[1], match[2]
+}
+
+func TestRegister_RequiresEmailVerification(t *testing.T) {
+	env := newTestEnv(t)
+
+	user, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+	assert.False(t, user.EmailVerified())
+
+	messages := env.mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user@example.com", messages[0].To)
+	path, token := env.lastLink(t)
+	assert.Equal(t, "/verify-email", path)
+
+	_, err = env.uc.Login("user@example.com", "secret1", testClient)
+	assert.EqualError(t, err, "email not verified")
+
+	require.NoError(t, env.uc.VerifyEmail(token))
+	result, err := env.uc.Login("user@example.com", "secret1", testClient)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+
+	// Links work once
+	assert.EqualError(t, env.uc.VerifyEmail(token), "invalid or expired token")
+}
+
+func TestResendVerification(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+	_, firstToken := env.lastLink(t)
+
+	require.NoError(t, env.uc.ResendVerification("user@example.com"))
+	_, secondToken := env.lastLink(t)
+
+	// Only the latest link works
+	assert.EqualError(t, env.uc.VerifyEmail(firstToken), "invalid or expired token")
+	require.NoError(t, env.uc.VerifyEmail(secondToken))
+
+	// Verified and unknown addresses get no email and no error
+	sent := len(env.mailer.Messages())
+	assert.NoError(t, env.uc.ResendVerification("user@example.com"))
+	assert.NoError(t, env.uc.ResendVerification("nobody@example.com"))
+	assert.Len(t, env.mailer.Messages(), sent)
+}
+
+func TestVerifyEmail_RejectsOtherTokens(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+	_, token := env.lastLink(t)
+
+	assert.EqualError(t, env.uc.VerifyEmail("unknown"), "invalid or expired token")
+
+	// A verification token can't reset the password
+	assert.EqualError(t, env.uc.ResetPassword(token, "newsecret"), "invalid or expired token")
+
+	for _, stored := range env.tokens.tokens {
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+	assert.EqualError(t, env.uc.VerifyEmail(token), "invalid or expired token")
+}
+
+func TestResetPassword(t *testing.T) {
+	env := newTestEnv(t)
+	user, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+	_, verifyToken := env.lastLink(t)
+	require.NoError(t, env.uc.VerifyEmail(verifyToken))
+	_, err = env.uc.Login("user@example.com", "secret1", testClient)
+	require.NoError(t, err)
+
+	require.NoError(t, env.uc.RequestPasswordReset("user@example.com"))
+	path, token := env.lastLink(t)
+	assert.Equal(t, "/reset-password", path)
+
+	require.NoError(t, env.uc.ResetPassword(token, "newsecret"))
+
+	stored, _ := env.users.GetByID(user.ID)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.Password), []byte("newsecret")))
+	_, err = env.uc.Login("user@example.com", "secret1", testClient)
+	assert.EqualError(t, err, "invalid credentials")
+
+	// Sessions opened with the old password are logged out
+	assert.Empty(t, env.sessions.sessions)
+	assert.Len(t, env.denylist, 1)
+
+	assert.EqualError(t, env.uc.ResetPassword(token, "another"), "invalid or expired token")
+}
+
+func TestResetPassword_VerifiesEmail(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+
+	require.NoError(t, env.uc.RequestPasswordReset("user@example.com"))
+	_, token := env.lastLink(t)
+	require.NoError(t, env.uc.ResetPassword(token, "newsecret"))
+
+	_, err = env.uc.Login("user@example.com", "newsecret", testClient)
+	assert.NoError(t, err)
+}
+
+func TestRequestPasswordReset_RateLimitedPerAddress(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.uc.Register("user@example.com", "user", "secret1")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, env.uc.RequestPasswordReset("user@example.com"))
+	}
+	assert.EqualError(t, env.uc.RequestPasswordReset("User@Example.com"), "too many emails requested, try again later")
+
+	// Unknown addresses are limited the same way, so the limit reveals nothing
+	for i := 0; i < 3; i++ {
+		require.NoError(t, env.uc.RequestPasswordReset("nobody@example.com"))
+	}
+	assert.Error(t, env.uc.RequestPasswordReset("nobody@example.com"))
+
+	// Verification emails have their own budget
+	assert.NoError(t, env.uc.ResendVerification("user@example.com"))
+}
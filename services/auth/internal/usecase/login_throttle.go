@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lick-scroll/pkg/mailer"
+	"lick-scroll/services/auth/internal/entity"
+)
+
+// lockoutPolicy locks a key on every threshold-th failure in a row: first
+// for base, then twice as long each time, up to max.
+type lockoutPolicy struct {
+	threshold int
+	base      time.Duration
+	max       time.Duration
+}
+
+var (
+	// Guessing one account's password
+	accountLockout = lockoutPolicy{threshold: 5, base: time.Minute, max: time.Hour}
+	// Trying leaked passwords against many accounts from one address
+	ipLockout = lockoutPolicy{threshold: 20, base: time.Minute, max: time.Hour}
+)
+
+// loginFailureTTL is how long failures are remembered after the latest one.
+const loginFailureTTL = 24 * time.Hour
+
+// lockout returns how long the failures-th failure locks the key for, or 0
+// if it doesn't.
+func (p lockoutPolicy) lockout(failures int) time.Duration {
+	if failures < p.threshold || failures%p.threshold != 0 {
+		return 0
+	}
+	duration := p.base
+	for i := failures / p.threshold; i > 1 && duration < p.max; i-- {
+		duration *= 2
+	}
+	return min(duration, p.max)
+}
+
+// LoginLockedError is returned while the account or the client's address
+// is locked out after too many failed logins.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+type loginThrottleKey struct {
+	key     string
+	policy  lockoutPolicy
+	account bool
+}
+
+// loginThrottleKeys are the keys a login from client to email counts
+// against. Emails are hashed so addresses don't end up in Redis.
+func loginThrottleKeys(email string, client entity.ClientInfo) []loginThrottleKey {
+	keys := []loginThrottleKey{{key: accountThrottleKey(email), policy: accountLockout, account: true}}
+	if client.IP != "" {
+		keys = append(keys, loginThrottleKey{key: "ip:" + client.IP, policy: ipLockout})
+	}
+	return keys
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + emailKey(email)
+}
+
+// checkLoginLock fails while any of the keys is locked out.
+func (uc *authUseCase) checkLoginLock(keys []loginThrottleKey) error {
+	ctx := context.Background()
+	now := uc.now()
+
+	var retryAfter time.Duration
+	for _, k := range keys {
+		until, err := uc.loginThrottleRepo.LockedUntil(ctx, k.key)
+		if err != nil {
+			uc.logger.Error("Failed to check login lockout: %v", err)
+			return fmt.Errorf("failed to log in")
+		}
+		if wait := until.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// loginFailed records a wrong password or code: it's audited, counted
+// against the account and the address, and the owner is emailed when their
+// account gets locked. Unknown emails are counted the same way, so lockouts
+// don't reveal which accounts exist.
+func (uc *authUseCase) loginFailed(user *entity.User, email string, client entity.ClientInfo, outcome entity.LoginOutcome) {
+	uc.recordLoginAttempt(user, email, client, outcome)
+
+	ctx := context.Background()
+	for _, k := range loginThrottleKeys(email, client) {
+		failures, err := uc.loginThrottleRepo.Fail(ctx, k.key, loginFailureTTL)
+		if err != nil {
+			uc.logger.Error("Failed to count failed login: %v", err)
+			continue
+		}
+
+		lockout := k.policy.lockout(failures)
+		if lockout == 0 {
+			continue
+		}
+		if err := uc.loginThrottleRepo.Lock(ctx, k.key, uc.now().Add(lockout), loginFailureTTL); err != nil {
+			uc.logger.Error("Failed to lock out login: %v", err)
+			continue
+		}
+
+		if !k.account {
+			uc.logger.Warn("Locked out logins from %s for %s after %d failures", client.IP, lockout, failures)
+			continue
+		}
+		if user != nil {
+			uc.logger.Warn("Locked out logins to user %s for %s after %d failures", user.ID, lockout, failures)
+			if err := uc.sendLockoutEmail(user, lockout); err != nil {
+				uc.logger.Error("Failed to send lockout email to user %s: %v", user.ID, err)
+			}
+		}
+	}
+}
+
+// loginSucceeded forgets the account's failures. The address keeps its
+// count: a credential stuffing run gets some logins right.
+func (uc *authUseCase) loginSucceeded(user *entity.User, email string, client entity.ClientInfo) {
+	uc.recordLoginAttempt(user, email, client, entity.LoginSucceeded)
+	uc.resetAccountLockout(email)
+}
+
+func (uc *authUseCase) resetAccountLockout(email string) {
+	if err := uc.loginThrottleRepo.Reset(context.Background(), accountThrottleKey(email)); err != nil {
+		uc.logger.Error("Failed to reset login lockout: %v", err)
+	}
+}
+
+// recordLoginAttempt writes the audit record. A failed write is logged and
+// doesn't stop the login.
+func (uc *authUseCase) recordLoginAttempt(user *entity.User, email string, client entity.ClientInfo, outcome entity.LoginOutcome) {
+	attempt := &entity.LoginAttempt{
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		IPAddress: client.IP,
+		UserAgent: truncateUTF8(client.UserAgent, maxUserAgentLength),
+		Outcome:   outcome,
+		CreatedAt: uc.now(),
+	}
+	if user != nil {
+		attempt.UserID = user.ID
+	}
+
+	if err := uc.loginAttemptRepo.Create(attempt); err != nil {
+		uc.logger.Error("Failed to record login attempt: %v", err)
+	}
+}
+
+const maxUserAgentLength = 512
+
+// truncateUTF8 cuts s to at most n bytes of valid UTF-8, which is all
+// Postgres accepts.
+func truncateUTF8(s string, n int) string {
+	if len(s) > n {
+		s = s[:n]
+	}
+	return strings.ToValidUTF8(s, "")
+}
+
+func (uc *authUseCase) sendLockoutEmail(user *entity.User, lockout time.Duration) error {
+	body := fmt.Sprintf(
+		"Здравствуйте, %s!\n\nКто-то несколько раз ввел неверный пароль или код от вашего аккаунта Lick Scroll, поэтому вход заблокирован на %d мин.\n\nЕсли это были вы, попробуйте позже или сбросьте пароль:\n%s/forgot-password\n\nЕсли нет, смените пароль и включите двухфакторную аутентификацию в профиле.\n",
+		user.Username, int(lockout.Minutes()), uc.appURL,
+	)
+	return uc.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Вход в Lick Scroll заблокирован",
+		Body:    body,
+	})
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/totp"
+	"lick-scroll/services/auth/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testClient = entity.ClientInfo{IP: "192.0.2.1", UserAgent: "test"}
+
+type fakeLoginAttemptRepository struct {
+	attempts []entity.LoginAttempt
+}
+
+func (r *fakeLoginAttemptRepository) Create(attempt *entity.LoginAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func (r *fakeLoginAttemptRepository) outcomes() []entity.LoginOutcome {
+	outcomes := make([]entity.LoginOutcome, len(r.attempts))
+	for i, attempt := range r.attempts {
+		outcomes[i] = attempt.Outcome
+	}
+	return outcomes
+}
+
+type fakeThrottleState struct {
+	failures    int
+	lockedUntil time.Time
+}
+
+type fakeLoginThrottleRepository map[string]*fakeThrottleState
+
+func (r fakeLoginThrottleRepository) state(key string) *fakeThrottleState {
+	if r[key] == nil {
+		r[key] = &fakeThrottleState{}
+	}
+	return r[key]
+}
+
+func (r fakeLoginThrottleRepository) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	return r.state(key).lockedUntil, nil
+}
+
+func (r fakeLoginThrottleRepository) Fail(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	r.state(key).failures++
+	return r.state(key).failures, nil
+}
+
+func (r fakeLoginThrottleRepository) Lock(ctx context.Context, key string, until time.Time, ttl time.Duration) error {
+	r.state(key).lockedUntil = until
+	return nil
+}
+
+func (r fakeLoginThrottleRepository) Reset(ctx context.Context, key string) error {
+	delete(r, key)
+	return nil
+}
+
+// assertLocked checks that err is a lockout ending after retryAfter.
+func assertLocked(t *testing.T, err error, retryAfter time.Duration) {
+	t.Helper()
+	var locked *LoginLockedError
+	require.True(t, errors.As(err, &locked), "expected lockout, got %v", err)
+	assert.Equal(t, retryAfter, locked.RetryAfter)
+}
+
+func TestLockoutPolicy(t *testing.T) {
+	tests := []struct {
+		failures int
+		lockout  time.Duration
+	}{
+		{1, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 0},
+		{10, 2 * time.Minute},
+		{15, 4 * time.Minute},
+		{30, 32 * time.Minute},
+		{35, time.Hour},
+		{500, time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.lockout, accountLockout.lockout(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestLogin_AccountLockoutBacksOff(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleViewer)
+
+	for i := 0; i < accountLockout.threshold; i++ {
+		_, err := env.uc.Login(user.Email, "wrong", testClient)
+		assert.EqualError(t, err, "invalid credentials")
+	}
+
+	// Even the right password is refused during the lockout
+	_, err := env.uc.Login(user.Email, "secret1", testClient)
+	assertLocked(t, err, time.Minute)
+	env.setClock(now.Add(30 * time.Second))
+	_, err = env.uc.Login(user.Email, "secret1", testClient)
+	assertLocked(t, err, 30*time.Second)
+
+	messages := env.mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, user.Email, messages[0].To)
+	assert.Contains(t, messages[0].Body, "заблокирован на 1 мин")
+
+	// The next run of failures locks for twice as long
+	now = now.Add(time.Minute)
+	env.setClock(now)
+	for i := 0; i < accountLockout.threshold; i++ {
+		_, err := env.uc.Login(user.Email, "wrong", testClient)
+		assert.EqualError(t, err, "invalid credentials")
+	}
+	_, err = env.uc.Login(user.Email, "secret1", testClient)
+	assertLocked(t, err, 2*time.Minute)
+	assert.Len(t, env.mailer.Messages(), 2)
+
+	env.setClock(now.Add(2 * time.Minute))
+	_, err = env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+
+	// A successful login starts the count over
+	for i := 0; i < accountLockout.threshold-1; i++ {
+		env.uc.Login(user.Email, "wrong", testClient)
+	}
+	_, err = env.uc.Login(user.Email, "secret1", testClient)
+	assert.NoError(t, err)
+}
+
+func TestLogin_AuditsAttempts(t *testing.T) {
+	env := newTestEnv(t)
+	env.setClock(time.Now())
+	user := env.newUser(t, entity.RoleViewer)
+
+	for i := 0; i < accountLockout.threshold; i++ {
+		env.uc.Login(user.Email, "wrong", testClient)
+	}
+	env.uc.Login(user.Email, "secret1", testClient)
+	env.uc.Login("nobody@example.com", "secret1", testClient)
+
+	expected := []entity.LoginOutcome{}
+	for i := 0; i < accountLockout.threshold; i++ {
+		expected = append(expected, entity.LoginInvalidCredentials)
+	}
+	expected = append(expected, entity.LoginLocked, entity.LoginInvalidCredentials)
+	assert.Equal(t, expected, env.attempts.outcomes())
+
+	first := env.attempts.attempts[0]
+	assert.Equal(t, user.ID, first.UserID)
+	assert.Equal(t, testClient.IP, first.IPAddress)
+	assert.Equal(t, testClient.UserAgent, first.UserAgent)
+	assert.Empty(t, env.attempts.attempts[len(env.attempts.attempts)-1].UserID)
+}
+
+func TestLogin_UnknownEmailLocksOutToo(t *testing.T) {
+	env := newTestEnv(t)
+	env.setClock(time.Now())
+
+	// Unknown emails behave like accounts, so lockouts reveal nothing
+	for i := 0; i < accountLockout.threshold; i++ {
+		_, err := env.uc.Login("nobody@example.com", "wrong", testClient)
+		assert.EqualError(t, err, "invalid credentials")
+	}
+	_, err := env.uc.Login("Nobody@Example.com", "wrong", testClient)
+	assertLocked(t, err, time.Minute)
+	assert.Empty(t, env.mailer.Messages())
+}
+
+func TestLogin_AddressLockout(t *testing.T) {
+	env := newTestEnv(t)
+	env.setClock(time.Now())
+	user := env.newUser(t, entity.RoleViewer)
+
+	// One wrong password for each of many accounts
+	for i := 0; i < ipLockout.threshold; i++ {
+		_, err := env.uc.Login(fmt.Sprintf("user%d@example.com", i), "secret1", testClient)
+		assert.EqualError(t, err, "invalid credentials")
+	}
+
+	_, err := env.uc.Login(user.Email, "secret1", testClient)
+	assertLocked(t, err, time.Minute)
+
+	// The account itself isn't locked, so its owner can log in from elsewhere
+	_, err = env.uc.Login(user.Email, "secret1", entity.ClientInfo{IP: "198.51.100.7"})
+	assert.NoError(t, err)
+	assert.Empty(t, env.mailer.Messages())
+}
+
+func TestCompleteMFALogin_CountsTowardsLockout(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Now()
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleCreator)
+	secret, _, _ := env.enableTOTP(t, user, now)
+
+	// Knowing the password doesn't give unlimited guesses at the code
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	for i := 0; i < accountLockout.threshold-1; i++ {
+		_, err = env.uc.CompleteMFALogin(result.MFAToken, "000000", testClient)
+		assert.EqualError(t, err, "invalid code")
+	}
+	_, err = env.uc.Login(user.Email, "wrong", testClient)
+	assert.EqualError(t, err, "invalid credentials")
+
+	code, _ := totp.Code(secret, now.Add(totp.Period))
+	env.setClock(now.Add(totp.Period))
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, code, testClient)
+	assertLocked(t, err, time.Minute-totp.Period)
+	assert.Contains(t, env.attempts.outcomes(), entity.LoginInvalidMFACode)
+}
+
+func TestResetPassword_EndsLockout(t *testing.T) {
+	env := newTestEnv(t)
+	env.setClock(time.Now())
+	user := env.newUser(t, entity.RoleViewer)
+
+	for i := 0; i < accountLockout.threshold; i++ {
+		env.uc.Login(user.Email, "wrong", testClient)
+	}
+
+	require.NoError(t, env.uc.RequestPasswordReset(user.Email))
+	_, token := env.lastLink(t)
+	require.NoError(t, env.uc.ResetPassword(token, "newsecret"))
+
+	_, err := env.uc.Login(user.Email, "newsecret", testClient)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lick-scroll/pkg/totp"
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/repo/cache"
+	"lick-scroll/services/auth/internal/repo/persistent"
+
+	"github.com/google/uuid"
+)
+
+const (
+	totpIssuer = "Lick Scroll"
+
+	// A password login waiting for a second factor lives this long and
+	// allows this many wrong codes.
+	mfaChallengeTTL         = 5 * time.Minute
+	maxMFAChallengeFailures = 5
+
+	recoveryCodeCount = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFARequired reports whether the role must use two-factor authentication
+// to withdraw funds or moderate.
+func MFARequired(role entity.UserRole) bool {
+	return role == entity.RoleCreator || role == entity.RoleModerator
+}
+
+// startMFALogin hands out the token that finishes a password login once the
+// user enters a code.
+func (uc *authUseCase) startMFALogin(user *entity.User) (*entity.LoginResult, error) {
+	token, err := newToken()
+	if err != nil {
+		uc.logger.Error("Failed to generate mfa token: %v", err)
+		return nil, fmt.Errorf("failed to log in")
+	}
+
+	if err := uc.mfaChallengeRepo.Create(context.Background(), hashToken(token), user.ID, mfaChallengeTTL); err != nil {
+		uc.logger.Error("Failed to store mfa challenge of user %s: %v", user.ID, err)
+		return nil, fmt.Errorf("failed to log in")
+	}
+	return &entity.LoginResult{MFAToken: token, MFAExpiresIn: int(mfaChallengeTTL.Seconds())}, nil
+}
+
+// CompleteMFALogin finishes a password login with an authenticator or
+// recovery code and opens a session that counts as two-factor. Wrong codes
+// count towards the same lockout as wrong passwords.
+func (uc *authUseCase) CompleteMFALogin(mfaToken, code string, client entity.ClientInfo) (*entity.LoginResult, error) {
+	ctx := context.Background()
+	tokenHash := hashToken(mfaToken)
+
+	userID, err := uc.mfaChallengeRepo.Get(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, cache.ErrMFAChallengeNotFound) {
+			return nil, fmt.Errorf("invalid or expired mfa token")
+		}
+		uc.logger.Error("Failed to get mfa challenge: %v", err)
+		return nil, fmt.Errorf("failed to log in")
+	}
+
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired mfa token")
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("account is deactivated")
+	}
+
+	if err := uc.checkLoginLock(loginThrottleKeys(user.Email, client)); err != nil {
+		uc.recordLoginAttempt(user, user.Email, client, entity.LoginLocked)
+		return nil, err
+	}
+
+	ok, err := uc.verifySecondFactor(userID, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		uc.loginFailed(user, user.Email, client, entity.LoginInvalidMFACode)
+		failures, err := uc.mfaChallengeRepo.Fail(ctx, tokenHash)
+		if err != nil && !errors.Is(err, cache.ErrMFAChallengeNotFound) {
+			uc.logger.Error("Failed to count mfa failure of user %s: %v", userID, err)
+		}
+		// Too many guesses end the login; the user has to enter the password again
+		if failures >= maxMFAChallengeFailures {
+			if _, err := uc.mfaChallengeRepo.Delete(ctx, tokenHash); err != nil {
+				uc.logger.Error("Failed to delete mfa challenge of user %s: %v", userID, err)
+			}
+		}
+		return nil, fmt.Errorf("invalid code")
+	}
+
+	deleted, err := uc.mfaChallengeRepo.Delete(ctx, tokenHash)
+	if err != nil {
+		uc.logger.Error("Failed to delete mfa challenge of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to log in")
+	}
+	if !deleted {
+		return nil, fmt.Errorf("invalid or expired mfa token")
+	}
+
+	tokens, err := uc.issueTokens(user, uuid.New().String(), true)
+	if err != nil {
+		return nil, err
+	}
+	uc.loginSucceeded(user, user.Email, client)
+
+	user.Password = ""
+	return &entity.LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// EnrollTOTP creates a new authenticator secret. Two-factor authentication
+// stays off until the secret is confirmed with a code, and enrolling again
+// before that replaces the secret.
+func (uc *authUseCase) EnrollTOTP(userID string) (*entity.TOTPSetup, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		uc.logger.Error("Failed to generate totp secret: %v", err)
+		return nil, fmt.Errorf("failed to enroll")
+	}
+
+	if err := uc.mfaRepo.SavePendingTOTP(userID, secret); err != nil {
+		if errors.Is(err, persistent.ErrTOTPEnabled) {
+			return nil, fmt.Errorf("two-factor authentication already enabled")
+		}
+		uc.logger.Error("Failed to save totp enrollment of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to enroll")
+	}
+
+	return &entity.TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns two-factor authentication on and returns the recovery
+// codes, which are shown only this once. The session the user confirmed
+// from counts as two-factor from its next refresh.
+func (uc *authUseCase) ConfirmTOTP(userID, sessionID, code string) ([]string, error) {
+	enrollment, err := uc.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, fmt.Errorf("two-factor authentication not enrolled")
+	}
+	if enrollment.Confirmed() {
+		return nil, fmt.Errorf("two-factor authentication already enabled")
+	}
+
+	step, ok := totp.Match(enrollment.Secret, strings.TrimSpace(code), uc.now())
+	if !ok {
+		return nil, fmt.Errorf("invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		uc.logger.Error("Failed to generate recovery codes: %v", err)
+		return nil, fmt.Errorf("failed to enable two-factor authentication")
+	}
+
+	confirmed, err := uc.mfaRepo.ConfirmTOTP(userID, step, hashes)
+	if err != nil {
+		uc.logger.Error("Failed to confirm totp of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to enable two-factor authentication")
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("invalid code")
+	}
+
+	if sessionID != "" {
+		if err := uc.sessionRepo.SetSessionMFA(sessionID); err != nil {
+			uc.logger.Error("Failed to mark session %s as two-factor: %v", sessionID, err)
+		}
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off. It takes a current code so
+// a hijacked session alone can't do it, and drops the two-factor mark from
+// every session of the user.
+func (uc *authUseCase) DisableTOTP(userID, code string) error {
+	if err := uc.requireSecondFactor(userID, code); err != nil {
+		return err
+	}
+
+	if err := uc.mfaRepo.DeleteTOTP(userID); err != nil {
+		uc.logger.Error("Failed to delete totp of user %s: %v", userID, err)
+		return fmt.Errorf("failed to disable two-factor authentication")
+	}
+	if err := uc.sessionRepo.ClearUserMFA(userID); err != nil {
+		uc.logger.Error("Failed to clear two-factor sessions of user %s: %v", userID, err)
+		return fmt.Errorf("failed to disable two-factor authentication")
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes with new ones.
+func (uc *authUseCase) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	if err := uc.requireSecondFactor(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		uc.logger.Error("Failed to generate recovery codes: %v", err)
+		return nil, fmt.Errorf("failed to regenerate recovery codes")
+	}
+	if err := uc.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		uc.logger.Error("Failed to replace recovery codes of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to regenerate recovery codes")
+	}
+	return codes, nil
+}
+
+func (uc *authUseCase) GetMFAStatus(userID string) (*entity.MFAStatus, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	enrollment, err := uc.getTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entity.MFAStatus{Required: MFARequired(user.Role)}
+	if enrollment == nil || !enrollment.Confirmed() {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.RecoveryCodesRemaining, err = uc.mfaRepo.CountRecoveryCodes(userID)
+	if err != nil {
+		uc.logger.Error("Failed to count recovery codes of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to get two-factor status")
+	}
+	return status, nil
+}
+
+// getTOTP returns the user's enrollment, or nil if there is none.
+func (uc *authUseCase) getTOTP(userID string) (*entity.TOTPEnrollment, error) {
+	enrollment, err := uc.mfaRepo.GetTOTP(userID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrTOTPNotFound) {
+			return nil, nil
+		}
+		uc.logger.Error("Failed to get totp of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to get two-factor status")
+	}
+	return enrollment, nil
+}
+
+// requireSecondFactor checks a code from a user who has two-factor
+// authentication on.
+func (uc *authUseCase) requireSecondFactor(userID, code string) error {
+	ok, err := uc.verifySecondFactor(userID, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid code")
+	}
+	return nil
+}
+
+// verifySecondFactor accepts a code from the authenticator or one of the
+// recovery codes, and uses it up.
+func (uc *authUseCase) verifySecondFactor(userID, code string) (bool, error) {
+	enrollment, err := uc.getTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	if enrollment == nil || !enrollment.Confirmed() {
+		return false, fmt.Errorf("two-factor authentication not enabled")
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Match(enrollment.Secret, code, uc.now())
+		if !ok {
+			return false, nil
+		}
+		used, err := uc.mfaRepo.UseTOTPStep(userID, step)
+		if err != nil {
+			uc.logger.Error("Failed to use totp step of user %s: %v", userID, err)
+			return false, fmt.Errorf("failed to verify code")
+		}
+		return used, nil
+	}
+
+	used, err := uc.mfaRepo.UseRecoveryCode(userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		uc.logger.Error("Failed to use recovery code of user %s: %v", userID, err)
+		return false, fmt.Errorf("failed to verify code")
+	}
+	if used {
+		uc.logger.Info("User %s logged in with a recovery code", userID)
+	}
+	return used, nil
+}
+
+// newRecoveryCodes returns codes formatted for the user, like
+// ABCD-EFGH-IJKL-MNOP, and their hashes for storage.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryCodeEncoding.EncodeToString(buf)
+		codes[i] = raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+		hashes[i] = hashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed in lower case or without dashes.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/totp"
+	"lick-scroll/services/auth/internal/entity"
+	"lick-scroll/services/auth/internal/repo/cache"
+	"lick-scroll/services/auth/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeMFARepository struct {
+	enrollments map[string]*entity.TOTPEnrollment
+	codes       map[string]map[string]bool // user ID -> code hash -> used
+}
+
+func newFakeMFARepository() *fakeMFARepository {
+	return &fakeMFARepository{
+		enrollments: map[string]*entity.TOTPEnrollment{},
+		codes:       map[string]map[string]bool{},
+	}
+}
+
+func (r *fakeMFARepository) SavePendingTOTP(userID, secret string) error {
+	if existing, ok := r.enrollments[userID]; ok && existing.Confirmed() {
+		return persistent.ErrTOTPEnabled
+	}
+	r.enrollments[userID] = &entity.TOTPEnrollment{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (r *fakeMFARepository) GetTOTP(userID string) (*entity.TOTPEnrollment, error) {
+	enrollment, ok := r.enrollments[userID]
+	if !ok {
+		return nil, persistent.ErrTOTPNotFound
+	}
+	copied := *enrollment
+	return &copied, nil
+}
+
+func (r *fakeMFARepository) ConfirmTOTP(userID string, step int64, codeHashes []string) (bool, error) {
+	enrollment, ok := r.enrollments[userID]
+	if !ok || enrollment.Confirmed() || enrollment.LastUsedStep >= step {
+		return false, nil
+	}
+	now := time.Now()
+	enrollment.ConfirmedAt = &now
+	enrollment.LastUsedStep = step
+	return true, r.ReplaceRecoveryCodes(userID, codeHashes)
+}
+
+func (r *fakeMFARepository) UseTOTPStep(userID string, step int64) (bool, error) {
+	enrollment, ok := r.enrollments[userID]
+	if !ok || !enrollment.Confirmed() || enrollment.LastUsedStep >= step {
+		return false, nil
+	}
+	enrollment.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepository) DeleteTOTP(userID string) error {
+	delete(r.enrollments, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *fakeMFARepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	r.codes[userID] = map[string]bool{}
+	for _, hash := range codeHashes {
+		r.codes[userID][hash] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	used, ok := r.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.codes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *fakeMFARepository) CountRecoveryCodes(userID string) (int, error) {
+	count := 0
+	for _, used := range r.codes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeMFAChallenge struct {
+	userID   string
+	failures int
+}
+
+type fakeMFAChallengeRepository map[string]*fakeMFAChallenge
+
+func (r fakeMFAChallengeRepository) Create(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	r[tokenHash] = &fakeMFAChallenge{userID: userID}
+	return nil
+}
+
+func (r fakeMFAChallengeRepository) Get(ctx context.Context, tokenHash string) (string, error) {
+	challenge, ok := r[tokenHash]
+	if !ok {
+		return "", cache.ErrMFAChallengeNotFound
+	}
+	return challenge.userID, nil
+}
+
+func (r fakeMFAChallengeRepository) Fail(ctx context.Context, tokenHash string) (int, error) {
+	challenge, ok := r[tokenHash]
+	if !ok {
+		return 0, cache.ErrMFAChallengeNotFound
+	}
+	challenge.failures++
+	return challenge.failures, nil
+}
+
+func (r fakeMFAChallengeRepository) Delete(ctx context.Context, tokenHash string) (bool, error) {
+	_, ok := r[tokenHash]
+	delete(r, tokenHash)
+	return ok, nil
+}
+
+// setClock freezes the use case's clock at now.
+func (env *testEnv) setClock(now time.Time) {
+	env.uc.now = func() time.Time { return now }
+}
+
+// newUser creates a verified user who can log in with "secret1".
+func (env *testEnv) newUser(t *testing.T, role entity.UserRole) *entity.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret1"), bcrypt.MinCost)
+	require.NoError(t, err)
+	verifiedAt := time.Now()
+	user := &entity.User{
+		Email:           string(role) + "@example.com",
+		Username:        string(role),
+		Password:        string(hash),
+		Role:            role,
+		IsActive:        true,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	require.NoError(t, env.users.Create(user))
+	return user
+}
+
+// enableTOTP enrolls the user from a fresh session and returns the secret,
+// the session and the recovery codes.
+func (env *testEnv) enableTOTP(t *testing.T, user *entity.User, now time.Time) (string, string, []string) {
+	t.Helper()
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	claims, err := env.jwt.ValidateToken(result.Tokens.AccessToken)
+	require.NoError(t, err)
+
+	setup, err := env.uc.EnrollTOTP(user.ID)
+	require.NoError(t, err)
+	code, err := totp.Code(setup.Secret, now)
+	require.NoError(t, err)
+	codes, err := env.uc.ConfirmTOTP(user.ID, claims.SessionID, code)
+	require.NoError(t, err)
+	return setup.Secret, claims.SessionID, codes
+}
+
+func TestTOTP_EnrollAndLogin(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleCreator)
+
+	setup, err := env.uc.EnrollTOTP(user.ID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(setup.ProvisioningURI, "otpauth://totp/Lick%20Scroll:creator@example.com?"))
+
+	// Not enabled until confirmed
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	require.NotNil(t, result.Tokens)
+	claims, err := env.jwt.ValidateToken(result.Tokens.AccessToken)
+	require.NoError(t, err)
+	assert.False(t, claims.MFA)
+
+	_, err = env.uc.ConfirmTOTP(user.ID, claims.SessionID, "000000")
+	assert.EqualError(t, err, "invalid code")
+
+	code, _ := totp.Code(setup.Secret, now)
+	codes, err := env.uc.ConfirmTOTP(user.ID, claims.SessionID, code)
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	// The confirming session counts as two-factor from its next refresh
+	assert.True(t, env.sessions.mfa[claims.SessionID])
+
+	_, err = env.uc.EnrollTOTP(user.ID)
+	assert.EqualError(t, err, "two-factor authentication already enabled")
+
+	// The password alone now only gets an MFA token
+	result, err = env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	require.NotEmpty(t, result.MFAToken)
+
+	// The code used to confirm can't be used again
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, code, testClient)
+	assert.EqualError(t, err, "invalid code")
+
+	env.setClock(now.Add(totp.Period))
+	code, _ = totp.Code(setup.Secret, now.Add(totp.Period))
+	completed, err := env.uc.CompleteMFALogin(result.MFAToken, code, testClient)
+	require.NoError(t, err)
+	claims, err = env.jwt.ValidateToken(completed.Tokens.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, claims.MFA)
+	assert.True(t, env.sessions.mfa[claims.SessionID])
+	assert.Empty(t, completed.User.Password)
+
+	// The MFA token works once
+	env.setClock(now.Add(2 * totp.Period))
+	code, _ = totp.Code(setup.Secret, now.Add(2*totp.Period))
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, code, testClient)
+	assert.EqualError(t, err, "invalid or expired mfa token")
+}
+
+func TestCompleteMFALogin_RecoveryCode(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Now()
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleViewer)
+	_, _, codes := env.enableTOTP(t, user, now)
+
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	// Typed in lower case without dashes
+	typed := strings.ToLower(strings.ReplaceAll(codes[0], "-", ""))
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, typed, testClient)
+	require.NoError(t, err)
+
+	status, err := env.uc.GetMFAStatus(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesRemaining)
+
+	// Recovery codes work once
+	result, err = env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, codes[0], testClient)
+	assert.EqualError(t, err, "invalid code")
+}
+
+func TestCompleteMFALogin_TooManyFailures(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Now()
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleViewer)
+	_, _, codes := env.enableTOTP(t, user, now)
+
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	for i := 0; i < maxMFAChallengeFailures; i++ {
+		_, err = env.uc.CompleteMFALogin(result.MFAToken, "000000", testClient)
+		assert.EqualError(t, err, "invalid code")
+	}
+
+	// Even a good code needs a new password login now
+	_, err = env.uc.CompleteMFALogin(result.MFAToken, codes[0], testClient)
+	assert.EqualError(t, err, "invalid or expired mfa token")
+}
+
+func TestDisableTOTP(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Now()
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleCreator)
+	secret, sessionID, _ := env.enableTOTP(t, user, now)
+
+	assert.EqualError(t, env.uc.DisableTOTP(user.ID, "000000"), "invalid code")
+
+	env.setClock(now.Add(totp.Period))
+	code, _ := totp.Code(secret, now.Add(totp.Period))
+	require.NoError(t, env.uc.DisableTOTP(user.ID, code))
+	assert.False(t, env.sessions.mfa[sessionID])
+
+	status, err := env.uc.GetMFAStatus(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MFAStatus{Required: true}, status)
+
+	result, err := env.uc.Login(user.Email, "secret1", testClient)
+	require.NoError(t, err)
+	assert.NotNil(t, result.Tokens)
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Now()
+	env.setClock(now)
+	user := env.newUser(t, entity.RoleModerator)
+	_, _, oldCodes := env.enableTOTP(t, user, now)
+
+	newCodes, err := env.uc.RegenerateRecoveryCodes(user.ID, oldCodes[0])
+	require.NoError(t, err)
+	assert.Len(t, newCodes, recoveryCodeCount)
+
+	// The old codes are gone
+	_, err = env.uc.RegenerateRecoveryCodes(user.ID, oldCodes[1])
+	assert.EqualError(t, err, "invalid code")
+
+	status, err := env.uc.GetMFAStatus(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MFAStatus{Enabled: true, Required: true, RecoveryCodesRemaining: recoveryCodeCount}, status)
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/services/fanout/internal/repo/persistent"
+	"lick-scroll/services/fanout/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const fanoutQueueName = "fanout_queue"
+
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, queueClient *queue.Client) {
+	// Initialize repositories
+	fanoutRepo := persistent.NewFanoutRepository(db)
+
+	// Initialize UseCase
+	fanoutUseCase := usecase.NewFanoutUseCase(fanoutRepo, redisClient, log)
+
+	// Setup router
+	r := gin.Default()
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+
+	// Start consuming feed events
+	routingKeys := []string{
+		queue.EventPostCreated,
+		queue.EventPostDeleted,
+		queue.EventSubscriptionCreated,
+		queue.EventSubscriptionDeleted,
+	}
+	err := queueClient.ConsumeEvents(fanoutQueueName, routingKeys, func(routingKey string, event map[string]interface{}) error {
+		switch routingKey {
+		case queue.EventPostCreated:
+			return fanoutUseCase.HandlePostCreated(event)
+		case queue.EventPostDeleted:
+			return fanoutUseCase.HandlePostDeleted(event)
+		case queue.EventSubscriptionCreated:
+			return fanoutUseCase.HandleSubscriptionCreated(event)
+		case queue.EventSubscriptionDeleted:
+			return fanoutUseCase.HandleSubscriptionDeleted(event)
+		default:
+			return fmt.Errorf("unknown event: %s", routingKey)
+		}
+	})
+	if err != nil {
+		log.Error("Failed to start event consumer: %v", err)
+		panic(err)
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Info("Fanout service starting on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Failed to start server: %v", err)
+			panic(err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info("Shutting down fanout service...")
+
+	// The context is used to inform the server it has 5 seconds to finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Close RabbitMQ connection first so no event is half-processed on shutdown
+	queueClient.Close()
+
+	// Close database connection
+	sqlDB, err := db.DB()
+	if err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Error("Error closing database: %v", err)
+		}
+	}
+
+	// Close Redis connection
+	if err := redisClient.Close(); err != nil {
+		log.Error("Error closing Redis: %v", err)
+	}
+
+	// Shutdown server
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("Server forced to shutdown: %v", err)
+		panic(err)
+	}
+
+	log.Info("Fanout service exited")
+}
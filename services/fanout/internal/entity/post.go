@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type Post struct {
+	ID             string
+	CreatorID      string
+	Status         string
+	SubscriberOnly bool
+	CreatedAt      time.Time
+}
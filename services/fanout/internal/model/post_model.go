@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type PostModel struct {
+	ID             string `gorm:"type:uuid;primary_key"`
+	CreatorID      string `gorm:"type:uuid;not null;index"`
+	Status         string `gorm:"type:varchar(20);default:'pending'"`
+	SubscriberOnly bool   `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+	DeletedAt      *time.Time `gorm:"index"`
+}
+
+func (PostModel) TableName() string {
+	return "posts"
+}
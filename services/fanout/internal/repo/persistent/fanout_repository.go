@@ -0,0 +1,89 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/fanout/internal/entity"
+	"lick-scroll/services/fanout/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrPostNotFound = errors.New("post not found")
+
+type FanoutRepository interface {
+	GetPost(postID string) (*entity.Post, error)
+	GetFollowerIDs(creatorID string, paidOnly bool, afterViewerID string, limit int) ([]string, error)
+	GetVisiblePosts(creatorID, viewerID string, limit int) ([]*entity.Post, error)
+	GetPostIDsByCreator(creatorID string) ([]string, error)
+}
+
+type fanoutRepository struct {
+	db *gorm.DB
+}
+
+func NewFanoutRepository(db *gorm.DB) FanoutRepository {
+	return &fanoutRepository{db: db}
+}
+
+func (r *fanoutRepository) GetPost(postID string) (*entity.Post, error) {
+	var postModel model.PostModel
+	err := r.db.Where("id = ? AND deleted_at IS NULL", postID).First(&postModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	return ToPostEntity(&postModel), nil
+}
+
+// GetFollowerIDs returns a page of the creator's subscribers ordered by viewer
+// ID; pass the last ID of the previous page as afterViewerID. With paidOnly
+// only viewers with an active paid subscription are returned.
+func (r *fanoutRepository) GetFollowerIDs(creatorID string, paidOnly bool, afterViewerID string, limit int) ([]string, error) {
+	query := r.db.Table("subscriptions").
+		Where("creator_id = ? AND deleted_at IS NULL", creatorID)
+	if paidOnly {
+		query = query.Where("type = ? AND current_period_end > ?", "paid", time.Now())
+	}
+	if afterViewerID != "" {
+		query = query.Where("viewer_id > ?", afterViewerID)
+	}
+
+	var viewerIDs []string
+	err := query.Order("viewer_id").Limit(limit).Pluck("viewer_id", &viewerIDs).Error
+	return viewerIDs, err
+}
+
+// GetVisiblePosts returns the creator's newest approved posts that the viewer
+// is allowed to see.
+func (r *fanoutRepository) GetVisiblePosts(creatorID, viewerID string, limit int) ([]*entity.Post, error) {
+	var postModels []model.PostModel
+	err := r.db.
+		Where("creator_id = ? AND status = ? AND deleted_at IS NULL", creatorID, "approved").
+		Where(`(subscriber_only = ? OR EXISTS (
+			SELECT 1 FROM subscriptions
+			WHERE subscriptions.viewer_id = ? AND subscriptions.creator_id = posts.creator_id
+			AND subscriptions.type = ? AND subscriptions.current_period_end > ? AND subscriptions.deleted_at IS NULL))`,
+			false, viewerID, "paid", time.Now()).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&postModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*entity.Post, len(postModels))
+	for i := range postModels {
+		posts[i] = ToPostEntity(&postModels[i])
+	}
+	return posts, nil
+}
+
+func (r *fanoutRepository) GetPostIDsByCreator(creatorID string) ([]string, error) {
+	var postIDs []string
+	err := r.db.Model(&model.PostModel{}).Where("creator_id = ?", creatorID).Pluck("id", &postIDs).Error
+	return postIDs, err
+}
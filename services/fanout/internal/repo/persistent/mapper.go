@@ -0,0 +1,20 @@
+package persistent
+
+import (
+	"lick-scroll/services/fanout/internal/entity"
+	"lick-scroll/services/fanout/internal/model"
+)
+
+func ToPostEntity(m *model.PostModel) *entity.Post {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.Post{
+		ID:             m.ID,
+		CreatorID:      m.CreatorID,
+		Status:         m.Status,
+		SubscriberOnly: m.SubscriberOnly,
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/timeline"
+	"lick-scroll/services/fanout/internal/repo/persistent"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// followerBatchSize bounds how many subscribers are loaded at once while
+// fanning a post out to a large creator's audience.
+const followerBatchSize = 500
+
+type FanoutUseCase interface {
+	HandlePostCreated(event map[string]interface{}) error
+	HandlePostDeleted(event map[string]interface{}) error
+	HandleSubscriptionCreated(event map[string]interface{}) error
+	HandleSubscriptionDeleted(event map[string]interface{}) error
+}
+
+type fanoutUseCase struct {
+	fanoutRepo  persistent.FanoutRepository
+	redisClient *redis.Client
+	logger      *logger.Logger
+}
+
+func NewFanoutUseCase(fanoutRepo persistent.FanoutRepository, redisClient *redis.Client, logger *logger.Logger) FanoutUseCase {
+	return &fanoutUseCase{
+		fanoutRepo:  fanoutRepo,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// HandlePostCreated pushes a newly approved post into the timelines of the
+// creator's subscribers. Subscriber-only posts only reach paid subscribers.
+func (uc *fanoutUseCase) HandlePostCreated(event map[string]interface{}) error {
+	postID, _ := event["post_id"].(string)
+	if postID == "" {
+		uc.logger.Error("Invalid post created event: %+v", event)
+		return nil
+	}
+
+	post, err := uc.fanoutRepo.GetPost(postID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrPostNotFound) {
+			uc.logger.Info("Post %s was deleted before fan-out, skipping", postID)
+			return nil
+		}
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+	if post.Status != "approved" {
+		uc.logger.Info("Post %s is %s, skipping fan-out", postID, post.Status)
+		return nil
+	}
+
+	ctx := context.Background()
+	entry := timeline.Entry{PostID: post.ID, CreatedAt: post.CreatedAt}
+	count, err := uc.forEachFollower(post.CreatorID, post.SubscriberOnly, func(viewerID string) error {
+		return timeline.Add(ctx, uc.redisClient, viewerID, entry)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fan out post %s: %w", postID, err)
+	}
+
+	uc.logger.Info("Fanned out post %s to %d subscribers", postID, count)
+	return nil
+}
+
+// HandlePostDeleted removes a deleted post from every subscriber's timeline.
+func (uc *fanoutUseCase) HandlePostDeleted(event map[string]interface{}) error {
+	postID, _ := event["post_id"].(string)
+	creatorID, _ := event["creator_id"].(string)
+	if postID == "" || creatorID == "" {
+		uc.logger.Error("Invalid post deleted event: %+v", event)
+		return nil
+	}
+
+	ctx := context.Background()
+	count, err := uc.forEachFollower(creatorID, false, func(viewerID string) error {
+		return timeline.Remove(ctx, uc.redisClient, viewerID, postID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove post %s from timelines: %w", postID, err)
+	}
+
+	uc.logger.Info("Removed post %s from %d timelines", postID, count)
+	return nil
+}
+
+// HandleSubscriptionCreated backfills the creator's recent posts into the new
+// subscriber's timeline.
+func (uc *fanoutUseCase) HandleSubscriptionCreated(event map[string]interface{}) error {
+	viewerID, creatorID, ok := uc.subscriptionFromEvent(event)
+	if !ok {
+		return nil
+	}
+
+	posts, err := uc.fanoutRepo.GetVisiblePosts(creatorID, viewerID, timeline.MaxSize)
+	if err != nil {
+		return fmt.Errorf("failed to get creator posts: %w", err)
+	}
+
+	entries := make([]timeline.Entry, len(posts))
+	for i, post := range posts {
+		entries[i] = timeline.Entry{PostID: post.ID, CreatedAt: post.CreatedAt}
+	}
+	if err := timeline.Add(context.Background(), uc.redisClient, viewerID, entries...); err != nil {
+		return fmt.Errorf("failed to backfill timeline: %w", err)
+	}
+	return nil
+}
+
+// HandleSubscriptionDeleted removes the creator's posts from the former
+// subscriber's timeline.
+func (uc *fanoutUseCase) HandleSubscriptionDeleted(event map[string]interface{}) error {
+	viewerID, creatorID, ok := uc.subscriptionFromEvent(event)
+	if !ok {
+		return nil
+	}
+
+	postIDs, err := uc.fanoutRepo.GetPostIDsByCreator(creatorID)
+	if err != nil {
+		return fmt.Errorf("failed to get creator posts: %w", err)
+	}
+	if err := timeline.Remove(context.Background(), uc.redisClient, viewerID, postIDs...); err != nil {
+		return fmt.Errorf("failed to clean up timeline: %w", err)
+	}
+	return nil
+}
+
+// forEachFollower calls fn for each of the creator's subscribers and returns
+// how many there were.
+func (uc *fanoutUseCase) forEachFollower(creatorID string, paidOnly bool, fn func(viewerID string) error) (int, error) {
+	count := 0
+	afterViewerID := ""
+	for {
+		viewerIDs, err := uc.fanoutRepo.GetFollowerIDs(creatorID, paidOnly, afterViewerID, followerBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, viewerID := range viewerIDs {
+			if err := fn(viewerID); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if len(viewerIDs) < followerBatchSize {
+			return count, nil
+		}
+		afterViewerID = viewerIDs[len(viewerIDs)-1]
+	}
+}
+
+func (uc *fanoutUseCase) subscriptionFromEvent(event map[string]interface{}) (viewerID, creatorID string, ok bool) {
+	viewerID, _ = event["viewer_id"].(string)
+	creatorID, _ = event["creator_id"].(string)
+	if viewerID == "" || creatorID == "" {
+		uc.logger.Error("Invalid subscription event: %+v", event)
+		return "", "", false
+	}
+	return viewerID, creatorID, true
+}
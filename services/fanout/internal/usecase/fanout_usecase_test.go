@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/fanout/internal/entity"
+	"lick-scroll/services/fanout/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFanoutRepository struct {
+	posts       map[string]*entity.Post
+	err         error
+	followerHit bool
+}
+
+func (r *fakeFanoutRepository) GetPost(postID string) (*entity.Post, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	post, ok := r.posts[postID]
+	if !ok {
+		return nil, persistent.ErrPostNotFound
+	}
+	return post, nil
+}
+
+func (r *fakeFanoutRepository) GetFollowerIDs(creatorID string, paidOnly bool, afterViewerID string, limit int) ([]string, error) {
+	r.followerHit = true
+	return nil, r.err
+}
+
+func (r *fakeFanoutRepository) GetVisiblePosts(creatorID, viewerID string, limit int) ([]*entity.Post, error) {
+	return nil, r.err
+}
+
+func (r *fakeFanoutRepository) GetPostIDsByCreator(creatorID string) ([]string, error) {
+	return nil, r.err
+}
+
+func TestHandlePostCreated_SkipsPostsThatCannotBeFannedOut(t *testing.T) {
+	repo := &fakeFanoutRepository{posts: map[string]*entity.Post{
+		"pending": {ID: "pending", CreatorID: "creator", Status: "pending"},
+	}}
+	uc := NewFanoutUseCase(repo, nil, logger.New())
+
+	// Malformed, deleted and unapproved posts are acknowledged without retrying
+	assert.NoError(t, uc.HandlePostCreated(map[string]interface{}{}))
+	assert.NoError(t, uc.HandlePostCreated(map[string]interface{}{"post_id": "deleted"}))
+	assert.NoError(t, uc.HandlePostCreated(map[string]interface{}{"post_id": "pending"}))
+	assert.False(t, repo.followerHit)
+}
+
+func TestHandleEvents_RetriesOnRepositoryErrors(t *testing.T) {
+	repo := &fakeFanoutRepository{err: assert.AnError}
+	uc := NewFanoutUseCase(repo, nil, logger.New())
+
+	assert.Error(t, uc.HandlePostCreated(map[string]interface{}{"post_id": "post"}))
+	assert.Error(t, uc.HandlePostDeleted(map[string]interface{}{"post_id": "post", "creator_id": "creator"}))
+	assert.Error(t, uc.HandleSubscriptionCreated(map[string]interface{}{"viewer_id": "viewer", "creator_id": "creator"}))
+	assert.Error(t, uc.HandleSubscriptionDeleted(map[string]interface{}{"viewer_id": "viewer", "creator_id": "creator"}))
+
+	// Malformed events are dropped rather than requeued forever
+	assert.NoError(t, uc.HandlePostDeleted(map[string]interface{}{"post_id": "post"}))
+	assert.NoError(t, uc.HandleSubscriptionCreated(map[string]interface{}{"creator_id": "creator"}))
+}
@@ -12,6 +12,7 @@ import (
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/s3"
 	feedHTTP "lick-scroll/services/feed/internal/controller/http"
 	"lick-scroll/services/feed/internal/repo/persistent"
 	"lick-scroll/services/feed/internal/usecase"
@@ -26,20 +27,15 @@ import (
 	_ "lick-scroll/services/feed/docs" // Swagger docs
 )
 
-func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
-	
-	// Use auth service URL from config, default to Docker service name
-	authServiceURL := cfg.AuthServiceURL
-	if authServiceURL == "http://localhost:8001" {
-		authServiceURL = "http://auth-service:8001"
-	}
-	
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, s3Client *s3.Client) {
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
+
 	// Initialize Repository
 	feedRepo := persistent.NewFeedRepository(db)
 	
 	// Initialize UseCase
-	feedUseCase := usecase.NewFeedUseCase(feedRepo, redisClient, log, authServiceURL, cfg)
+	feedUseCase := usecase.NewFeedUseCase(feedRepo, redisClient, s3Client, usecase.NewEngagementScorer(usecase.DefaultEngagementWeights()), log, cfg)
 	
 	// Initialize HTTP handlers
 	feedHandler := feedHTTP.NewFeedHandler(feedUseCase, log)
@@ -66,7 +62,7 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(jwtService))
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
 	api.Use(middleware.RateLimitMiddleware(redisClient, 200, time.Minute)) // 200 requests per minute
 
 	{
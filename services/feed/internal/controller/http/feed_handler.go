@@ -24,19 +24,20 @@ func NewFeedHandler(feedUseCase usecase.FeedUseCase, logger *logger.Logger) *Fee
 
 // GetFeed godoc
 // @Summary      Get personalized feed
-// @Description  Get personalized feed based on subscriptions: posts from subscribed creators first, then other posts
+// @Description  Get the personalized feed. The default mode returns the newest posts from followed creators, or the newest posts from everyone when the user follows nobody. The for_you mode ranks recent posts from everyone by likes, views, donations, recency and the categories the user likes.
 // @Tags         feed
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        mode query string false "Feed mode" Enums(following, for_you)
 // @Param        limit query int false "Number of posts to return (max 100)"
-// @Param        offset query int false "Offset for pagination"
+// @Param        cursor query string false "next_cursor from the previous page"
 // @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
 // @Router       /feed [get]
 func (h *FeedHandler) GetFeed(c *gin.Context) {
 	userID := c.GetString("user_id")
 	limit := 100
-	offset := 0
 
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
@@ -44,20 +45,29 @@ func (h *FeedHandler) GetFeed(c *gin.Context) {
 		}
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	var posts []map[string]interface{}
+	var nextCursor string
+	var err error
+	switch mode := c.DefaultQuery("mode", "following"); mode {
+	case "following":
+		posts, nextCursor, err = h.feedUseCase.GetFeed(userID, limit, c.Query("cursor"))
+	case "for_you":
+		posts, nextCursor, err = h.feedUseCase.GetForYouFeed(userID, limit, c.Query("cursor"))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed mode"})
+		return
 	}
-
-	posts, count, err := h.feedUseCase.GetFeed(userID, limit, offset, c.GetHeader("Authorization"))
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to get feed: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feed"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": count, "offset": offset})
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "next_cursor": nextCursor})
 }
 
 // GetFeedByCategory godoc
@@ -10,6 +10,7 @@ type Post struct {
 	Type         string
 	MediaURL     string
 	ThumbnailURL string
+	PreviewURL   string
 	Category     string
 	Status       string
 	Views        int
@@ -24,5 +25,6 @@ type PostImage struct {
 	PostID       string
 	ImageURL     string
 	ThumbnailURL string
+	PreviewURL   string
 	Order        int
 }
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Candidate is a post considered for the "For You" feed together with the
+// engagement it has collected.
+type Candidate struct {
+	PostID    string
+	CreatorID string
+	Category  string
+	CreatedAt time.Time
+	Likes     int64
+	Views     int64
+	Donations int64
+}
+
+// ViewerProfile describes what the viewer engages with. CategoryAffinity is
+// the share of the viewer's likes that went to each category.
+type ViewerProfile struct {
+	CategoryAffinity map[string]float64
+}
@@ -10,6 +10,7 @@ type PostModel struct {
 	Type         string           `gorm:"type:varchar(20);not null"`
 	MediaURL     string           `gorm:"type:varchar(500)"`
 	ThumbnailURL string           `gorm:"type:varchar(500)"`
+	PreviewURL   string           `gorm:"type:varchar(500)"`
 	Category     string           `gorm:"type:varchar(100)"`
 	Status       string           `gorm:"type:varchar(20);default:'pending'"`
 	Views        int              `gorm:"default:0"`
@@ -29,6 +30,7 @@ type PostImageModel struct {
 	PostID       string `gorm:"type:uuid;not null;index"`
 	ImageURL     string `gorm:"type:varchar(500);not null"`
 	ThumbnailURL string `gorm:"type:varchar(500)"`
+	PreviewURL   string `gorm:"type:varchar(500)"`
 	Order        int    `gorm:"default:0;index"`
 }
 
@@ -2,16 +2,26 @@ package persistent
 
 import (
 	"database/sql"
+	"time"
+
+	"lick-scroll/pkg/pagination"
+	"lick-scroll/services/feed/internal/entity"
+	"lick-scroll/services/feed/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type FeedRepository interface {
-	GetPostsByCreatorIDs(creatorIDs []string, limit int) ([]map[string]interface{}, error)
-	GetOtherPosts(userID string, excludeCreatorIDs []string, limit int) ([]map[string]interface{}, error)
+	GetFollowedPosts(userID string, limit int) ([]*entity.Post, error)
+	GetRecentPosts(userID string, limit int, after *pagination.Cursor) ([]*entity.Post, error)
+	GetPostsByIDs(userID string, postIDs []string) ([]map[string]interface{}, error)
+	GetRankingCandidates(userID string, since time.Time, limit int) ([]*entity.Candidate, error)
+	GetLikedCategoryCounts(userID string) (map[string]int64, error)
 	IsLiked(userID, postID string) (bool, error)
 	GetLikeCount(postID string) (int64, error)
 	GetCreatorInfo(creatorID string) (map[string]interface{}, error)
+	GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error)
+	HasActiveSubscription(userID, creatorID string) (bool, error)
 }
 
 type feedRepository struct {
@@ -22,17 +32,65 @@ func NewFeedRepository(db *gorm.DB) FeedRepository {
 	return &feedRepository{db: db}
 }
 
-func (r *feedRepository) GetPostsByCreatorIDs(creatorIDs []string, limit int) ([]map[string]interface{}, error) {
-	if len(creatorIDs) == 0 {
+// GetFollowedPosts returns the newest posts the user can see from the
+// creators they follow, to rebuild their timeline.
+func (r *feedRepository) GetFollowedPosts(userID string, limit int) ([]*entity.Post, error) {
+	var postModels []model.PostModel
+	err := r.db.
+		Select("posts.id, posts.creator_id, posts.created_at").
+		Where("posts.creator_id IN (?)", r.db.Table("subscriptions").Select("creator_id").Where("viewer_id = ? AND deleted_at IS NULL", userID)).
+		Where("posts.deleted_at IS NULL AND posts.status = ?", "approved").
+		Scopes(visibleTo(userID)).
+		Order("posts.created_at DESC").
+		Limit(limit).
+		Find(&postModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*entity.Post, len(postModels))
+	for i := range postModels {
+		posts[i] = ToPostEntity(&postModels[i])
+	}
+	return posts, nil
+}
+
+// GetRecentPosts returns the newest posts from everyone but the user.
+func (r *feedRepository) GetRecentPosts(userID string, limit int, after *pagination.Cursor) ([]*entity.Post, error) {
+	query := r.db.
+		Select("posts.id, posts.creator_id, posts.created_at").
+		Where("posts.deleted_at IS NULL AND posts.status = ?", "approved").
+		Scopes(visibleTo(userID), pagination.Scope("posts.created_at", "posts.id", after)).
+		Limit(limit)
+
+	if userID != "" {
+		query = query.Where("posts.creator_id != ?", userID)
+	}
+
+	var postModels []model.PostModel
+	if err := query.Find(&postModels).Error; err != nil {
+		return nil, err
+	}
+
+	posts := make([]*entity.Post, len(postModels))
+	for i := range postModels {
+		posts[i] = ToPostEntity(&postModels[i])
+	}
+	return posts, nil
+}
+
+// GetPostsByIDs loads the given posts with their images, skipping ones that
+// were deleted or that the user can no longer see.
+func (r *feedRepository) GetPostsByIDs(userID string, postIDs []string) ([]map[string]interface{}, error) {
+	if len(postIDs) == 0 {
 		return []map[string]interface{}{}, nil
 	}
 
 	query := r.db.Table("posts").
-		Select("posts.id, posts.creator_id, posts.title, posts.description, posts.type, posts.media_url, posts.thumbnail_url, posts.category, posts.status, posts.views, posts.purchases, posts.created_at, posts.updated_at, post_images.id as image_id, post_images.image_url, post_images.thumbnail_url, post_images.\"order\" as image_order").
+		Select("posts.id, posts.creator_id, posts.title, posts.description, posts.type, posts.media_url, posts.thumbnail_url, posts.preview_url, posts.category, posts.price, posts.status, posts.views, posts.purchases, posts.created_at, posts.updated_at, post_images.id as image_id, post_images.image_url, post_images.thumbnail_url, post_images.preview_url, post_images.\"order\" as image_order").
 		Joins("LEFT JOIN post_images ON posts.id = post_images.post_id").
-		Where("posts.creator_id IN ? AND posts.deleted_at IS NULL AND (posts.status IS NULL OR posts.status = '' OR posts.status != ?)", creatorIDs, "rejected").
-		Order("posts.created_at DESC").
-		Limit(limit)
+		Where("posts.id IN ? AND posts.deleted_at IS NULL AND posts.status = ?", postIDs, "approved").
+		Scopes(visibleTo(userID))
 
 	rows, err := query.Rows()
 	if err != nil {
@@ -43,11 +101,29 @@ func (r *feedRepository) GetPostsByCreatorIDs(creatorIDs []string, limit int) ([
 	return r.scanPostsFromRows(rows), nil
 }
 
-func (r *feedRepository) GetOtherPosts(userID string, excludeCreatorIDs []string, limit int) ([]map[string]interface{}, error) {
+// GetRankingCandidates returns the newest posts since the given time that the
+// user can see, with their like count, stored view count and donations that
+// were not refunded. The user's own posts are skipped.
+func (r *feedRepository) GetRankingCandidates(userID string, since time.Time, limit int) ([]*entity.Candidate, error) {
+	var rows []struct {
+		ID        string
+		CreatorID string
+		Category  string
+		CreatedAt time.Time
+		Likes     int64
+		Views     int64
+		Donations int64
+	}
+
 	query := r.db.Table("posts").
-		Select("posts.id, posts.creator_id, posts.title, posts.description, posts.type, posts.media_url, posts.thumbnail_url, posts.category, posts.status, posts.views, posts.purchases, posts.created_at, posts.updated_at, post_images.id as image_id, post_images.image_url, post_images.thumbnail_url, post_images.\"order\" as image_order").
-		Joins("LEFT JOIN post_images ON posts.id = post_images.post_id").
-		Where("posts.deleted_at IS NULL AND (posts.status IS NULL OR posts.status = '' OR posts.status != ?)", "rejected").
+		Select(`posts.id, posts.creator_id, posts.category, posts.created_at, posts.views,
+			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id AND likes.deleted_at IS NULL) AS likes,
+			(SELECT COALESCE(SUM(-transactions.amount), 0) FROM transactions
+				WHERE transactions.post_id = posts.id AND transactions.type = ? AND transactions.amount < 0
+				AND NOT EXISTS (SELECT 1 FROM transactions refunds WHERE refunds.refunded_transaction_id = transactions.id)) AS donations`,
+			"donation").
+		Where("posts.deleted_at IS NULL AND posts.status = ? AND posts.created_at >= ?", "approved", since).
+		Scopes(visibleTo(userID)).
 		Order("posts.created_at DESC").
 		Limit(limit)
 
@@ -55,17 +131,51 @@ func (r *feedRepository) GetOtherPosts(userID string, excludeCreatorIDs []string
 		query = query.Where("posts.creator_id != ?", userID)
 	}
 
-	if len(excludeCreatorIDs) > 0 {
-		query = query.Where("posts.creator_id NOT IN ?", excludeCreatorIDs)
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	rows, err := query.Rows()
+	candidates := make([]*entity.Candidate, len(rows))
+	for i, row := range rows {
+		candidates[i] = &entity.Candidate{
+			PostID:    row.ID,
+			CreatorID: row.CreatorID,
+			Category:  row.Category,
+			CreatedAt: row.CreatedAt,
+			Likes:     row.Likes,
+			Views:     row.Views,
+			Donations: row.Donations,
+		}
+	}
+	return candidates, nil
+}
+
+// GetLikedCategoryCounts returns how many posts of each category the user
+// has liked.
+func (r *feedRepository) GetLikedCategoryCounts(userID string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if userID == "" {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Category string
+		Count    int64
+	}
+	err := r.db.Table("likes").
+		Select("posts.category, COUNT(*) AS count").
+		Joins("JOIN posts ON posts.id = likes.post_id").
+		Where("likes.user_id = ? AND likes.deleted_at IS NULL AND posts.deleted_at IS NULL", userID).
+		Group("posts.category").
+		Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	return r.scanPostsFromRows(rows), nil
+	for _, row := range rows {
+		counts[row.Category] = row.Count
+	}
+	return counts, nil
 }
 
 func (r *feedRepository) IsLiked(userID, postID string) (bool, error) {
@@ -95,16 +205,64 @@ func (r *feedRepository) GetCreatorInfo(creatorID string) (map[string]interface{
 	}, nil
 }
 
+func (r *feedRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	purchased := make(map[string]bool)
+	if userID == "" || len(postIDs) == 0 {
+		return purchased, nil
+	}
+
+	var ids []string
+	err := r.db.Table("transactions").
+		Where("user_id = ? AND post_id IN ? AND type = ?", userID, postIDs, "purchase").
+		Distinct("post_id").
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		purchased[id] = true
+	}
+	return purchased, nil
+}
+
+func (r *feedRepository) HasActiveSubscription(userID, creatorID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+
+	var count int64
+	err := r.db.Table("subscriptions").
+		Where("viewer_id = ? AND creator_id = ? AND type = ? AND current_period_end > ? AND deleted_at IS NULL", userID, creatorID, "paid", time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// visibleTo hides subscriber-only posts unless the user created them or has
+// an active paid subscription to their creator.
+func visibleTo(userID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID == "" {
+			return db.Where("posts.subscriber_only = ?", false)
+		}
+		return db.Where(`(posts.subscriber_only = ? OR posts.creator_id = ? OR EXISTS (
+			SELECT 1 FROM subscriptions
+			WHERE subscriptions.viewer_id = ? AND subscriptions.creator_id = posts.creator_id
+			AND subscriptions.type = ? AND subscriptions.current_period_end > ? AND subscriptions.deleted_at IS NULL))`,
+			false, userID, userID, "paid", time.Now())
+	}
+}
+
 func (r *feedRepository) scanPostsFromRows(rows *sql.Rows) []map[string]interface{} {
 	postMap := make(map[string]map[string]interface{})
 	for rows.Next() {
-		var postID, creatorID, title, description, postType, mediaURL, thumbnailURL, category, status sql.NullString
-		var views, purchases sql.NullInt32
+		var postID, creatorID, title, description, postType, mediaURL, thumbnailURL, previewURL, category, status sql.NullString
+		var price, views, purchases sql.NullInt32
 		var createdAt, updatedAt sql.NullTime
-		var imageID, imageURL, imageThumbnailURL sql.NullString
+		var imageID, imageURL, imageThumbnailURL, imagePreviewURL sql.NullString
 		var imageOrder sql.NullInt32
 
-		if err := rows.Scan(&postID, &creatorID, &title, &description, &postType, &mediaURL, &thumbnailURL, &category, &status, &views, &purchases, &createdAt, &updatedAt, &imageID, &imageURL, &imageThumbnailURL, &imageOrder); err != nil {
+		if err := rows.Scan(&postID, &creatorID, &title, &description, &postType, &mediaURL, &thumbnailURL, &previewURL, &category, &price, &status, &views, &purchases, &createdAt, &updatedAt, &imageID, &imageURL, &imageThumbnailURL, &imagePreviewURL, &imageOrder); err != nil {
 			continue
 		}
 
@@ -117,7 +275,9 @@ func (r *feedRepository) scanPostsFromRows(rows *sql.Rows) []map[string]interfac
 				"type":         postType.String,
 				"media_url":    mediaURL.String,
 				"thumbnail_url": thumbnailURL.String,
+				"preview_url":   previewURL.String,
 				"category":     category.String,
+				"price":        int(price.Int32),
 				"status":       status.String,
 				"views":        int(views.Int32),
 				"purchases":    int(purchases.Int32),
@@ -134,6 +294,7 @@ func (r *feedRepository) scanPostsFromRows(rows *sql.Rows) []map[string]interfac
 				"post_id":      postID.String,
 				"image_url":    imageURL.String,
 				"thumbnail_url": imageThumbnailURL.String,
+				"preview_url":   imagePreviewURL.String,
 				"order":        int(imageOrder.Int32),
 			})
 			postMap[postID.String]["images"] = images
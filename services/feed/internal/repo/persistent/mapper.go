@@ -17,6 +17,7 @@ func ToPostEntity(m *model.PostModel) *entity.Post {
 			PostID:       img.PostID,
 			ImageURL:     img.ImageURL,
 			ThumbnailURL: img.ThumbnailURL,
+			PreviewURL:   img.PreviewURL,
 			Order:        img.Order,
 		}
 	}
@@ -29,6 +30,7 @@ func ToPostEntity(m *model.PostModel) *entity.Post {
 		Type:         m.Type,
 		MediaURL:     m.MediaURL,
 		ThumbnailURL: m.ThumbnailURL,
+		PreviewURL:   m.PreviewURL,
 		Category:     m.Category,
 		Status:       m.Status,
 		Views:        m.Views,
@@ -51,6 +53,7 @@ func ToPostModel(e *entity.Post) *model.PostModel {
 			PostID:       img.PostID,
 			ImageURL:     img.ImageURL,
 			ThumbnailURL: img.ThumbnailURL,
+			PreviewURL:   img.PreviewURL,
 			Order:        img.Order,
 		}
 	}
@@ -63,6 +66,7 @@ func ToPostModel(e *entity.Post) *model.PostModel {
 		Type:         e.Type,
 		MediaURL:     e.MediaURL,
 		ThumbnailURL: e.ThumbnailURL,
+		PreviewURL:   e.PreviewURL,
 		Category:     e.Category,
 		Status:       e.Status,
 		Views:        e.Views,
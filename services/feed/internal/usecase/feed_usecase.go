@@ -4,91 +4,341 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"sort"
+	"strconv"
 	"time"
 
 	"lick-scroll/pkg/config"
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
+	"lick-scroll/pkg/timeline"
+	"lick-scroll/services/feed/internal/entity"
 	"lick-scroll/services/feed/internal/repo/persistent"
 
 	"github.com/redis/go-redis/v9"
 )
 
 type FeedUseCase interface {
-	GetFeed(userID string, limit, offset int, authToken string) ([]map[string]interface{}, int, error)
+	GetFeed(userID string, limit int, cursor string) ([]map[string]interface{}, string, error)
+	GetForYouFeed(userID string, limit int, cursor string) ([]map[string]interface{}, string, error)
 	GetFeedByCategory(userID, category string, limit, offset int) ([]map[string]interface{}, error)
 }
 
+// MediaSigner turns object keys into URLs clients can load; *s3.Client
+// implements it.
+type MediaSigner interface {
+	MediaURL(key string) string
+}
+
 type feedUseCase struct {
-	feedRepo       persistent.FeedRepository
-	redisClient    *redis.Client
-	logger         *logger.Logger
-	authServiceURL string
-	config         *config.Config
+	feedRepo    persistent.FeedRepository
+	redisClient *redis.Client
+	s3Client    MediaSigner
+	scorer      Scorer
+	logger      *logger.Logger
+	config      *config.Config
 }
 
-func NewFeedUseCase(feedRepo persistent.FeedRepository, redisClient *redis.Client, logger *logger.Logger, authServiceURL string, cfg *config.Config) FeedUseCase {
+const (
+	// forYouWindow and forYouCandidates bound the posts ranked for the
+	// "For You" feed: the newest ones published within the window.
+	forYouWindow     = 7 * 24 * time.Hour
+	forYouCandidates = 500
+	// forYouRankingTTL is how long a ranking is kept for paging through it.
+	forYouRankingTTL = 30 * time.Minute
+)
+
+func NewFeedUseCase(feedRepo persistent.FeedRepository, redisClient *redis.Client, s3Client MediaSigner, scorer Scorer, logger *logger.Logger, cfg *config.Config) FeedUseCase {
 	return &feedUseCase{
-		feedRepo:       feedRepo,
-		redisClient:    redisClient,
-		logger:         logger,
-		authServiceURL: authServiceURL,
-		config:         cfg,
+		feedRepo:    feedRepo,
+		redisClient: redisClient,
+		s3Client:    s3Client,
+		scorer:      scorer,
+		logger:      logger,
+		config:      cfg,
 	}
 }
 
-func (uc *feedUseCase) GetFeed(userID string, limit, offset int, authToken string) ([]map[string]interface{}, int, error) {
+// GetFeed pages through the user's precomputed timeline, rebuilding it from
+// Postgres when it has expired. Users who follow nobody get the newest posts
+// from everyone instead.
+func (uc *feedUseCase) GetFeed(userID string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
+	var afterEntry *timeline.Entry
+	if after != nil {
+		afterEntry = &timeline.Entry{PostID: after.ID, CreatedAt: after.CreatedAt}
+	}
+
+	entries, found, err := uc.getTimelinePage(userID, afterEntry, limit)
+	if err != nil {
+		uc.logger.Error("Failed to get timeline: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch feed")
+	}
+
+	if !found {
+		recent, err := uc.feedRepo.GetRecentPosts(userID, limit, after)
+		if err != nil {
+			uc.logger.Error("Failed to get recent posts: %v", err)
+			return nil, "", fmt.Errorf("failed to fetch feed")
+		}
+		entries = toTimelineEntries(recent)
+	}
+
+	postIDs := make([]string, len(entries))
+	position := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		postIDs[i] = entry.PostID
+		position[entry.PostID] = i
+	}
+
+	posts, err := uc.feedRepo.GetPostsByIDs(userID, postIDs)
+	if err != nil {
+		uc.logger.Error("Failed to get feed posts: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch feed")
+	}
+
+	// Posts deleted or hidden since they were added to the timeline are
+	// missing from the result; keep the rest in timeline order
+	sort.Slice(posts, func(i, j int) bool {
+		return position[posts[i]["id"].(string)] < position[posts[j]["id"].(string)]
+	})
+
+	// The cursor follows the timeline rather than the posts that survived
+	// filtering, so a page of hidden posts doesn't end the feed early
+	nextCursor := ""
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		nextCursor = pagination.Encode(last.CreatedAt, last.PostID)
+	}
+
+	return uc.formatPosts(userID, posts), nextCursor, nil
+}
+
+// GetForYouFeed ranks recent posts from everyone by engagement and the
+// user's interests. The first page computes a new ranking and stores it in
+// Redis; the cursor points into that ranking, so scores changing while the
+// user scrolls don't reorder pages they have not seen yet.
+func (uc *feedUseCase) GetForYouFeed(userID string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
 	ctx := context.Background()
-	cacheKey := fmt.Sprintf("feed:user:%s", userID)
-
-	if cachedFeed, err := uc.redisClient.Get(ctx, cacheKey).Result(); err == nil {
-		var cachedPosts []map[string]interface{}
-		if err := json.Unmarshal([]byte(cachedFeed), &cachedPosts); err == nil {
-			if offset+limit <= len(cachedPosts) {
-				feed := cachedPosts[offset : offset+limit]
-				return feed, len(feed), nil
+	var rankedAt time.Time
+	var postIDs []string
+	if after != nil {
+		rankedAt = after.CreatedAt
+		postIDs, err = uc.redisClient.LRange(ctx, forYouKey(userID, rankedAt), 0, -1).Result()
+		if err != nil {
+			uc.logger.Error("Failed to get ranking from cache: %v", err)
+			return nil, "", fmt.Errorf("failed to fetch feed")
+		}
+	}
+
+	// A ranking that expired is computed again; paging then continues after
+	// the last seen post if it is still ranked and starts over otherwise
+	if len(postIDs) == 0 {
+		rankedAt = time.Now().UTC()
+		postIDs, err = uc.rankPosts(ctx, userID, rankedAt)
+		if err != nil {
+			uc.logger.Error("Failed to rank posts: %v", err)
+			return nil, "", fmt.Errorf("failed to fetch feed")
+		}
+	}
+
+	start := 0
+	if after != nil {
+		for i, postID := range postIDs {
+			if postID == after.ID {
+				start = i + 1
+				break
 			}
 		}
 	}
+	end := start + limit
+	if end > len(postIDs) {
+		end = len(postIDs)
+	}
+	pageIDs := postIDs[start:end]
 
-	subscriptions, err := uc.getSubscriptionsFromAuthService(userID, authToken)
+	position := make(map[string]int, len(pageIDs))
+	for i, postID := range pageIDs {
+		position[postID] = i
+	}
+
+	posts, err := uc.feedRepo.GetPostsByIDs(userID, pageIDs)
 	if err != nil {
-		uc.logger.Warn("Failed to get subscriptions from Auth Service: %v, continuing with empty subscriptions", err)
-		subscriptions = []string{}
+		uc.logger.Error("Failed to get feed posts: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch feed")
 	}
+	sort.Slice(posts, func(i, j int) bool {
+		return position[posts[i]["id"].(string)] < position[posts[j]["id"].(string)]
+	})
 
-	var subscribedPosts []map[string]interface{}
-	if len(subscriptions) > 0 {
-		posts, err := uc.feedRepo.GetPostsByCreatorIDs(subscriptions, limit*2)
-		if err != nil {
-			uc.logger.Error("Failed to get posts from subscribed creators: %v", err)
-		} else {
-			subscribedPosts = posts
+	// The cursor carries the ranking's timestamp instead of a post's
+	nextCursor := ""
+	if end < len(postIDs) {
+		nextCursor = pagination.Encode(rankedAt, pageIDs[len(pageIDs)-1])
+	}
+
+	return uc.formatPosts(userID, posts), nextCursor, nil
+}
+
+// rankPosts scores the candidate posts for the user and stores the ranked
+// IDs under the ranking's timestamp.
+func (uc *feedUseCase) rankPosts(ctx context.Context, userID string, now time.Time) ([]string, error) {
+	candidates, err := uc.feedRepo.GetRankingCandidates(userID, now.Add(-forYouWindow), forYouCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	uc.loadViewCounts(ctx, candidates)
+
+	viewer, err := uc.getViewerProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	postIDs := rankCandidates(uc.scorer, candidates, viewer, now)
+
+	key := forYouKey(userID, now)
+	members := make([]interface{}, len(postIDs))
+	for i, postID := range postIDs {
+		members[i] = postID
+	}
+	_, err = uc.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, members...)
+		pipe.Expire(ctx, key, forYouRankingTTL)
+		return nil
+	})
+	if err != nil {
+		uc.logger.Warn("Failed to store ranking: %v", err)
+	}
+	return postIDs, nil
+}
+
+// loadViewCounts replaces the stored view counts with the unique view
+// counters kept by the interaction service where they exist.
+func (uc *feedUseCase) loadViewCounts(ctx context.Context, candidates []*entity.Candidate) {
+	keys := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		keys[i] = fmt.Sprintf("post:views:%s", candidate.PostID)
+	}
+
+	values, err := uc.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		uc.logger.Warn("Failed to get view counts: %v", err)
+		return
+	}
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if views, err := strconv.ParseInt(str, 10, 64); err == nil {
+			candidates[i].Views = views
 		}
 	}
+}
 
-	otherPosts, err := uc.feedRepo.GetOtherPosts(userID, subscriptions, limit*2)
+func (uc *feedUseCase) getViewerProfile(userID string) (*entity.ViewerProfile, error) {
+	counts, err := uc.feedRepo.GetLikedCategoryCounts(userID)
 	if err != nil {
-		uc.logger.Error("Failed to get other posts: %v", err)
-		otherPosts = []map[string]interface{}{}
+		return nil, err
 	}
 
-	uc.logger.Info("GetFeed: subscribedPosts=%d, otherPosts=%d", len(subscribedPosts), len(otherPosts))
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
 
-	allPosts := make([]map[string]interface{}, 0, len(subscribedPosts)+len(otherPosts))
-	allPosts = append(allPosts, subscribedPosts...)
-	allPosts = append(allPosts, otherPosts...)
+	profile := &entity.ViewerProfile{CategoryAffinity: make(map[string]float64, len(counts))}
+	for category, count := range counts {
+		profile.CategoryAffinity[category] = float64(count) / float64(total)
+	}
+	return profile, nil
+}
+
+// rankCandidates orders candidates by score, breaking ties by recency.
+func rankCandidates(scorer Scorer, candidates []*entity.Candidate, viewer *entity.ViewerProfile, now time.Time) []string {
+	scores := make(map[string]float64, len(candidates))
+	for _, candidate := range candidates {
+		scores[candidate.PostID] = scorer.Score(candidate, viewer, now)
+	}
 
-	sort.Slice(allPosts, func(i, j int) bool {
-		timeI := uc.parseTime(allPosts[i]["created_at"])
-		timeJ := uc.parseTime(allPosts[j]["created_at"])
-		return timeI.After(timeJ)
+	ranked := make([]*entity.Candidate, len(candidates))
+	copy(ranked, candidates)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if scores[a.PostID] != scores[b.PostID] {
+			return scores[a.PostID] > scores[b.PostID]
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.PostID > b.PostID
 	})
 
-	var formattedPosts []map[string]interface{}
-	for _, post := range allPosts {
+	postIDs := make([]string, len(ranked))
+	for i, candidate := range ranked {
+		postIDs[i] = candidate.PostID
+	}
+	return postIDs
+}
+
+func forYouKey(userID string, rankedAt time.Time) string {
+	return fmt.Sprintf("feed:foryou:%s:%d", userID, rankedAt.UnixNano())
+}
+
+// getTimelinePage returns a page of the user's timeline. found is false when
+// the user follows nobody with visible posts.
+func (uc *feedUseCase) getTimelinePage(userID string, after *timeline.Entry, limit int) (entries []timeline.Entry, found bool, err error) {
+	ctx := context.Background()
+
+	entries, exists, err := timeline.Page(ctx, uc.redisClient, userID, after, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	if exists {
+		return entries, true, nil
+	}
+
+	posts, err := uc.feedRepo.GetFollowedPosts(userID, timeline.MaxSize)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(posts) == 0 {
+		return nil, false, nil
+	}
+
+	entries = toTimelineEntries(posts)
+	if err := timeline.Replace(ctx, uc.redisClient, userID, entries); err != nil {
+		uc.logger.Warn("Failed to store rebuilt timeline: %v", err)
+	}
+	return timeline.PageEntries(entries, after, limit), true, nil
+}
+
+func toTimelineEntries(posts []*entity.Post) []timeline.Entry {
+	entries := make([]timeline.Entry, len(posts))
+	for i, post := range posts {
+		entries[i] = timeline.Entry{PostID: post.ID, CreatedAt: post.CreatedAt}
+	}
+	return entries
+}
+
+func (uc *feedUseCase) formatPosts(userID string, posts []map[string]interface{}) []map[string]interface{} {
+	purchased := uc.getPurchasedPostIDs(userID, posts)
+
+	formattedPosts := []map[string]interface{}{}
+	for _, post := range posts {
 		postIDStr, ok := post["id"].(string)
 		if !ok {
 			uc.logger.Warn("Post ID is not a string: %v", post["id"])
@@ -124,7 +374,13 @@ func (uc *feedUseCase) GetFeed(userID string, limit, offset int, authToken strin
 			}
 		}
 
+		price, _ := post["price"].(int)
+		isLocked := price > 0 && creatorIDStr != userID && !purchased[postID]
+
 		images := uc.formatPostImages(post)
+		for _, img := range images {
+			uc.signImage(img, isLocked)
+		}
 
 		postItem := map[string]interface{}{
 			"id":               post["id"],
@@ -135,36 +391,30 @@ func (uc *feedUseCase) GetFeed(userID string, limit, offset int, authToken strin
 			"creator_avatar":   creatorInfo["avatar_url"],
 			"creator_username": creatorInfo["username"],
 			"category":         post["category"],
+			"price":            price,
+			"is_locked":        isLocked,
 			"images":           images,
 			"likes_count":      likeCount,
 			"is_liked":         isLiked,
 			"created_at":       post["created_at"],
 		}
 
-		if mediaURL, ok := post["media_url"].(string); ok && mediaURL != "" && len(images) == 0 {
-			postItem["media_url"] = mediaURL
+		if mediaURL, ok := post["media_url"].(string); ok && mediaURL != "" && len(images) == 0 && !isLocked {
+			postItem["media_url"] = uc.s3Client.MediaURL(mediaURL)
 		}
 
-		formattedPosts = append(formattedPosts, postItem)
-	}
-
-	if len(formattedPosts) > 0 {
-		feedJSON, _ := json.Marshal(formattedPosts)
-		uc.redisClient.Set(ctx, cacheKey, feedJSON, 10*time.Minute)
-	}
+		if thumbnailURL, ok := post["thumbnail_url"].(string); ok && thumbnailURL != "" && !isLocked {
+			postItem["thumbnail_url"] = uc.s3Client.MediaURL(thumbnailURL)
+		}
 
-	start := offset
-	end := offset + limit
-	if start > len(formattedPosts) {
-		formattedPosts = []map[string]interface{}{}
-	} else {
-		if end > len(formattedPosts) {
-			end = len(formattedPosts)
+		if previewURL, ok := post["preview_url"].(string); ok && previewURL != "" && isLocked {
+			postItem["preview_url"] = uc.s3Client.MediaURL(previewURL)
 		}
-		formattedPosts = formattedPosts[start:end]
+
+		formattedPosts = append(formattedPosts, postItem)
 	}
 
-	return formattedPosts, len(formattedPosts), nil
+	return formattedPosts
 }
 
 func (uc *feedUseCase) GetFeedByCategory(userID, category string, limit, offset int) ([]map[string]interface{}, error) {
@@ -190,18 +440,42 @@ func (uc *feedUseCase) GetFeedByCategory(userID, category string, limit, offset
 				continue
 			}
 
+			// Subscriber-only posts are hidden from everyone but paid subscribers
+			if postData["subscriber_only"] == "1" || postData["subscriber_only"] == "true" {
+				subscribed, err := uc.feedRepo.HasActiveSubscription(userID, postData["creator_id"])
+				if err != nil || !subscribed {
+					continue
+				}
+			}
+
+			price, _ := strconv.Atoi(postData["price"])
+			isLocked := false
+			if price > 0 {
+				purchased, err := uc.feedRepo.GetPurchasedPostIDs(userID, []string{postID})
+				isLocked = err != nil || !purchased[postID]
+			}
+
 			postItem := map[string]interface{}{
 				"id":         postData["id"],
 				"title":      postData["title"],
 				"creator_id": postData["creator_id"],
 				"category":   postData["category"],
-				"media_url":  postData["media_url"],
+				"price":      price,
+				"is_locked":  isLocked,
+			}
+			if !isLocked {
+				postItem["media_url"] = uc.s3Client.MediaURL(postData["media_url"])
+			} else if postData["preview_url"] != "" {
+				postItem["preview_url"] = uc.s3Client.MediaURL(postData["preview_url"])
 			}
 
 			// Add images if available
 			if imagesJSON, ok := postData["images"]; ok && imagesJSON != "" {
 				var images []map[string]interface{}
 				if err := json.Unmarshal([]byte(imagesJSON), &images); err == nil {
+					for _, img := range images {
+						uc.signImage(img, isLocked)
+					}
 					postItem["images"] = images
 				}
 			}
@@ -213,46 +487,52 @@ func (uc *feedUseCase) GetFeedByCategory(userID, category string, limit, offset
 	return posts, nil
 }
 
-func (uc *feedUseCase) getSubscriptionsFromAuthService(userID, authToken string) ([]string, error) {
-	url := fmt.Sprintf("%s/api/v1/users/%s/subscriptions", uc.authServiceURL, userID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if authToken != "" {
-		req.Header.Set("Authorization", authToken)
+// getPurchasedPostIDs returns the paid posts among the given ones that the user has unlocked.
+func (uc *feedUseCase) getPurchasedPostIDs(userID string, posts []map[string]interface{}) map[string]bool {
+	var paidPostIDs []string
+	for _, post := range posts {
+		price, _ := post["price"].(int)
+		creatorID, _ := post["creator_id"].(string)
+		postID, _ := post["id"].(string)
+		if price > 0 && creatorID != userID && postID != "" {
+			paidPostIDs = append(paidPostIDs, postID)
+		}
 	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	purchased, err := uc.feedRepo.GetPurchasedPostIDs(userID, paidPostIDs)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("auth service returned %d: %s", resp.StatusCode, string(body))
+		uc.logger.Warn("Failed to get purchased posts: %v", err)
+		return map[string]bool{}
 	}
+	return purchased
+}
 
-	var result struct {
-		Subscriptions []struct {
-			CreatorID string `json:"creator_id"`
-		} `json:"subscriptions"`
-		Count int `json:"count"`
+// signImage replaces the object keys of an image with presigned URLs. Locked
+// images keep only their blurred preview: the thumbnail is a downscaled copy
+// of the original. Unlocked ones drop the preview instead.
+func (uc *feedUseCase) signImage(img map[string]interface{}, isLocked bool) {
+	if isLocked {
+		img["image_url"] = ""
+		img["thumbnail_url"] = ""
+		delete(img, "variants")
+		if key, ok := img["preview_url"].(string); ok {
+			img["preview_url"] = uc.s3Client.MediaURL(key)
+		}
+		return
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	delete(img, "preview_url")
+	for _, field := range []string{"image_url", "thumbnail_url"} {
+		if key, ok := img[field].(string); ok {
+			img[field] = uc.s3Client.MediaURL(key)
+		}
 	}
-
-	creatorIDs := make([]string, len(result.Subscriptions))
-	for i, sub := range result.Subscriptions {
-		creatorIDs[i] = sub.CreatorID
+	if variants, ok := img["variants"].(map[string]interface{}); ok {
+		for name, key := range variants {
+			if key, ok := key.(string); ok {
+				variants[name] = uc.s3Client.MediaURL(key)
+			}
+		}
 	}
-
-	return creatorIDs, nil
 }
 
 func (uc *feedUseCase) formatPostImages(post map[string]interface{}) []map[string]interface{} {
@@ -267,30 +547,10 @@ func (uc *feedUseCase) formatPostImages(post map[string]interface{}) []map[strin
 			"id":           img["id"],
 			"image_url":    img["image_url"],
 			"thumbnail_url": img["thumbnail_url"],
+			"preview_url":  img["preview_url"],
 			"order":        img["order"],
 		}
 	}
 
 	return formatted
 }
-
-func (uc *feedUseCase) parseTime(t interface{}) time.Time {
-	if t == nil {
-		return time.Time{}
-	}
-	
-	switch v := t.(type) {
-	case time.Time:
-		return v
-	case string:
-		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
-			return parsed
-		}
-		if parsed, err := time.Parse("2006-01-02 15:04:05.999999999-07:00", v); err == nil {
-			return parsed
-		}
-		return time.Time{}
-	default:
-		return time.Time{}
-	}
-}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/feed/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeedRepository struct {
+	persistent.FeedRepository
+	purchased map[string]bool
+}
+
+func (r *fakeFeedRepository) GetCreatorInfo(creatorID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"avatar_url": "", "username": "creator"}, nil
+}
+
+func (r *fakeFeedRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	return r.purchased, nil
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) MediaURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed.test/" + key
+}
+
+// mediaURLs returns every string in the JSON form of v that points at a
+// stored object.
+func mediaURLs(t *testing.T, v interface{}) []string {
+	t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(t, err)
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+
+	var urls []string
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, field := range v {
+				walk(field)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			if strings.Contains(v, "posts/") {
+				urls = append(urls, v)
+			}
+		}
+	}
+	walk(decoded)
+	return urls
+}
+
+func TestFormatPosts_LockedPostShowsOnlyBlurredPreviews(t *testing.T) {
+	uc := &feedUseCase{
+		feedRepo: &fakeFeedRepository{purchased: map[string]bool{}},
+		s3Client: fakeSigner{},
+		logger:   logger.New(),
+	}
+	posts := []map[string]interface{}{{
+		"id":            "post-1",
+		"creator_id":    "creator-1",
+		"price":         100,
+		"media_url":     "posts/creator-1/original.jpg",
+		"thumbnail_url": "posts/creator-1/original_thumbnail.jpg",
+		"preview_url":   "posts/creator-1/original_preview.jpg",
+		"images": []map[string]interface{}{{
+			"id":            "image-1",
+			"image_url":     "posts/creator-1/original.jpg",
+			"thumbnail_url": "posts/creator-1/original_thumbnail.jpg",
+			"preview_url":   "posts/creator-1/original_preview.jpg",
+			"order":         0,
+		}},
+	}}
+
+	formatted := uc.formatPosts("", posts)
+
+	require.Len(t, formatted, 1)
+	assert.Equal(t, true, formatted[0]["is_locked"])
+	assert.ElementsMatch(t, []string{
+		"https://signed.test/posts/creator-1/original_preview.jpg",
+		"https://signed.test/posts/creator-1/original_preview.jpg",
+	}, mediaURLs(t, formatted[0]))
+}
+
+func TestFormatPosts_PurchasedPostHasNoPreview(t *testing.T) {
+	uc := &feedUseCase{
+		feedRepo: &fakeFeedRepository{purchased: map[string]bool{"post-1": true}},
+		s3Client: fakeSigner{},
+		logger:   logger.New(),
+	}
+	posts := []map[string]interface{}{{
+		"id":          "post-1",
+		"creator_id":  "creator-1",
+		"price":       100,
+		"preview_url": "posts/creator-1/original_preview.jpg",
+		"images": []map[string]interface{}{{
+			"id":          "image-1",
+			"image_url":   "posts/creator-1/original.jpg",
+			"preview_url": "posts/creator-1/original_preview.jpg",
+			"order":       0,
+		}},
+	}}
+
+	formatted := uc.formatPosts("", posts)
+
+	require.Len(t, formatted, 1)
+	assert.Equal(t, false, formatted[0]["is_locked"])
+	assert.Equal(t, []string{"https://signed.test/posts/creator-1/original.jpg"}, mediaURLs(t, formatted[0]))
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"lick-scroll/services/feed/internal/entity"
+)
+
+// Scorer ranks candidates for the "For You" feed; higher scores come first.
+// It must be a pure function of its arguments so rankings can be replayed and
+// tuned offline.
+type Scorer interface {
+	Score(candidate *entity.Candidate, viewer *entity.ViewerProfile, now time.Time) float64
+}
+
+// EngagementWeights tunes the engagement scorer. Like, View and Donation
+// weigh the log of each counter, Affinity boosts categories the viewer likes
+// and HalfLife is how long it takes a post's score to halve.
+type EngagementWeights struct {
+	Like     float64
+	View     float64
+	Donation float64
+	Affinity float64
+	HalfLife time.Duration
+}
+
+func DefaultEngagementWeights() EngagementWeights {
+	return EngagementWeights{
+		Like:     1.0,
+		View:     0.3,
+		Donation: 1.5,
+		Affinity: 2.0,
+		HalfLife: 24 * time.Hour,
+	}
+}
+
+type engagementScorer struct {
+	weights EngagementWeights
+}
+
+func NewEngagementScorer(weights EngagementWeights) Scorer {
+	return &engagementScorer{weights: weights}
+}
+
+// Score multiplies engagement by the viewer's category affinity and decays the
+// result with the post's age. Counters are log-scaled so a viral post doesn't
+// bury everything else, and a post without engagement still scores by recency.
+func (s *engagementScorer) Score(candidate *entity.Candidate, viewer *entity.ViewerProfile, now time.Time) float64 {
+	engagement := 1 +
+		s.weights.Like*math.Log1p(float64(candidate.Likes)) +
+		s.weights.View*math.Log1p(float64(candidate.Views)) +
+		s.weights.Donation*math.Log1p(float64(candidate.Donations))
+
+	affinity := 1.0
+	if viewer != nil {
+		affinity += s.weights.Affinity * viewer.CategoryAffinity[candidate.Category]
+	}
+
+	decay := 1.0
+	if age := now.Sub(candidate.CreatedAt); age > 0 && s.weights.HalfLife > 0 {
+		decay = math.Pow(0.5, age.Hours()/s.weights.HalfLife.Hours())
+	}
+
+	return engagement * affinity * decay
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"lick-scroll/services/feed/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngagementScorer(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	scorer := NewEngagementScorer(DefaultEngagementWeights())
+	viewer := &entity.ViewerProfile{CategoryAffinity: map[string]float64{"art": 0.75, "music": 0.25}}
+
+	fresh := &entity.Candidate{PostID: "fresh", Category: "art", CreatedAt: now}
+	dayOld := &entity.Candidate{PostID: "day-old", Category: "art", CreatedAt: now.Add(-24 * time.Hour)}
+	assert.InDelta(t, scorer.Score(fresh, viewer, now)/2, scorer.Score(dayOld, viewer, now), 1e-9, "score halves every half-life")
+
+	liked := &entity.Candidate{PostID: "liked", Category: "art", CreatedAt: now, Likes: 10}
+	viewed := &entity.Candidate{PostID: "viewed", Category: "art", CreatedAt: now, Views: 10}
+	donated := &entity.Candidate{PostID: "donated", Category: "art", CreatedAt: now, Donations: 10}
+	assert.Greater(t, scorer.Score(liked, viewer, now), scorer.Score(viewed, viewer, now))
+	assert.Greater(t, scorer.Score(donated, viewer, now), scorer.Score(liked, viewer, now))
+	assert.Greater(t, scorer.Score(viewed, viewer, now), scorer.Score(fresh, viewer, now))
+
+	music := &entity.Candidate{PostID: "music", Category: "music", CreatedAt: now}
+	other := &entity.Candidate{PostID: "other", Category: "other", CreatedAt: now}
+	assert.Greater(t, scorer.Score(fresh, viewer, now), scorer.Score(music, viewer, now))
+	assert.Greater(t, scorer.Score(music, viewer, now), scorer.Score(other, viewer, now))
+	assert.Equal(t, scorer.Score(other, viewer, now), scorer.Score(other, &entity.ViewerProfile{}, now))
+}
+
+func TestRankCandidates(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	scorer := NewEngagementScorer(DefaultEngagementWeights())
+	viewer := &entity.ViewerProfile{CategoryAffinity: map[string]float64{"art": 1}}
+
+	candidates := []*entity.Candidate{
+		{PostID: "old-popular", Category: "music", CreatedAt: now.Add(-6 * 24 * time.Hour), Likes: 50, Views: 500},
+		{PostID: "new", Category: "music", CreatedAt: now.Add(-time.Hour)},
+		{PostID: "new-art", Category: "art", CreatedAt: now.Add(-time.Hour)},
+		{PostID: "new-liked", Category: "music", CreatedAt: now.Add(-time.Hour), Likes: 20},
+		{PostID: "tie-a", Category: "music", CreatedAt: now.Add(-2 * time.Hour)},
+		{PostID: "tie-b", Category: "music", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	assert.Equal(t, []string{"new-liked", "new-art", "new", "tie-b", "tie-a", "old-popular"}, rankCandidates(scorer, candidates, viewer, now))
+}
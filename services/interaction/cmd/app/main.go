@@ -6,6 +6,7 @@ import (
 	"lick-scroll/pkg/database"
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
 	interactionApp "lick-scroll/services/interaction/internal/app"
 
 	"github.com/gin-gonic/gin"
@@ -54,6 +55,12 @@ func main() {
 		panic(err)
 	}
 
+	s3Client, err := s3.NewClient(cfg)
+	if err != nil {
+		log.Error("Failed to create S3 client: %v", err)
+		panic(err)
+	}
+
 	// Connect to RabbitMQ for publishing notification events
 	queueClient, err := queue.NewRabbitMQClient(cfg, log)
 	if err != nil {
@@ -61,5 +68,5 @@ func main() {
 		queueClient = nil // Allow service to start without RabbitMQ
 	}
 
-	interactionApp.Run(cfg, log, db, redisClient, queueClient)
+	interactionApp.Run(cfg, log, db, redisClient, s3Client, queueClient)
 }
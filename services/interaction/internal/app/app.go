@@ -13,6 +13,7 @@ import (
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
 	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
 	interactionHTTP "lick-scroll/services/interaction/internal/controller/http"
 	"lick-scroll/services/interaction/internal/repo/persistent"
 	"lick-scroll/services/interaction/internal/usecase"
@@ -27,18 +28,22 @@ import (
 	_ "lick-scroll/services/interaction/docs" // Swagger docs
 )
 
-func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, queueClient *queue.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, s3Client *s3.Client, queueClient *queue.Client) {
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
 
 	// Initialize repositories
 	interactionRepo := persistent.NewInteractionRepository(db)
 	postRepo := persistent.NewPostRepository(db)
+	commentRepo := persistent.NewCommentRepository(db)
 
 	// Initialize UseCase
-	interactionUseCase := usecase.NewInteractionUseCase(interactionRepo, postRepo, redisClient, queueClient, log)
+	interactionUseCase := usecase.NewInteractionUseCase(interactionRepo, postRepo, redisClient, s3Client, queueClient, log)
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, postRepo, queueClient, log)
 
 	// Initialize HTTP handlers
 	interactionHandler := interactionHTTP.NewInteractionHandler(interactionUseCase, log)
+	commentHandler := interactionHTTP.NewCommentHandler(commentUseCase, log)
 
 	// Setup router
 	r := gin.Default()
@@ -62,17 +67,24 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(jwtService))
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
 	api.Use(middleware.RateLimitMiddleware(redisClient, 100, time.Minute))
 
 	// Protected routes
 	protected := api.Group("")
-	protected.Use(middleware.AuthMiddleware(jwtService))
+	protected.Use(middleware.AuthMiddleware(jwtService, denylist))
 	{
 		protected.POST("/interactions/posts/:post_id/like", interactionHandler.LikePost)
 		protected.GET("/interactions/posts/:post_id/liked", interactionHandler.IsLiked)
 		protected.GET("/interactions/posts/liked", interactionHandler.GetLikedPosts)
 		protected.POST("/interactions/posts/:post_id/view", interactionHandler.IncrementView)
+
+		protected.POST("/interactions/posts/:post_id/comments", commentHandler.CreateComment)
+		protected.GET("/interactions/posts/:post_id/comments", commentHandler.GetComments)
+		protected.GET("/interactions/comments/:comment_id/replies", commentHandler.GetReplies)
+		protected.PUT("/interactions/comments/:comment_id", commentHandler.UpdateComment)
+		protected.DELETE("/interactions/comments/:comment_id", commentHandler.DeleteComment)
+		protected.POST("/interactions/comments/:comment_id/like", commentHandler.LikeComment)
 	}
 
 	// Public routes
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/interaction/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentUseCase usecase.CommentUseCase
+	logger         *logger.Logger
+}
+
+func NewCommentHandler(commentUseCase usecase.CommentUseCase, logger *logger.Logger) *CommentHandler {
+	return &CommentHandler{
+		commentUseCase: commentUseCase,
+		logger:         logger,
+	}
+}
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// CreateComment godoc
+// @Summary      Comment on a post
+// @Description  Add a comment to a post, or reply to a top-level comment with parent_id. Replies to replies are not allowed. The post's creator and the author of the parent comment are notified.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        post_id path string true "Post ID"
+// @Param        request body CreateCommentRequest true "Comment"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/posts/{post_id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentUseCase.CreateComment(c.GetString("user_id"), c.Param("post_id"), req.ParentID, req.Body)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// GetComments godoc
+// @Summary      Get comments on a post
+// @Description  Get the post's top-level comments, newest first, with like and reply counts
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        post_id path string true "Post ID"
+// @Param        limit query int false "Number of comments to return (max 100)"
+// @Param        cursor query string false "next_cursor from the previous page"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/posts/{post_id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	comments, nextCursor, err := h.commentUseCase.GetComments(c.GetString("user_id"), c.Param("post_id"), commentPageLimit(c), c.Query("cursor"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments, "count": len(comments), "next_cursor": nextCursor})
+}
+
+// GetReplies godoc
+// @Summary      Get replies to a comment
+// @Description  Get the replies to a top-level comment, newest first
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        comment_id path string true "Comment ID"
+// @Param        limit query int false "Number of replies to return (max 100)"
+// @Param        cursor query string false "next_cursor from the previous page"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/comments/{comment_id}/replies [get]
+func (h *CommentHandler) GetReplies(c *gin.Context) {
+	replies, nextCursor, err := h.commentUseCase.GetReplies(c.GetString("user_id"), c.Param("comment_id"), commentPageLimit(c), c.Query("cursor"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": replies, "count": len(replies), "next_cursor": nextCursor})
+}
+
+// UpdateComment godoc
+// @Summary      Edit a comment
+// @Description  Edit the text of your own comment
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        comment_id path string true "Comment ID"
+// @Param        request body UpdateCommentRequest true "New text"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentUseCase.UpdateComment(c.GetString("user_id"), c.Param("comment_id"), req.Body)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment godoc
+// @Summary      Delete a comment
+// @Description  Delete your own comment, or any comment on your post. Replies are deleted with the comment.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        comment_id path string true "Comment ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	if err := h.commentUseCase.DeleteComment(c.GetString("user_id"), c.Param("comment_id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// LikeComment godoc
+// @Summary      Like a comment
+// @Description  Like a comment (toggle - if already liked, removes like)
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        comment_id path string true "Comment ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Router       /interactions/comments/{comment_id}/like [post]
+func (h *CommentHandler) LikeComment(c *gin.Context) {
+	liked, err := h.commentUseCase.LikeComment(c.GetString("user_id"), c.Param("comment_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if liked {
+		c.JSON(http.StatusOK, gin.H{"message": "Comment liked", "liked": true})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "Comment unliked", "liked": false})
+	}
+}
+
+func (h *CommentHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "post not found", "comment not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "you can only edit your own comments", "you can only delete your own comments or comments on your posts":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case "comment cannot be empty", "comment is too long", "replies can only be one level deep", "invalid cursor":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Comment request failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func commentPageLimit(c *gin.Context) int {
+	limit := 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	return limit
+}
@@ -111,14 +111,14 @@ func (h *InteractionHandler) IsLiked(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        limit query int false "Number of posts to return (max 100)"
-// @Param        offset query int false "Offset for pagination"
+// @Param        cursor query string false "next_cursor from the previous page"
 // @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /interactions/posts/liked [get]
 func (h *InteractionHandler) GetLikedPosts(c *gin.Context) {
 	userID := c.GetString("user_id")
 	limit := 20
-	offset := 0
 
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
@@ -126,20 +126,18 @@ func (h *InteractionHandler) GetLikedPosts(c *gin.Context) {
 		}
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
-	}
-
-	posts, err := h.interactionUseCase.GetLikedPosts(userID, limit, offset)
+	posts, nextCursor, err := h.interactionUseCase.GetLikedPosts(userID, limit, c.Query("cursor"))
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to get liked posts: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch liked posts"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "offset": offset})
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "next_cursor": nextCursor})
 }
 
 // IncrementView godoc
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Comment struct {
+	ID         string    `json:"id"`
+	PostID     string    `json:"post_id"`
+	UserID     string    `json:"user_id"`
+	Username   string    `json:"username"`
+	ParentID   string    `json:"parent_id,omitempty"`
+	Body       string    `json:"body"`
+	LikeCount  int64     `json:"like_count"`
+	ReplyCount int64     `json:"reply_count"`
+	IsLiked    bool      `json:"is_liked"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+// StatusApproved is the status of posts that passed moderation.
+const StatusApproved = "approved"
+
+// Post holds what interactions need to know about a post to decide who can
+// see it.
+type Post struct {
+	ID             string
+	CreatorID      string
+	Status         string
+	SubscriberOnly bool
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentModel struct {
+	ID        string         `gorm:"type:uuid;primary_key" json:"id"`
+	PostID    string         `gorm:"type:uuid;not null;index" json:"post_id"`
+	UserID    string         `gorm:"type:uuid;not null" json:"user_id"`
+	ParentID  *string        `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (CommentModel) TableName() string {
+	return "comments"
+}
+
+func (c *CommentModel) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+type CommentLikeModel struct {
+	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
+	CommentID string    `gorm:"type:uuid;not null" json:"comment_id"`
+	UserID    string    `gorm:"type:uuid;not null" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CommentLikeModel) TableName() string {
+	return "comment_likes"
+}
+
+func (l *CommentLikeModel) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/pkg/pagination"
+	"lick-scroll/services/interaction/internal/entity"
+	"lick-scroll/services/interaction/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+type CommentRepository interface {
+	Create(comment *entity.Comment) error
+	GetByID(commentID string) (*entity.Comment, error)
+	UpdateBody(commentID, body string) error
+	Delete(commentID string) error
+	ListByPost(postID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error)
+	ListReplies(parentID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error)
+	IsLiked(userID, commentID string) (bool, error)
+	CreateLike(userID, commentID string) error
+	DeleteLike(userID, commentID string) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(comment *entity.Comment) error {
+	commentModel := ToCommentModel(comment)
+	if err := r.db.Create(commentModel).Error; err != nil {
+		return err
+	}
+	*comment = *ToCommentEntity(commentModel)
+	return nil
+}
+
+func (r *commentRepository) GetByID(commentID string) (*entity.Comment, error) {
+	var commentModel model.CommentModel
+	if err := r.db.Where("id = ?", commentID).First(&commentModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return ToCommentEntity(&commentModel), nil
+}
+
+func (r *commentRepository) UpdateBody(commentID, body string) error {
+	result := r.db.Model(&model.CommentModel{}).Where("id = ?", commentID).Update("body", body)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Delete removes the comment together with its replies.
+func (r *commentRepository) Delete(commentID string) error {
+	result := r.db.Where("id = ? OR parent_id = ?", commentID, commentID).Delete(&model.CommentModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// ListByPost returns the post's top-level comments, newest first.
+func (r *commentRepository) ListByPost(postID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error) {
+	return r.list(viewerID, limit, after, func(db *gorm.DB) *gorm.DB {
+		return db.Where("comments.post_id = ? AND comments.parent_id IS NULL", postID)
+	})
+}
+
+// ListReplies returns the replies to a comment, newest first.
+func (r *commentRepository) ListReplies(parentID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error) {
+	return r.list(viewerID, limit, after, func(db *gorm.DB) *gorm.DB {
+		return db.Where("comments.parent_id = ?", parentID)
+	})
+}
+
+func (r *commentRepository) list(viewerID string, limit int, after *pagination.Cursor, filter func(db *gorm.DB) *gorm.DB) ([]*entity.Comment, error) {
+	var rows []struct {
+		ID         string
+		PostID     string
+		UserID     string
+		Username   string
+		ParentID   *string
+		Body       string
+		LikeCount  int64
+		ReplyCount int64
+		IsLiked    bool
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+	}
+
+	err := r.db.Table("comments").
+		Select(`comments.id, comments.post_id, comments.user_id, users.username, comments.parent_id, comments.body,
+			comments.created_at, comments.updated_at,
+			(SELECT COUNT(*) FROM comment_likes WHERE comment_likes.comment_id = comments.id) AS like_count,
+			(SELECT COUNT(*) FROM comments replies WHERE replies.parent_id = comments.id AND replies.deleted_at IS NULL) AS reply_count,
+			EXISTS (SELECT 1 FROM comment_likes WHERE comment_likes.comment_id = comments.id AND comment_likes.user_id = ?) AS is_liked`,
+			viewerID).
+		Joins("JOIN users ON users.id = comments.user_id").
+		Where("comments.deleted_at IS NULL").
+		Scopes(filter, pagination.Scope("comments.created_at", "comments.id", after)).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*entity.Comment, len(rows))
+	for i, row := range rows {
+		comments[i] = &entity.Comment{
+			ID:         row.ID,
+			PostID:     row.PostID,
+			UserID:     row.UserID,
+			Username:   row.Username,
+			Body:       row.Body,
+			LikeCount:  row.LikeCount,
+			ReplyCount: row.ReplyCount,
+			IsLiked:    row.IsLiked,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}
+		if row.ParentID != nil {
+			comments[i].ParentID = *row.ParentID
+		}
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) IsLiked(userID, commentID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.CommentLikeModel{}).Where("user_id = ? AND comment_id = ?", userID, commentID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commentRepository) CreateLike(userID, commentID string) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.CommentLikeModel{
+		UserID:    userID,
+		CommentID: commentID,
+	}).Error
+}
+
+func (r *commentRepository) DeleteLike(userID, commentID string) error {
+	return r.db.Where("user_id = ? AND comment_id = ?", userID, commentID).Delete(&model.CommentLikeModel{}).Error
+}
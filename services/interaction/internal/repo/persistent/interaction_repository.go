@@ -1,6 +1,9 @@
 package persistent
 
 import (
+	"time"
+
+	"lick-scroll/pkg/pagination"
 	"lick-scroll/services/interaction/internal/model"
 
 	"github.com/google/uuid"
@@ -12,7 +15,7 @@ type InteractionRepository interface {
 	CreateLike(userID, postID string) error
 	DeleteLike(userID, postID string) error
 	IsLiked(userID, postID string) (bool, error)
-	GetLikedPosts(userID string, limit, offset int) ([]map[string]interface{}, error)
+	GetLikedPosts(userID string, limit int, after *pagination.Cursor) ([]map[string]interface{}, error)
 	GetLikeCount(postID string) (int64, error)
 	IncrementViews(postID string) error
 	GetViewCount(postID string) (int64, error)
@@ -57,20 +60,41 @@ func (r *interactionRepository) IsLiked(userID, postID string) (bool, error) {
 	return count > 0, err
 }
 
-func (r *interactionRepository) GetLikedPosts(userID string, limit, offset int) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
-	query := r.db.Table("posts").
-		Select("posts.*, post_images.id as image_id, post_images.image_url, post_images.thumbnail_url, post_images.\"order\" as image_order").
-		Joins("INNER JOIN likes ON posts.id = likes.post_id").
-		Joins("LEFT JOIN post_images ON posts.id = post_images.post_id").
+// GetLikedPosts returns the posts the user liked, most recently liked first.
+// Each post carries liked_at, which together with the post ID is the
+// pagination key.
+func (r *interactionRepository) GetLikedPosts(userID string, limit int, after *pagination.Cursor) ([]map[string]interface{}, error) {
+	var likes []struct {
+		PostID    string
+		CreatedAt time.Time
+	}
+	query := r.db.Table("likes").
+		Select("likes.post_id, likes.created_at").
+		Joins("INNER JOIN posts ON posts.id = likes.post_id").
 		Where("likes.user_id = ? AND likes.deleted_at IS NULL AND posts.deleted_at IS NULL", userID).
-		Order("likes.created_at DESC")
+		Scopes(pagination.Scope("likes.created_at", "likes.post_id", after))
 
 	if limit > 0 {
-		query = query.Limit(limit).Offset(offset)
+		query = query.Limit(limit)
 	}
 
-	rows, err := query.Rows()
+	if err := query.Scan(&likes).Error; err != nil {
+		return nil, err
+	}
+	if len(likes) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	postIDs := make([]string, len(likes))
+	for i, like := range likes {
+		postIDs[i] = like.PostID
+	}
+
+	rows, err := r.db.Table("posts").
+		Select("posts.id, posts.creator_id, COALESCE(posts.title, ''), COALESCE(posts.description, ''), posts.type, COALESCE(posts.media_url, ''), COALESCE(posts.thumbnail_url, ''), COALESCE(posts.preview_url, ''), COALESCE(posts.category, ''), posts.price, COALESCE(posts.subscriber_only, FALSE), posts.status, posts.views, posts.purchases, posts.created_at, posts.updated_at, post_images.id as image_id, post_images.image_url, post_images.thumbnail_url, post_images.preview_url, post_images.\"order\" as image_order").
+		Joins("LEFT JOIN post_images ON posts.id = post_images.post_id").
+		Where("posts.id IN ?", postIDs).
+		Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -78,13 +102,14 @@ func (r *interactionRepository) GetLikedPosts(userID string, limit, offset int)
 
 	postMap := make(map[string]map[string]interface{})
 	for rows.Next() {
-		var postID, creatorID, title, description, postType, mediaURL, thumbnailURL, category, status string
-		var views, purchases int
+		var postID, creatorID, title, description, postType, mediaURL, thumbnailURL, previewURL, category, status string
+		var price, views, purchases int
+		var subscriberOnly bool
 		var createdAt, updatedAt interface{}
-		var imageID, imageURL, imageThumbnailURL interface{}
+		var imageID, imageURL, imageThumbnailURL, imagePreviewURL interface{}
 		var imageOrder interface{}
 
-		if err := rows.Scan(&postID, &creatorID, &title, &description, &postType, &mediaURL, &thumbnailURL, &category, &status, &views, &purchases, &createdAt, &updatedAt, &imageID, &imageURL, &imageThumbnailURL, &imageOrder); err != nil {
+		if err := rows.Scan(&postID, &creatorID, &title, &description, &postType, &mediaURL, &thumbnailURL, &previewURL, &category, &price, &subscriberOnly, &status, &views, &purchases, &createdAt, &updatedAt, &imageID, &imageURL, &imageThumbnailURL, &imagePreviewURL, &imageOrder); err != nil {
 			continue
 		}
 
@@ -97,7 +122,10 @@ func (r *interactionRepository) GetLikedPosts(userID string, limit, offset int)
 				"type":         postType,
 				"media_url":    mediaURL,
 				"thumbnail_url": thumbnailURL,
+				"preview_url":   previewURL,
 				"category":     category,
+				"price":        price,
+				"subscriber_only": subscriberOnly,
 				"status":       status,
 				"views":        views,
 				"purchases":    purchases,
@@ -114,15 +142,19 @@ func (r *interactionRepository) GetLikedPosts(userID string, limit, offset int)
 				"post_id":      postID,
 				"image_url":    imageURL,
 				"thumbnail_url": imageThumbnailURL,
+				"preview_url":   imagePreviewURL,
 				"order":        imageOrder,
 			})
 			postMap[postID]["images"] = images
 		}
 	}
 
-	results = make([]map[string]interface{}, 0, len(postMap))
-	for _, post := range postMap {
-		results = append(results, post)
+	results := make([]map[string]interface{}, 0, len(likes))
+	for _, like := range likes {
+		if post, ok := postMap[like.PostID]; ok {
+			post["liked_at"] = like.CreatedAt
+			results = append(results, post)
+		}
 	}
 
 	return results, nil
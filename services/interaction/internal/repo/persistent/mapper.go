@@ -32,3 +32,41 @@ func ToLikeModel(e *entity.Like) *model.LikeModel {
 		UpdatedAt: e.UpdatedAt,
 	}
 }
+
+func ToCommentEntity(m *model.CommentModel) *entity.Comment {
+	if m == nil {
+		return nil
+	}
+
+	comment := &entity.Comment{
+		ID:        m.ID,
+		PostID:    m.PostID,
+		UserID:    m.UserID,
+		Body:      m.Body,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.ParentID != nil {
+		comment.ParentID = *m.ParentID
+	}
+	return comment
+}
+
+func ToCommentModel(e *entity.Comment) *model.CommentModel {
+	if e == nil {
+		return nil
+	}
+
+	m := &model.CommentModel{
+		ID:        e.ID,
+		PostID:    e.PostID,
+		UserID:    e.UserID,
+		Body:      e.Body,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+	if e.ParentID != "" {
+		m.ParentID = &e.ParentID
+	}
+	return m
+}
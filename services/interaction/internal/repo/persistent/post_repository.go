@@ -1,12 +1,22 @@
 package persistent
 
 import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/interaction/internal/entity"
+
 	"gorm.io/gorm"
 )
 
+var ErrPostNotFound = errors.New("post not found")
+
 type PostRepository interface {
 	PostExists(postID string) (bool, error)
+	GetPost(postID string) (*entity.Post, error)
 	GetCreatorID(postID string) (string, error)
+	GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error)
+	HasActiveSubscription(viewerID, creatorID string) (bool, error)
 }
 
 type postRepository struct {
@@ -23,8 +33,58 @@ func (r *postRepository) PostExists(postID string) (bool, error) {
 	return count > 0, err
 }
 
+// GetPost loads the fields that decide who can see a post. Deleted posts
+// are not found.
+func (r *postRepository) GetPost(postID string) (*entity.Post, error) {
+	var post entity.Post
+	result := r.db.Table("posts").
+		Select("id, creator_id, status, COALESCE(subscriber_only, FALSE) AS subscriber_only").
+		Where("id = ? AND deleted_at IS NULL", postID).
+		Scan(&post)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPostNotFound
+	}
+	return &post, nil
+}
+
 func (r *postRepository) GetCreatorID(postID string) (string, error) {
 	var creatorID string
-	err := r.db.Table("posts").Select("creator_id").Where("id = ?", postID).Scan(&creatorID).Error
+	err := r.db.Table("posts").Select("creator_id").Where("id = ? AND deleted_at IS NULL", postID).Scan(&creatorID).Error
 	return creatorID, err
 }
+
+func (r *postRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	purchased := make(map[string]bool)
+	if userID == "" || len(postIDs) == 0 {
+		return purchased, nil
+	}
+
+	var ids []string
+	err := r.db.Table("transactions").
+		Where("user_id = ? AND post_id IN ? AND type = ?", userID, postIDs, "purchase").
+		Distinct("post_id").
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		purchased[id] = true
+	}
+	return purchased, nil
+}
+
+func (r *postRepository) HasActiveSubscription(viewerID, creatorID string) (bool, error) {
+	if viewerID == "" {
+		return false, nil
+	}
+
+	var count int64
+	err := r.db.Table("subscriptions").
+		Where("viewer_id = ? AND creator_id = ? AND type = ? AND current_period_end > ? AND deleted_at IS NULL", viewerID, creatorID, "paid", time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
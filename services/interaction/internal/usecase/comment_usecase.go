@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/services/interaction/internal/entity"
+	"lick-scroll/services/interaction/internal/repo/persistent"
+)
+
+// maxCommentLength is counted in characters, not bytes.
+const maxCommentLength = 2000
+
+type CommentUseCase interface {
+	CreateComment(userID, postID, parentID, body string) (*entity.Comment, error)
+	UpdateComment(userID, commentID, body string) (*entity.Comment, error)
+	DeleteComment(userID, commentID string) error
+	GetComments(viewerID, postID string, limit int, cursor string) ([]*entity.Comment, string, error)
+	GetReplies(viewerID, commentID string, limit int, cursor string) ([]*entity.Comment, string, error)
+	LikeComment(userID, commentID string) (bool, error)
+}
+
+type commentUseCase struct {
+	commentRepo persistent.CommentRepository
+	postRepo    persistent.PostRepository
+	queueClient *queue.Client
+	logger      *logger.Logger
+}
+
+func NewCommentUseCase(
+	commentRepo persistent.CommentRepository,
+	postRepo persistent.PostRepository,
+	queueClient *queue.Client,
+	logger *logger.Logger,
+) CommentUseCase {
+	return &commentUseCase{
+		commentRepo: commentRepo,
+		postRepo:    postRepo,
+		queueClient: queueClient,
+		logger:      logger,
+	}
+}
+
+// CreateComment adds a comment to a post, or a reply when parentID is set.
+// Replies can only be made to top-level comments. The post's creator is
+// notified, and so is the author of the comment being replied to.
+func (uc *commentUseCase) CreateComment(userID, postID, parentID, body string) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkPostAccess(userID, postID); err != nil {
+		return nil, err
+	}
+
+	var parent *entity.Comment
+	if parentID != "" {
+		parent, err = uc.getComment(parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PostID != postID {
+			return nil, fmt.Errorf("comment not found")
+		}
+		if parent.ParentID != "" {
+			return nil, fmt.Errorf("replies can only be one level deep")
+		}
+	}
+
+	comment := &entity.Comment{
+		PostID:   postID,
+		UserID:   userID,
+		ParentID: parentID,
+		Body:     body,
+	}
+	if err := uc.commentRepo.Create(comment); err != nil {
+		uc.logger.Error("Failed to create comment: %v", err)
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	notified := map[string]bool{userID: true}
+	if parent != nil && !notified[parent.UserID] {
+		notified[parent.UserID] = true
+		uc.publishCommentNotification(parent.UserID, comment, true)
+	}
+	if creatorID, err := uc.postRepo.GetCreatorID(postID); err == nil && !notified[creatorID] {
+		uc.publishCommentNotification(creatorID, comment, false)
+	}
+
+	return comment, nil
+}
+
+func (uc *commentUseCase) UpdateComment(userID, commentID, body string) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.getComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, fmt.Errorf("you can only edit your own comments")
+	}
+
+	if err := uc.commentRepo.UpdateBody(commentID, body); err != nil {
+		if errors.Is(err, persistent.ErrCommentNotFound) {
+			return nil, fmt.Errorf("comment not found")
+		}
+		uc.logger.Error("Failed to update comment: %v", err)
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return uc.getComment(commentID)
+}
+
+// DeleteComment lets authors delete their comments and creators delete any
+// comment on their posts. Replies are deleted with their comment.
+func (uc *commentUseCase) DeleteComment(userID, commentID string) error {
+	comment, err := uc.getComment(commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID {
+		creatorID, err := uc.postRepo.GetCreatorID(comment.PostID)
+		if err != nil {
+			uc.logger.Error("Failed to get post creator: %v", err)
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if creatorID != userID {
+			return fmt.Errorf("you can only delete your own comments or comments on your posts")
+		}
+	}
+
+	if err := uc.commentRepo.Delete(commentID); err != nil {
+		if errors.Is(err, persistent.ErrCommentNotFound) {
+			return fmt.Errorf("comment not found")
+		}
+		uc.logger.Error("Failed to delete comment: %v", err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+func (uc *commentUseCase) GetComments(viewerID, postID string, limit int, cursor string) ([]*entity.Comment, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
+	if err := uc.checkPostAccess(viewerID, postID); err != nil {
+		return nil, "", err
+	}
+
+	comments, err := uc.commentRepo.ListByPost(postID, viewerID, limit, after)
+	if err != nil {
+		uc.logger.Error("Failed to get comments: %v", err)
+		return nil, "", fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, nextCommentCursor(comments, limit), nil
+}
+
+func (uc *commentUseCase) GetReplies(viewerID, commentID string, limit int, cursor string) ([]*entity.Comment, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
+	comment, err := uc.getComment(commentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := uc.checkPostAccess(viewerID, comment.PostID); err != nil {
+		return nil, "", err
+	}
+
+	replies, err := uc.commentRepo.ListReplies(commentID, viewerID, limit, after)
+	if err != nil {
+		uc.logger.Error("Failed to get replies: %v", err)
+		return nil, "", fmt.Errorf("failed to get replies: %w", err)
+	}
+	return replies, nextCommentCursor(replies, limit), nil
+}
+
+// LikeComment toggles the user's like on a comment, like LikePost does for
+// posts.
+func (uc *commentUseCase) LikeComment(userID, commentID string) (bool, error) {
+	comment, err := uc.getComment(commentID)
+	if err != nil {
+		return false, err
+	}
+	if err := uc.checkPostAccess(userID, comment.PostID); err != nil {
+		return false, err
+	}
+
+	isLiked, err := uc.commentRepo.IsLiked(userID, commentID)
+	if err != nil {
+		uc.logger.Error("Failed to check comment like status: %v", err)
+		return false, fmt.Errorf("failed to check like status: %w", err)
+	}
+
+	if isLiked {
+		if err := uc.commentRepo.DeleteLike(userID, commentID); err != nil {
+			uc.logger.Error("Failed to delete comment like: %v", err)
+			return false, fmt.Errorf("failed to unlike comment: %w", err)
+		}
+		return false, nil
+	}
+
+	if err := uc.commentRepo.CreateLike(userID, commentID); err != nil {
+		uc.logger.Error("Failed to create comment like: %v", err)
+		return false, fmt.Errorf("failed to like comment: %w", err)
+	}
+	return true, nil
+}
+
+func (uc *commentUseCase) getComment(commentID string) (*entity.Comment, error) {
+	comment, err := uc.commentRepo.GetByID(commentID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrCommentNotFound) {
+			return nil, fmt.Errorf("comment not found")
+		}
+		uc.logger.Error("Failed to get comment: %v", err)
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return comment, nil
+}
+
+// checkPostAccess hides the comments of posts the user can't see: ones not
+// approved yet and subscriber-only ones without an active subscription.
+// Creators always see their own posts.
+func (uc *commentUseCase) checkPostAccess(userID, postID string) error {
+	post, err := uc.postRepo.GetPost(postID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrPostNotFound) {
+			return fmt.Errorf("post not found")
+		}
+		uc.logger.Error("Failed to get post: %v", err)
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+	if post.CreatorID == userID {
+		return nil
+	}
+
+	if post.Status != entity.StatusApproved {
+		return fmt.Errorf("post not found")
+	}
+	if post.SubscriberOnly {
+		subscribed, err := uc.postRepo.HasActiveSubscription(userID, post.CreatorID)
+		if err != nil {
+			uc.logger.Error("Failed to check subscription: %v", err)
+			return fmt.Errorf("failed to check subscription: %w", err)
+		}
+		if !subscribed {
+			return fmt.Errorf("post not found")
+		}
+	}
+	return nil
+}
+
+func (uc *commentUseCase) publishCommentNotification(recipientID string, comment *entity.Comment, isReply bool) {
+	if uc.queueClient == nil {
+		return
+	}
+
+	go func() {
+		task := map[string]interface{}{
+			"type":         "comment",
+			"user_id":      recipientID,
+			"commenter_id": comment.UserID,
+			"post_id":      comment.PostID,
+			"comment_id":   comment.ID,
+			"is_reply":     isReply,
+			"priority":     3,
+		}
+
+		if err := uc.queueClient.PublishNotificationTask(task); err != nil {
+			uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish comment notification task to RabbitMQ: %v", err)
+		}
+	}()
+}
+
+func nextCommentCursor(comments []*entity.Comment, limit int) string {
+	if len(comments) == 0 || len(comments) < limit {
+		return ""
+	}
+	last := comments[len(comments)-1]
+	return pagination.Encode(last.CreatedAt, last.ID)
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("comment cannot be empty")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("comment is too long")
+	}
+	return body, nil
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
+	"lick-scroll/services/interaction/internal/entity"
+	"lick-scroll/services/interaction/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePostRepository knows each post's creator, the posts the user bought
+// and the creators they are subscribed to. Posts are approved unless listed
+// in pending.
+type fakePostRepository struct {
+	creators       map[string]string
+	pending        map[string]bool
+	subscriberOnly map[string]bool
+	purchased      map[string]bool
+	subscribed     map[string]bool
+}
+
+func (r *fakePostRepository) PostExists(postID string) (bool, error) {
+	_, ok := r.creators[postID]
+	return ok, nil
+}
+
+func (r *fakePostRepository) GetPost(postID string) (*entity.Post, error) {
+	creatorID, ok := r.creators[postID]
+	if !ok {
+		return nil, persistent.ErrPostNotFound
+	}
+	status := entity.StatusApproved
+	if r.pending[postID] {
+		status = "pending"
+	}
+	return &entity.Post{ID: postID, CreatorID: creatorID, Status: status, SubscriberOnly: r.subscriberOnly[postID]}, nil
+}
+
+func (r *fakePostRepository) GetCreatorID(postID string) (string, error) {
+	return r.creators[postID], nil
+}
+
+func (r *fakePostRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	purchased := make(map[string]bool)
+	for _, postID := range postIDs {
+		purchased[postID] = r.purchased[postID]
+	}
+	return purchased, nil
+}
+
+func (r *fakePostRepository) HasActiveSubscription(viewerID, creatorID string) (bool, error) {
+	return r.subscribed[creatorID], nil
+}
+
+// fakeCommentRepository keeps comments and likes in memory.
+type fakeCommentRepository struct {
+	comments map[string]*entity.Comment
+	likes    map[string]bool
+}
+
+func newFakeCommentRepository() *fakeCommentRepository {
+	return &fakeCommentRepository{comments: map[string]*entity.Comment{}, likes: map[string]bool{}}
+}
+
+func (r *fakeCommentRepository) Create(comment *entity.Comment) error {
+	comment.ID = fmt.Sprintf("comment-%d", len(r.comments)+1)
+	stored := *comment
+	r.comments[comment.ID] = &stored
+	return nil
+}
+
+func (r *fakeCommentRepository) GetByID(commentID string) (*entity.Comment, error) {
+	comment, ok := r.comments[commentID]
+	if !ok {
+		return nil, persistent.ErrCommentNotFound
+	}
+	stored := *comment
+	return &stored, nil
+}
+
+func (r *fakeCommentRepository) UpdateBody(commentID, body string) error {
+	r.comments[commentID].Body = body
+	return nil
+}
+
+func (r *fakeCommentRepository) Delete(commentID string) error {
+	for id, comment := range r.comments {
+		if id == commentID || comment.ParentID == commentID {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeCommentRepository) ListByPost(postID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error) {
+	return nil, nil
+}
+
+func (r *fakeCommentRepository) ListReplies(parentID, viewerID string, limit int, after *pagination.Cursor) ([]*entity.Comment, error) {
+	return nil, nil
+}
+
+func (r *fakeCommentRepository) IsLiked(userID, commentID string) (bool, error) {
+	return r.likes[userID+":"+commentID], nil
+}
+
+func (r *fakeCommentRepository) CreateLike(userID, commentID string) error {
+	r.likes[userID+":"+commentID] = true
+	return nil
+}
+
+func (r *fakeCommentRepository) DeleteLike(userID, commentID string) error {
+	delete(r.likes, userID+":"+commentID)
+	return nil
+}
+
+func newTestCommentUseCase() (CommentUseCase, *fakeCommentRepository) {
+	commentRepo := newFakeCommentRepository()
+	postRepo := &fakePostRepository{creators: map[string]string{"post": "creator", "other-post": "creator"}}
+	return NewCommentUseCase(commentRepo, postRepo, nil, logger.New()), commentRepo
+}
+
+func TestCreateComment(t *testing.T) {
+	uc, _ := newTestCommentUseCase()
+
+	_, err := uc.CreateComment("viewer", "missing", "", "hello")
+	assert.EqualError(t, err, "post not found")
+
+	_, err = uc.CreateComment("viewer", "post", "", "   ")
+	assert.EqualError(t, err, "comment cannot be empty")
+
+	_, err = uc.CreateComment("viewer", "post", "", strings.Repeat("я", maxCommentLength+1))
+	assert.EqualError(t, err, "comment is too long")
+
+	comment, err := uc.CreateComment("viewer", "post", "", "  nice post  ")
+	require.NoError(t, err)
+	assert.Equal(t, "nice post", comment.Body)
+
+	reply, err := uc.CreateComment("creator", "post", comment.ID, "thanks")
+	require.NoError(t, err)
+	assert.Equal(t, comment.ID, reply.ParentID)
+
+	_, err = uc.CreateComment("viewer", "post", reply.ID, "a reply to a reply")
+	assert.EqualError(t, err, "replies can only be one level deep")
+
+	_, err = uc.CreateComment("viewer", "other-post", comment.ID, "wrong post")
+	assert.EqualError(t, err, "comment not found")
+}
+
+func TestUpdateAndDeleteComment(t *testing.T) {
+	uc, repo := newTestCommentUseCase()
+
+	comment, err := uc.CreateComment("viewer", "post", "", "first")
+	require.NoError(t, err)
+	_, err = uc.CreateComment("someone", "post", comment.ID, "reply")
+	require.NoError(t, err)
+
+	_, err = uc.UpdateComment("creator", comment.ID, "edited")
+	assert.EqualError(t, err, "you can only edit your own comments")
+
+	updated, err := uc.UpdateComment("viewer", comment.ID, "edited")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", updated.Body)
+
+	err = uc.DeleteComment("someone", comment.ID)
+	assert.EqualError(t, err, "you can only delete your own comments or comments on your posts")
+
+	// The post's creator can moderate comments on it; replies go too
+	require.NoError(t, uc.DeleteComment("creator", comment.ID))
+	assert.Empty(t, repo.comments)
+
+	err = uc.DeleteComment("viewer", comment.ID)
+	assert.EqualError(t, err, "comment not found")
+}
+
+func TestLikeComment(t *testing.T) {
+	uc, _ := newTestCommentUseCase()
+
+	comment, err := uc.CreateComment("viewer", "post", "", "hello")
+	require.NoError(t, err)
+
+	liked, err := uc.LikeComment("creator", comment.ID)
+	require.NoError(t, err)
+	assert.True(t, liked)
+
+	liked, err = uc.LikeComment("creator", comment.ID)
+	require.NoError(t, err)
+	assert.False(t, liked)
+
+	_, err = uc.LikeComment("creator", "missing")
+	assert.EqualError(t, err, "comment not found")
+}
+
+func TestComments_HiddenPosts(t *testing.T) {
+	commentRepo := newFakeCommentRepository()
+	postRepo := &fakePostRepository{
+		creators:       map[string]string{"pending": "creator", "exclusive": "creator"},
+		pending:        map[string]bool{"pending": true},
+		subscriberOnly: map[string]bool{"exclusive": true},
+		subscribed:     map[string]bool{},
+	}
+	uc := NewCommentUseCase(commentRepo, postRepo, nil, logger.New())
+
+	// Creators can comment on their own posts whatever their state
+	pendingComment, err := uc.CreateComment("creator", "pending", "", "soon")
+	require.NoError(t, err)
+	exclusiveComment, err := uc.CreateComment("creator", "exclusive", "", "for subscribers")
+	require.NoError(t, err)
+
+	for _, c := range []*entity.Comment{pendingComment, exclusiveComment} {
+		_, err = uc.CreateComment("viewer", c.PostID, "", "hello")
+		assert.EqualError(t, err, "post not found", c.PostID)
+		_, _, err = uc.GetComments("viewer", c.PostID, 10, "")
+		assert.EqualError(t, err, "post not found", c.PostID)
+		_, _, err = uc.GetReplies("viewer", c.ID, 10, "")
+		assert.EqualError(t, err, "post not found", c.PostID)
+		_, err = uc.LikeComment("viewer", c.ID)
+		assert.EqualError(t, err, "post not found", c.PostID)
+	}
+
+	// Subscribers see subscriber-only posts, pending ones stay hidden
+	postRepo.subscribed["creator"] = true
+	_, err = uc.CreateComment("viewer", "exclusive", exclusiveComment.ID, "hello")
+	require.NoError(t, err)
+	_, err = uc.CreateComment("viewer", "pending", "", "hello")
+	assert.EqualError(t, err, "post not found")
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/services/interaction/internal/repo/persistent"
 
@@ -17,15 +18,22 @@ type InteractionUseCase interface {
 	LikePost(userID, postID string) (bool, error)
 	GetLikeCount(postID string) (int64, error)
 	IsLiked(userID, postID string) (bool, error)
-	GetLikedPosts(userID string, limit, offset int) ([]map[string]interface{}, error)
+	GetLikedPosts(userID string, limit int, cursor string) ([]map[string]interface{}, string, error)
 	IncrementView(userID, postID string) (bool, error)
 	GetViewCount(postID string) (int64, error)
 }
 
+// MediaSigner turns object keys into URLs clients can load; *s3.Client
+// implements it.
+type MediaSigner interface {
+	MediaURL(key string) string
+}
+
 type interactionUseCase struct {
 	interactionRepo persistent.InteractionRepository
 	postRepo         persistent.PostRepository
 	redisClient      *redis.Client
+	s3Client         MediaSigner
 	queueClient      *queue.Client
 	logger           *logger.Logger
 }
@@ -34,6 +42,7 @@ func NewInteractionUseCase(
 	interactionRepo persistent.InteractionRepository,
 	postRepo persistent.PostRepository,
 	redisClient *redis.Client,
+	s3Client MediaSigner,
 	queueClient *queue.Client,
 	logger *logger.Logger,
 ) InteractionUseCase {
@@ -41,6 +50,7 @@ func NewInteractionUseCase(
 		interactionRepo: interactionRepo,
 		postRepo:         postRepo,
 		redisClient:      redisClient,
+		s3Client:         s3Client,
 		queueClient:      queueClient,
 		logger:           logger,
 	}
@@ -122,8 +132,93 @@ func (uc *interactionUseCase) IsLiked(userID, postID string) (bool, error) {
 	return uc.interactionRepo.IsLiked(userID, postID)
 }
 
-func (uc *interactionUseCase) GetLikedPosts(userID string, limit, offset int) ([]map[string]interface{}, error) {
-	return uc.interactionRepo.GetLikedPosts(userID, limit, offset)
+func (uc *interactionUseCase) GetLikedPosts(userID string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
+	posts, err := uc.interactionRepo.GetLikedPosts(userID, limit, after)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := uc.signMedia(userID, posts); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(posts) > 0 && len(posts) == limit {
+		last := posts[len(posts)-1]
+		likedAt, _ := last["liked_at"].(time.Time)
+		postID, _ := last["id"].(string)
+		nextCursor = pagination.Encode(likedAt, postID)
+	}
+	return posts, nextCursor, nil
+}
+
+// signMedia replaces the object keys of the posts with presigned URLs. A post
+// is locked when it is paid and not purchased, or subscriber-only and the
+// user has no active subscription to its creator. Locked posts keep only
+// their blurred previews; creators always see their own posts.
+func (uc *interactionUseCase) signMedia(userID string, posts []map[string]interface{}) error {
+	var paidPostIDs []string
+	subscribed := make(map[string]bool)
+	for _, post := range posts {
+		price, _ := post["price"].(int)
+		subscriberOnly, _ := post["subscriber_only"].(bool)
+		creatorID, _ := post["creator_id"].(string)
+		if creatorID == userID {
+			continue
+		}
+		if price > 0 {
+			paidPostIDs = append(paidPostIDs, post["id"].(string))
+		}
+		if _, checked := subscribed[creatorID]; subscriberOnly && !checked {
+			active, err := uc.postRepo.HasActiveSubscription(userID, creatorID)
+			if err != nil {
+				return fmt.Errorf("failed to check subscriptions: %w", err)
+			}
+			subscribed[creatorID] = active
+		}
+	}
+	purchased, err := uc.postRepo.GetPurchasedPostIDs(userID, paidPostIDs)
+	if err != nil {
+		return fmt.Errorf("failed to check purchases: %w", err)
+	}
+
+	for _, post := range posts {
+		price, _ := post["price"].(int)
+		subscriberOnly, _ := post["subscriber_only"].(bool)
+		creatorID, _ := post["creator_id"].(string)
+		postID, _ := post["id"].(string)
+		isLocked := creatorID != userID &&
+			(price > 0 && !purchased[postID] || subscriberOnly && !subscribed[creatorID])
+		post["is_locked"] = isLocked
+
+		uc.signKeys(post, []string{"media_url", "thumbnail_url"}, isLocked)
+		images, _ := post["images"].([]map[string]interface{})
+		for _, image := range images {
+			uc.signKeys(image, []string{"image_url", "thumbnail_url"}, isLocked)
+		}
+	}
+	return nil
+}
+
+// signKeys presigns the given media fields and the blurred preview of a post
+// or image. Locked ones get the preview alone, unlocked ones everything else.
+func (uc *interactionUseCase) signKeys(media map[string]interface{}, fields []string, isLocked bool) {
+	for _, field := range fields {
+		key, _ := media[field].(string)
+		if isLocked {
+			key = ""
+		}
+		media[field] = uc.s3Client.MediaURL(key)
+	}
+	previewURL, _ := media["preview_url"].(string)
+	if !isLocked {
+		previewURL = ""
+	}
+	media["preview_url"] = uc.s3Client.MediaURL(previewURL)
 }
 
 func (uc *interactionUseCase) IncrementView(userID, postID string) (bool, error) {
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) MediaURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed.test/" + key
+}
+
+func likedPost(id, creatorID string, price int, subscriberOnly bool) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              id,
+		"creator_id":      creatorID,
+		"price":           price,
+		"subscriber_only": subscriberOnly,
+		"media_url":       "posts/" + id + ".jpg",
+		"thumbnail_url":   "posts/" + id + "_thumbnail.jpg",
+		"preview_url":     "posts/" + id + "_preview.jpg",
+		"images": []map[string]interface{}{{
+			"image_url":     "posts/" + id + ".jpg",
+			"thumbnail_url": "posts/" + id + "_thumbnail.jpg",
+			"preview_url":   "posts/" + id + "_preview.jpg",
+		}},
+	}
+}
+
+func TestSignMedia_LockedPostsShowOnlyBlurredPreviews(t *testing.T) {
+	uc := &interactionUseCase{
+		postRepo: &fakePostRepository{
+			purchased:  map[string]bool{"bought": true},
+			subscribed: map[string]bool{"followed": true},
+		},
+		s3Client: fakeSigner{},
+	}
+	posts := []map[string]interface{}{
+		likedPost("paid", "creator", 100, false),
+		likedPost("bought", "creator", 100, false),
+		likedPost("subscriber-only", "creator", 0, true),
+		likedPost("subscribed", "followed", 0, true),
+		likedPost("own", "viewer", 100, true),
+	}
+
+	require.NoError(t, uc.signMedia("viewer", posts))
+
+	locked := map[string]bool{"paid": true, "subscriber-only": true}
+	for _, post := range posts {
+		id := post["id"].(string)
+		image := post["images"].([]map[string]interface{})[0]
+		assert.Equal(t, locked[id], post["is_locked"], id)
+		if locked[id] {
+			preview := "https://signed.test/posts/" + id + "_preview.jpg"
+			assert.Equal(t, "", post["media_url"], id)
+			assert.Equal(t, "", post["thumbnail_url"], id)
+			assert.Equal(t, preview, post["preview_url"], id)
+			assert.Equal(t, "", image["image_url"], id)
+			assert.Equal(t, "", image["thumbnail_url"], id)
+			assert.Equal(t, preview, image["preview_url"], id)
+			continue
+		}
+		assert.Equal(t, "https://signed.test/posts/"+id+".jpg", post["media_url"], id)
+		assert.Equal(t, "https://signed.test/posts/"+id+"_thumbnail.jpg", post["thumbnail_url"], id)
+		assert.Equal(t, "", post["preview_url"], id)
+		assert.Equal(t, "https://signed.test/posts/"+id+".jpg", image["image_url"], id)
+		assert.Equal(t, "", image["preview_url"], id)
+	}
+}
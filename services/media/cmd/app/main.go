@@ -0,0 +1,44 @@
+package main
+
+import (
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/database"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
+	mediaApp "lick-scroll/services/media/internal/app"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.ReleaseMode)
+}
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		panic(err)
+	}
+
+	log := logger.New()
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Error("Failed to connect to database: %v", err)
+		panic(err)
+	}
+
+	s3Client, err := s3.NewClient(cfg)
+	if err != nil {
+		log.Error("Failed to create S3 client: %v", err)
+		panic(err)
+	}
+
+	queueClient, err := queue.NewRabbitMQClient(cfg, log)
+	if err != nil {
+		log.Error("Failed to connect to RabbitMQ: %v", err)
+		panic(err)
+	}
+
+	mediaApp.Run(cfg, log, db, s3Client, queueClient)
+}
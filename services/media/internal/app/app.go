@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
+	"lick-scroll/services/media/internal/repo/persistent"
+	"lick-scroll/services/media/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, s3Client *s3.Client, queueClient *queue.Client) {
+	// Initialize repositories
+	mediaRepo := persistent.NewMediaRepository(db)
+
+	// Initialize UseCase
+	mediaUseCase := usecase.NewMediaUseCase(mediaRepo, s3Client, log)
+
+	// Setup router
+	r := gin.Default()
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+
+	// Start processing uploaded images
+	if err := queueClient.ConsumeMediaTasks(mediaUseCase.HandleImageTask); err != nil {
+		log.Error("Failed to start media consumer: %v", err)
+		panic(err)
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Info("Media service starting on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Failed to start server: %v", err)
+			panic(err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info("Shutting down media service...")
+
+	// The context is used to inform the server it has 5 seconds to finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Close RabbitMQ connection first so no task is half-processed on shutdown
+	queueClient.Close()
+
+	// Close database connection
+	sqlDB, err := db.DB()
+	if err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Error("Error closing database: %v", err)
+		}
+	}
+
+	// Shutdown server
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("Server forced to shutdown: %v", err)
+		panic(err)
+	}
+
+	log.Info("Media service exited")
+}
@@ -0,0 +1,20 @@
+package entity
+
+const (
+	ProcessingStatusPending   = "pending"
+	ProcessingStatusProcessed = "processed"
+	ProcessingStatusFailed    = "failed"
+)
+
+// Image is a post photo waiting for, or done with, processing. Variants maps
+// a variant name to the URL of its resized copy; PreviewURL is a blurred copy.
+type Image struct {
+	ID               string
+	PostID           string
+	ImageURL         string
+	ThumbnailURL     string
+	PreviewURL       string
+	Variants         map[string]string
+	ProcessingStatus string
+	Order            int
+}
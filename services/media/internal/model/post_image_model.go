@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type PostImageModel struct {
+	ID               string            `gorm:"type:uuid;primary_key"`
+	PostID           string            `gorm:"type:uuid;not null;index"`
+	ImageURL         string            `gorm:"type:varchar(500);not null"`
+	ThumbnailURL     string            `gorm:"type:varchar(500)"`
+	PreviewURL       string            `gorm:"type:varchar(500)"`
+	Variants         map[string]string `gorm:"type:jsonb;serializer:json"`
+	ProcessingStatus string            `gorm:"type:varchar(20);not null;default:'pending'"`
+	Order            int               `gorm:"default:0"`
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time `gorm:"index"`
+}
+
+func (PostImageModel) TableName() string {
+	return "post_images"
+}
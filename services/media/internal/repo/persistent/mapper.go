@@ -0,0 +1,23 @@
+package persistent
+
+import (
+	"lick-scroll/services/media/internal/entity"
+	"lick-scroll/services/media/internal/model"
+)
+
+func ToImageEntity(m *model.PostImageModel) *entity.Image {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.Image{
+		ID:               m.ID,
+		PostID:           m.PostID,
+		ImageURL:         m.ImageURL,
+		ThumbnailURL:     m.ThumbnailURL,
+		PreviewURL:       m.PreviewURL,
+		Variants:         m.Variants,
+		ProcessingStatus: m.ProcessingStatus,
+		Order:            m.Order,
+	}
+}
@@ -0,0 +1,81 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/media/internal/entity"
+	"lick-scroll/services/media/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrImageNotFound = errors.New("image not found")
+
+type MediaRepository interface {
+	GetImage(imageID string) (*entity.Image, error)
+	SaveProcessedImage(image *entity.Image) error
+	MarkFailed(imageID string) error
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+func (r *mediaRepository) GetImage(imageID string) (*entity.Image, error) {
+	var imageModel model.PostImageModel
+	err := r.db.Where("id = ? AND deleted_at IS NULL", imageID).First(&imageModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+	return ToImageEntity(&imageModel), nil
+}
+
+// SaveProcessedImage stores the image's new URLs and marks it processed. The
+// first image of a post also becomes the post's thumbnail and preview.
+func (r *mediaRepository) SaveProcessedImage(image *entity.Image) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PostImageModel{}).
+			Where("id = ? AND deleted_at IS NULL", image.ID).
+			Updates(model.PostImageModel{
+				ImageURL:         image.ImageURL,
+				ThumbnailURL:     image.ThumbnailURL,
+				PreviewURL:       image.PreviewURL,
+				Variants:         image.Variants,
+				ProcessingStatus: entity.ProcessingStatusProcessed,
+				UpdatedAt:        time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrImageNotFound
+		}
+
+		if image.Order == 0 && image.ThumbnailURL != "" {
+			return tx.Table("posts").
+				Where("id = ?", image.PostID).
+				Updates(map[string]interface{}{
+					"thumbnail_url": image.ThumbnailURL,
+					"preview_url":   image.PreviewURL,
+				}).Error
+		}
+		return nil
+	})
+}
+
+func (r *mediaRepository) MarkFailed(imageID string) error {
+	return r.db.Model(&model.PostImageModel{}).
+		Where("id = ? AND deleted_at IS NULL", imageID).
+		Updates(map[string]interface{}{
+			"processing_status": entity.ProcessingStatusFailed,
+			"updated_at":        time.Now(),
+		}).Error
+}
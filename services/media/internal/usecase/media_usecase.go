@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"strings"
+
+	"lick-scroll/pkg/imaging"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/media/internal/entity"
+	"lick-scroll/services/media/internal/repo/persistent"
+)
+
+const (
+	originalQuality = 92
+	variantQuality  = 85
+)
+
+// variant is a resized copy stored next to the original under
+// "<original key without extension>_<name><ext>".
+type variant struct {
+	name    string
+	maxSize int
+}
+
+// variants are generated for every image, smallest first. Images smaller
+// than a variant are not scaled up.
+var variants = []variant{
+	{name: "thumbnail", maxSize: 320},
+	{name: "small", maxSize: 640},
+	{name: "medium", maxSize: 1280},
+}
+
+// previewVariant is a blurred copy stored as the image's preview. It is the
+// only copy shown for paid posts the viewer hasn't unlocked, so it is kept
+// out of Variants.
+var previewVariant = variant{name: "preview", maxSize: 320}
+
+// Storage is the part of the S3 client the worker uses.
+type Storage interface {
+	DownloadFile(key string) ([]byte, error)
+	UploadFile(key string, reader io.Reader, contentType string) error
+	DeleteFile(key string) error
+}
+
+type MediaUseCase interface {
+	HandleImageTask(task map[string]interface{}) error
+}
+
+type mediaUseCase struct {
+	mediaRepo persistent.MediaRepository
+	storage   Storage
+	logger    *logger.Logger
+}
+
+func NewMediaUseCase(mediaRepo persistent.MediaRepository, storage Storage, logger *logger.Logger) MediaUseCase {
+	return &mediaUseCase{
+		mediaRepo: mediaRepo,
+		storage:   storage,
+		logger:    logger,
+	}
+}
+
+// HandleImageTask strips the metadata from an uploaded image and generates
+// its variants. The image row stores object keys, which the API presigns
+// when serving posts. Images that can't be decoded are marked failed rather than
+// retried; storage and database errors are returned so the task is requeued.
+func (uc *mediaUseCase) HandleImageTask(task map[string]interface{}) error {
+	imageID, _ := task["image_id"].(string)
+	key, _ := task["key"].(string)
+	if imageID == "" || key == "" {
+		uc.logger.Error("Invalid image task: %+v", task)
+		return nil
+	}
+
+	postImage, err := uc.mediaRepo.GetImage(imageID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrImageNotFound) {
+			uc.logger.Info("Image %s was deleted before processing, skipping", imageID)
+			return nil
+		}
+		return fmt.Errorf("failed to get image: %w", err)
+	}
+	if postImage.ProcessingStatus == entity.ProcessingStatusProcessed {
+		return nil
+	}
+
+	data, err := uc.storage.DownloadFile(key)
+	if err != nil {
+		return fmt.Errorf("failed to download image %s: %w", imageID, err)
+	}
+
+	img, format, err := imaging.Decode(data)
+	if err != nil {
+		uc.logger.Error("Failed to decode image %s: %v", imageID, err)
+		if err := uc.mediaRepo.MarkFailed(imageID); err != nil {
+			return fmt.Errorf("failed to mark image failed: %w", err)
+		}
+		return nil
+	}
+
+	base := strings.TrimSuffix(key, path.Ext(key))
+	outputFormat := imaging.OutputFormat(format)
+
+	// GIFs are kept as they are so animations survive; they carry no EXIF.
+	// Other originals are re-encoded in place to strip their metadata, except
+	// WebP, which has no encoder and is stored as JPEG under a new key.
+	originalKey := key
+	if format != "gif" {
+		if format == "webp" {
+			originalKey = base + imaging.Extension(outputFormat)
+		}
+		if err := uc.upload(originalKey, img, outputFormat, originalQuality); err != nil {
+			return err
+		}
+	}
+	postImage.ImageURL = originalKey
+
+	postImage.Variants = make(map[string]string, len(variants))
+	for _, v := range variants {
+		variantKey := fmt.Sprintf("%s_%s%s", base, v.name, imaging.Extension(outputFormat))
+		if err := uc.upload(variantKey, imaging.Fit(img, v.maxSize), outputFormat, variantQuality); err != nil {
+			return err
+		}
+		postImage.Variants[v.name] = variantKey
+	}
+	postImage.ThumbnailURL = postImage.Variants["thumbnail"]
+
+	previewKey := fmt.Sprintf("%s_%s%s", base, previewVariant.name, imaging.Extension(outputFormat))
+	if err := uc.upload(previewKey, imaging.Blur(img, previewVariant.maxSize), outputFormat, variantQuality); err != nil {
+		return err
+	}
+	postImage.PreviewURL = previewKey
+
+	if err := uc.mediaRepo.SaveProcessedImage(postImage); err != nil {
+		if errors.Is(err, persistent.ErrImageNotFound) {
+			uc.logger.Info("Image %s was deleted during processing, skipping", imageID)
+			return nil
+		}
+		return fmt.Errorf("failed to save processed image: %w", err)
+	}
+
+	if originalKey != key {
+		if err := uc.storage.DeleteFile(key); err != nil {
+			uc.logger.Error("Failed to delete original of image %s: %v", imageID, err)
+		}
+	}
+
+	uc.logger.Info("Processed image %s (%s, %d variants)", imageID, format, len(postImage.Variants))
+	return nil
+}
+
+func (uc *mediaUseCase) upload(key string, img image.Image, format string, quality int) error {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format, quality); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if err := uc.storage.UploadFile(key, bytes.NewReader(buf.Bytes()), imaging.ContentType(format)); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
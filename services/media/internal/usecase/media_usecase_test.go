@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"bytes"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/media/internal/entity"
+	"lick-scroll/services/media/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage struct {
+	files   map[string][]byte
+	deleted []string
+}
+
+func (s *fakeStorage) DownloadFile(key string) ([]byte, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return data, nil
+}
+
+func (s *fakeStorage) UploadFile(key string, reader io.Reader, contentType string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.files[key] = data
+	return nil
+}
+
+func (s *fakeStorage) DeleteFile(key string) error {
+	delete(s.files, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+type fakeMediaRepository struct {
+	images map[string]*entity.Image
+	saved  *entity.Image
+}
+
+func (r *fakeMediaRepository) GetImage(imageID string) (*entity.Image, error) {
+	image, ok := r.images[imageID]
+	if !ok {
+		return nil, persistent.ErrImageNotFound
+	}
+	copied := *image
+	return &copied, nil
+}
+
+func (r *fakeMediaRepository) SaveProcessedImage(image *entity.Image) error {
+	image.ProcessingStatus = entity.ProcessingStatusProcessed
+	r.images[image.ID] = image
+	r.saved = image
+	return nil
+}
+
+func (r *fakeMediaRepository) MarkFailed(imageID string) error {
+	r.images[imageID].ProcessingStatus = entity.ProcessingStatusFailed
+	return nil
+}
+
+func newPendingRepository() *fakeMediaRepository {
+	return &fakeMediaRepository{images: map[string]*entity.Image{
+		"image": {ID: "image", PostID: "post", ProcessingStatus: entity.ProcessingStatusPending},
+	}}
+}
+
+func imageTask(key string) map[string]interface{} {
+	return map[string]interface{}{"post_id": "post", "image_id": "image", "key": key}
+}
+
+func decodeSize(t *testing.T, data []byte) (int, int) {
+	t.Helper()
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	return config.Width, config.Height
+}
+
+func TestHandleImageTask_GeneratesVariants(t *testing.T) {
+	var original bytes.Buffer
+	require.NoError(t, jpeg.Encode(&original, image.NewRGBA(image.Rect(0, 0, 2000, 1000)), nil))
+
+	storage := &fakeStorage{files: map[string][]byte{"posts/creator/photo.jpg": original.Bytes()}}
+	repo := newPendingRepository()
+	uc := NewMediaUseCase(repo, storage, logger.New())
+
+	require.NoError(t, uc.HandleImageTask(imageTask("posts/creator/photo.jpg")))
+
+	require.NotNil(t, repo.saved)
+	assert.Equal(t, "posts/creator/photo.jpg", repo.saved.ImageURL)
+	assert.Equal(t, "posts/creator/photo_thumbnail.jpg", repo.saved.ThumbnailURL)
+	assert.Equal(t, map[string]string{
+		"thumbnail": "posts/creator/photo_thumbnail.jpg",
+		"small":     "posts/creator/photo_small.jpg",
+		"medium":    "posts/creator/photo_medium.jpg",
+	}, repo.saved.Variants)
+	assert.Equal(t, "posts/creator/photo_preview.jpg", repo.saved.PreviewURL)
+
+	width, height := decodeSize(t, storage.files["posts/creator/photo_thumbnail.jpg"])
+	assert.Equal(t, []int{320, 160}, []int{width, height})
+	width, height = decodeSize(t, storage.files["posts/creator/photo_preview.jpg"])
+	assert.Equal(t, []int{320, 160}, []int{width, height})
+	width, height = decodeSize(t, storage.files["posts/creator/photo_medium.jpg"])
+	assert.Equal(t, []int{1280, 640}, []int{width, height})
+	width, height = decodeSize(t, storage.files["posts/creator/photo.jpg"])
+	assert.Equal(t, []int{2000, 1000}, []int{width, height})
+	assert.Empty(t, storage.deleted)
+
+	// Redelivered tasks are acknowledged without processing the image again
+	repo.saved = nil
+	require.NoError(t, uc.HandleImageTask(imageTask("posts/creator/photo.jpg")))
+	assert.Nil(t, repo.saved)
+}
+
+func TestHandleImageTask_KeepsGIFOriginals(t *testing.T) {
+	var original bytes.Buffer
+	require.NoError(t, gif.Encode(&original, image.NewPaletted(image.Rect(0, 0, 100, 50), palette.Plan9), nil))
+
+	storage := &fakeStorage{files: map[string][]byte{"posts/creator/anim.gif": original.Bytes()}}
+	repo := newPendingRepository()
+	repo.images["image"].ImageURL = "posts/creator/anim.gif"
+	uc := NewMediaUseCase(repo, storage, logger.New())
+
+	require.NoError(t, uc.HandleImageTask(imageTask("posts/creator/anim.gif")))
+
+	assert.Equal(t, original.Bytes(), storage.files["posts/creator/anim.gif"])
+	assert.Equal(t, "posts/creator/anim.gif", repo.saved.ImageURL)
+	assert.Equal(t, "posts/creator/anim_thumbnail.png", repo.saved.ThumbnailURL)
+
+	// Small images are not scaled up
+	width, height := decodeSize(t, storage.files["posts/creator/anim_medium.png"])
+	assert.Equal(t, []int{100, 50}, []int{width, height})
+}
+
+func TestHandleImageTask_MarksUndecodableImagesFailed(t *testing.T) {
+	storage := &fakeStorage{files: map[string][]byte{"posts/creator/broken.jpg": []byte("not an image")}}
+	repo := newPendingRepository()
+	uc := NewMediaUseCase(repo, storage, logger.New())
+
+	assert.NoError(t, uc.HandleImageTask(imageTask("posts/creator/broken.jpg")))
+	assert.Equal(t, entity.ProcessingStatusFailed, repo.images["image"].ProcessingStatus)
+	assert.Nil(t, repo.saved)
+
+	// Missing objects are retried, deleted images and malformed tasks are not
+	assert.Error(t, uc.HandleImageTask(imageTask("posts/creator/missing.jpg")))
+	assert.NoError(t, uc.HandleImageTask(map[string]interface{}{"image_id": "deleted", "key": "posts/creator/broken.jpg"}))
+	assert.NoError(t, uc.HandleImageTask(map[string]interface{}{}))
+}
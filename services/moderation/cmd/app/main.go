@@ -0,0 +1,62 @@
+package main
+
+import (
+	"lick-scroll/pkg/cache"
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/database"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
+	moderationApp "lick-scroll/services/moderation/internal/app"
+
+	"github.com/gin-gonic/gin"
+
+	_ "lick-scroll/services/moderation/docs" // Swagger docs
+)
+
+func init() {
+	gin.SetMode(gin.ReleaseMode)
+}
+
+// @title           Moderation Service API
+// @version         1.0
+// @description     Moderation service for reviewing posts on Lick Scroll platform
+// @host      localhost:8009
+// @BasePath  /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		panic(err)
+	}
+
+	log := logger.New()
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Error("Failed to connect to database: %v", err)
+		panic(err)
+	}
+
+	redisClient, err := cache.NewRedisClient(cfg)
+	if err != nil {
+		log.Error("Failed to connect to redis: %v", err)
+		panic(err)
+	}
+
+	s3Client, err := s3.NewClient(cfg)
+	if err != nil {
+		log.Error("Failed to create S3 client: %v", err)
+		panic(err)
+	}
+
+	queueClient, err := queue.NewRabbitMQClient(cfg, log)
+	if err != nil {
+		log.Error("Failed to connect to RabbitMQ: %v (continuing without queue)", err)
+		queueClient = nil // Allow service to start without RabbitMQ
+	}
+
+	moderationApp.Run(cfg, log, db, redisClient, s3Client, queueClient)
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/moderation/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationUseCase usecase.ModerationUseCase
+	logger            *logger.Logger
+}
+
+func NewModerationHandler(moderationUseCase usecase.ModerationUseCase, logger *logger.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		moderationUseCase: moderationUseCase,
+		logger:            logger,
+	}
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ListPendingPosts godoc
+// @Summary      List pending posts
+// @Description  Get the moderation queue: posts waiting for review, oldest first. Moderators only.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Number of posts to return (max 100)"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /moderation/posts [get]
+func (h *ModerationHandler) ListPendingPosts(c *gin.Context) {
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	posts, total, err := h.moderationUseCase.ListPendingPosts(limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list pending posts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending posts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "total": total, "offset": offset})
+}
+
+// GetPost godoc
+// @Summary      Get post for review
+// @Description  Get a post with its moderation status. Moderators only.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Post ID"
+// @Success      200  {object}  entity.Post
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /moderation/posts/{id} [get]
+func (h *ModerationHandler) GetPost(c *gin.Context) {
+	post, err := h.moderationUseCase.GetPost(c.Param("id"))
+	if err != nil {
+		if err.Error() == "post not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			h.logger.Error("Failed to get post: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// ApprovePost godoc
+// @Summary      Approve post
+// @Description  Approve a pending post. The post becomes visible in feeds and the creator is notified. Moderators only.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Post ID"
+// @Success      200  {object}  entity.Post
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /moderation/posts/{id}/approve [post]
+func (h *ModerationHandler) ApprovePost(c *gin.Context) {
+	post, err := h.moderationUseCase.ApprovePost(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// RejectPost godoc
+// @Summary      Reject post
+// @Description  Reject a pending post with a reason. The creator is notified with the reason. Moderators only.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Post ID"
+// @Param        request body RejectRequest true "Rejection reason"
+// @Success      200  {object}  entity.Post
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /moderation/posts/{id}/reject [post]
+func (h *ModerationHandler) RejectPost(c *gin.Context) {
+	var req RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.moderationUseCase.RejectPost(c.Param("id"), c.GetString("user_id"), req.Reason)
+	if err != nil {
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *ModerationHandler) respondModerationError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "post not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "post is not pending moderation":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "rejection reason is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to moderate post: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/models"
+	"lick-scroll/services/moderation/internal/entity"
+	"lick-scroll/services/moderation/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockModerationUseCase is a mock implementation of ModerationUseCase
+type MockModerationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockModerationUseCase) ListPendingPosts(limit, offset int) ([]*entity.Post, int64, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*entity.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockModerationUseCase) GetPost(postID string) (*entity.Post, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockModerationUseCase) ApprovePost(postID, moderatorID string) (*entity.Post, error) {
+	args := m.Called(postID, moderatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockModerationUseCase) RejectPost(postID, moderatorID, reason string) (*entity.Post, error) {
+	args := m.Called(postID, moderatorID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+var _ usecase.ModerationUseCase = (*MockModerationUseCase)(nil)
+
+func setupModerationTestRouter(userID, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("user_role", role)
+		c.Next()
+	})
+	return r
+}
+
+func TestListPendingPosts_ForbiddenForViewer(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("user-123", "viewer")
+	router.GET("/moderation/posts", middleware.RequireRole(models.RoleModerator), handler.ListPendingPosts)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/moderation/posts", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUseCase.AssertNotCalled(t, "ListPendingPosts", mock.Anything, mock.Anything)
+}
+
+func TestListPendingPosts_Success(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("mod-1", "moderator")
+	router.GET("/moderation/posts", handler.ListPendingPosts)
+
+	posts := []*entity.Post{{ID: "post-1", Status: entity.StatusPending}}
+	mockUseCase.On("ListPendingPosts", 10, 5).Return(posts, int64(6), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/moderation/posts?limit=10&offset=5", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(1), response["count"])
+	assert.Equal(t, float64(6), response["total"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestApprovePost_Success(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("mod-1", "moderator")
+	router.POST("/moderation/posts/:id/approve", handler.ApprovePost)
+
+	mockUseCase.On("ApprovePost", "post-1", "mod-1").Return(&entity.Post{ID: "post-1", Status: entity.StatusApproved}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/moderation/posts/post-1/approve", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "approved", response["status"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestApprovePost_AlreadyModerated(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("mod-1", "moderator")
+	router.POST("/moderation/posts/:id/approve", handler.ApprovePost)
+
+	mockUseCase.On("ApprovePost", "post-1", "mod-1").Return(nil, errors.New("post is not pending moderation"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/moderation/posts/post-1/approve", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestRejectPost_MissingReason(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("mod-1", "moderator")
+	router.POST("/moderation/posts/:id/reject", handler.RejectPost)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/moderation/posts/post-1/reject", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "RejectPost", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRejectPost_Success(t *testing.T) {
+	mockUseCase := new(MockModerationUseCase)
+	handler := NewModerationHandler(mockUseCase, logger.New())
+
+	router := setupModerationTestRouter("mod-1", "moderator")
+	router.POST("/moderation/posts/:id/reject", handler.RejectPost)
+
+	mockUseCase.On("RejectPost", "post-1", "mod-1", "spam").Return(&entity.Post{
+		ID:               "post-1",
+		Status:           entity.StatusRejected,
+		ModerationReason: "spam",
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/moderation/posts/post-1/reject", bytes.NewBufferString(`{"reason":"spam"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "rejected", response["status"])
+	assert.Equal(t, "spam", response["moderation_reason"])
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type PostStatus string
+
+const (
+	StatusPending  PostStatus = "pending"
+	StatusApproved PostStatus = "approved"
+	StatusRejected PostStatus = "rejected"
+)
+
+type Post struct {
+	ID               string      `json:"id"`
+	CreatorID        string      `json:"creator_id"`
+	Title            string      `json:"title"`
+	Description      string      `json:"description"`
+	Type             string      `json:"type"`
+	MediaURL         string      `json:"media_url"`
+	ThumbnailURL     string      `json:"thumbnail_url"`
+	Category         string      `json:"category"`
+	Status           PostStatus  `json:"status"`
+	ModerationReason string      `json:"moderation_reason,omitempty"`
+	ModeratedBy      string      `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time  `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+	Images           []PostImage `json:"images,omitempty"`
+}
+
+type PostImage struct {
+	ID           string `json:"id"`
+	ImageURL     string `json:"image_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Order        int    `json:"order"`
+}
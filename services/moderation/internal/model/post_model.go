@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+type PostModel struct {
+	ID               string           `gorm:"column:id;type:uuid;primaryKey"`
+	CreatorID        string           `gorm:"column:creator_id;type:uuid;not null"`
+	Title            string           `gorm:"column:title;type:varchar(255)"`
+	Description      string           `gorm:"column:description;type:text"`
+	Type             string           `gorm:"column:type;type:varchar(50)"`
+	MediaURL         string           `gorm:"column:media_url;type:text"`
+	ThumbnailURL     string           `gorm:"column:thumbnail_url;type:text"`
+	Category         string           `gorm:"column:category;type:varchar(100)"`
+	Status           string           `gorm:"column:status;type:varchar(50)"`
+	ModerationReason *string          `gorm:"column:moderation_reason;type:text"`
+	ModeratedBy      *string          `gorm:"column:moderated_by;type:uuid"`
+	ModeratedAt      *time.Time       `gorm:"column:moderated_at;type:timestamp"`
+	CreatedAt        time.Time        `gorm:"column:created_at;type:timestamp"`
+	UpdatedAt        time.Time        `gorm:"column:updated_at;type:timestamp"`
+	DeletedAt        *time.Time       `gorm:"column:deleted_at;type:timestamp"`
+	Images           []PostImageModel `gorm:"foreignKey:PostID"`
+}
+
+func (PostModel) TableName() string {
+	return "posts"
+}
+
+type PostImageModel struct {
+	ID           string     `gorm:"column:id;type:uuid;primaryKey"`
+	PostID       string     `gorm:"column:post_id;type:uuid;not null"`
+	ImageURL     string     `gorm:"column:image_url;type:varchar(500)"`
+	ThumbnailURL string     `gorm:"column:thumbnail_url;type:varchar(500)"`
+	Order        int        `gorm:"column:order;type:integer;default:0"`
+	DeletedAt    *time.Time `gorm:"column:deleted_at;type:timestamp"`
+}
+
+func (PostImageModel) TableName() string {
+	return "post_images"
+}
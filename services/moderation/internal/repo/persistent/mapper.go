@@ -0,0 +1,49 @@
+package persistent
+
+import (
+	"lick-scroll/services/moderation/internal/entity"
+	"lick-scroll/services/moderation/internal/model"
+)
+
+func ToPostEntity(m *model.PostModel) *entity.Post {
+	if m == nil {
+		return nil
+	}
+	post := &entity.Post{
+		ID:           m.ID,
+		CreatorID:    m.CreatorID,
+		Title:        m.Title,
+		Description:  m.Description,
+		Type:         m.Type,
+		MediaURL:     m.MediaURL,
+		ThumbnailURL: m.ThumbnailURL,
+		Category:     m.Category,
+		Status:       entity.PostStatus(m.Status),
+		ModeratedAt:  m.ModeratedAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+	if m.ModerationReason != nil {
+		post.ModerationReason = *m.ModerationReason
+	}
+	if m.ModeratedBy != nil {
+		post.ModeratedBy = *m.ModeratedBy
+	}
+	for _, img := range m.Images {
+		post.Images = append(post.Images, entity.PostImage{
+			ID:           img.ID,
+			ImageURL:     img.ImageURL,
+			ThumbnailURL: img.ThumbnailURL,
+			Order:        img.Order,
+		})
+	}
+	return post
+}
+
+func ToPostEntities(models []model.PostModel) []*entity.Post {
+	results := make([]*entity.Post, len(models))
+	for i := range models {
+		results[i] = ToPostEntity(&models[i])
+	}
+	return results
+}
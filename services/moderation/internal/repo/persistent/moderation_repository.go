@@ -0,0 +1,75 @@
+package persistent
+
+import (
+	"time"
+
+	"lick-scroll/services/moderation/internal/entity"
+	"lick-scroll/services/moderation/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ModerationRepository interface {
+	ListByStatus(status entity.PostStatus, limit, offset int) ([]*entity.Post, error)
+	CountByStatus(status entity.PostStatus) (int64, error)
+	GetPostByID(postID string) (*entity.Post, error)
+	UpdateStatus(postID string, from, to entity.PostStatus, moderatorID, reason string) (bool, error)
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (r *moderationRepository) ListByStatus(status entity.PostStatus, limit, offset int) ([]*entity.Post, error) {
+	var postModels []model.PostModel
+	query := r.db.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Where("deleted_at IS NULL").Order("post_images.order ASC")
+	}).Where("status = ? AND deleted_at IS NULL", string(status)).Order("created_at ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	if err := query.Find(&postModels).Error; err != nil {
+		return nil, err
+	}
+	return ToPostEntities(postModels), nil
+}
+
+func (r *moderationRepository) CountByStatus(status entity.PostStatus) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.PostModel{}).Where("status = ? AND deleted_at IS NULL", string(status)).Count(&count).Error
+	return count, err
+}
+
+func (r *moderationRepository) GetPostByID(postID string) (*entity.Post, error) {
+	var postModel model.PostModel
+	if err := r.db.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Where("deleted_at IS NULL").Order("post_images.order ASC")
+	}).Where("id = ? AND deleted_at IS NULL", postID).First(&postModel).Error; err != nil {
+		return nil, err
+	}
+	return ToPostEntity(&postModel), nil
+}
+
+// UpdateStatus moves a post from one status to another. The status check is part of
+// the UPDATE so two moderators acting on the same post cannot both succeed.
+func (r *moderationRepository) UpdateStatus(postID string, from, to entity.PostStatus, moderatorID, reason string) (bool, error) {
+	result := r.db.Model(&model.PostModel{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", postID, string(from)).
+		Updates(map[string]interface{}{
+			"status":            string(to),
+			"moderation_reason": reason,
+			"moderated_by":      moderatorID,
+			"moderated_at":      time.Now(),
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
+	"lick-scroll/services/moderation/internal/entity"
+	"lick-scroll/services/moderation/internal/repo/persistent"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type ModerationUseCase interface {
+	ListPendingPosts(limit, offset int) ([]*entity.Post, int64, error)
+	GetPost(postID string) (*entity.Post, error)
+	ApprovePost(postID, moderatorID string) (*entity.Post, error)
+	RejectPost(postID, moderatorID, reason string) (*entity.Post, error)
+}
+
+type moderationUseCase struct {
+	moderationRepo persistent.ModerationRepository
+	redisClient    *redis.Client
+	s3Client       *s3.Client
+	queueClient    *queue.Client
+	logger         *logger.Logger
+}
+
+func NewModerationUseCase(moderationRepo persistent.ModerationRepository, redisClient *redis.Client, s3Client *s3.Client, queueClient *queue.Client, logger *logger.Logger) ModerationUseCase {
+	return &moderationUseCase{
+		moderationRepo: moderationRepo,
+		redisClient:    redisClient,
+		s3Client:       s3Client,
+		queueClient:    queueClient,
+		logger:         logger,
+	}
+}
+
+func (uc *moderationUseCase) ListPendingPosts(limit, offset int) ([]*entity.Post, int64, error) {
+	posts, err := uc.moderationRepo.ListByStatus(entity.StatusPending, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending posts: %w", err)
+	}
+
+	total, err := uc.moderationRepo.CountByStatus(entity.StatusPending)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending posts: %w", err)
+	}
+
+	for _, post := range posts {
+		uc.signMedia(post)
+	}
+	return posts, total, nil
+}
+
+func (uc *moderationUseCase) GetPost(postID string) (*entity.Post, error) {
+	post, err := uc.moderationRepo.GetPostByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("post not found")
+		}
+		return nil, err
+	}
+	uc.signMedia(post)
+	return post, nil
+}
+
+// signMedia replaces the object keys of a post with presigned URLs so
+// moderators can review media in the private bucket.
+func (uc *moderationUseCase) signMedia(post *entity.Post) {
+	post.MediaURL = uc.s3Client.MediaURL(post.MediaURL)
+	post.ThumbnailURL = uc.s3Client.MediaURL(post.ThumbnailURL)
+	for i := range post.Images {
+		post.Images[i].ImageURL = uc.s3Client.MediaURL(post.Images[i].ImageURL)
+		post.Images[i].ThumbnailURL = uc.s3Client.MediaURL(post.Images[i].ThumbnailURL)
+	}
+}
+
+func (uc *moderationUseCase) ApprovePost(postID, moderatorID string) (*entity.Post, error) {
+	post, err := uc.moderate(postID, moderatorID, entity.StatusApproved, "")
+	if err != nil {
+		return nil, err
+	}
+
+	uc.addToFeed(post)
+
+	if uc.queueClient != nil {
+		go uc.publishModerationNotification(post)
+		go uc.publishNewPostNotification(post)
+		go uc.publishPostCreatedEvent(post)
+	}
+
+	return post, nil
+}
+
+func (uc *moderationUseCase) RejectPost(postID, moderatorID, reason string) (*entity.Post, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("rejection reason is required")
+	}
+
+	post, err := uc.moderate(postID, moderatorID, entity.StatusRejected, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.queueClient != nil {
+		go uc.publishModerationNotification(post)
+	}
+
+	return post, nil
+}
+
+func (uc *moderationUseCase) moderate(postID, moderatorID string, status entity.PostStatus, reason string) (*entity.Post, error) {
+	post, err := uc.GetPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Status != entity.StatusPending {
+		return nil, fmt.Errorf("post is not pending moderation")
+	}
+
+	updated, err := uc.moderationRepo.UpdateStatus(postID, entity.StatusPending, status, moderatorID, reason)
+	if err != nil {
+		uc.logger.Error("Failed to update post status: %v", err)
+		return nil, fmt.Errorf("failed to update post status: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("post is not pending moderation")
+	}
+
+	now := time.Now()
+	post.Status = status
+	post.ModerationReason = reason
+	post.ModeratedBy = moderatorID
+	post.ModeratedAt = &now
+
+	uc.updateCachedStatus(post)
+	uc.logger.Info("Post %s moderated by %s: status=%s", post.ID, moderatorID, status)
+
+	return post, nil
+}
+
+func (uc *moderationUseCase) updateCachedStatus(post *entity.Post) {
+	ctx := context.Background()
+	postKey := fmt.Sprintf("post:%s", post.ID)
+	if exists, err := uc.redisClient.Exists(ctx, postKey).Result(); err == nil && exists > 0 {
+		uc.redisClient.HSet(ctx, postKey, "status", string(post.Status))
+	}
+}
+
+func (uc *moderationUseCase) addToFeed(post *entity.Post) {
+	ctx := context.Background()
+	globalFeedKey := "feed:global"
+	uc.redisClient.LPush(ctx, globalFeedKey, post.ID)
+	uc.redisClient.LTrim(ctx, globalFeedKey, 0, 9999)
+	uc.redisClient.Expire(ctx, globalFeedKey, 7*24*time.Hour)
+
+	if post.Category != "" {
+		categoryFeedKey := fmt.Sprintf("feed:global:%s", post.Category)
+		uc.redisClient.LPush(ctx, categoryFeedKey, post.ID)
+		uc.redisClient.LTrim(ctx, categoryFeedKey, 0, 9999)
+		uc.redisClient.Expire(ctx, categoryFeedKey, 7*24*time.Hour)
+	}
+}
+
+func (uc *moderationUseCase) publishModerationNotification(post *entity.Post) {
+	task := map[string]interface{}{
+		"type":     "post_moderated",
+		"user_id":  post.CreatorID,
+		"post_id":  post.ID,
+		"status":   string(post.Status),
+		"reason":   post.ModerationReason,
+		"priority": 5,
+	}
+
+	uc.logger.Info("[NOTIFICATION QUEUE] Publishing post_moderated task to RabbitMQ: post_id=%s, status=%s", post.ID, post.Status)
+	if err := uc.queueClient.PublishNotificationTask(task); err != nil {
+		uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish post_moderated task to RabbitMQ: %v (post_id=%s)", err, post.ID)
+	}
+}
+
+func (uc *moderationUseCase) publishNewPostNotification(post *entity.Post) {
+	task := map[string]interface{}{
+		"type":       "new_post",
+		"post_id":    post.ID,
+		"creator_id": post.CreatorID,
+		"category":   post.Category,
+		"priority":   5,
+	}
+
+	uc.logger.Info("[NOTIFICATION QUEUE] Publishing new_post task to RabbitMQ: post_id=%s, creator_id=%s", post.ID, post.CreatorID)
+	if err := uc.queueClient.PublishNotificationTask(task); err != nil {
+		uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish new_post task to RabbitMQ: %v (post_id=%s, creator_id=%s)", err, post.ID, post.CreatorID)
+	}
+}
+
+// publishPostCreatedEvent lets the fanout service push the post into its
+// followers' timelines. A post only becomes visible once approved.
+func (uc *moderationUseCase) publishPostCreatedEvent(post *entity.Post) {
+	event := map[string]interface{}{
+		"post_id":    post.ID,
+		"creator_id": post.CreatorID,
+	}
+
+	if err := uc.queueClient.PublishEvent(queue.EventPostCreated, event); err != nil {
+		uc.logger.Error("Failed to publish %s event: %v (post_id=%s)", queue.EventPostCreated, err, post.ID)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
 	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/serviceauth"
 	notificationHTTP "lick-scroll/services/notification/internal/controller/http"
 	"lick-scroll/services/notification/internal/repo/persistent"
 	"lick-scroll/services/notification/internal/usecase"
@@ -29,7 +30,18 @@ import (
 )
 
 func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, queueClient *queue.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
+
+	serviceKeys, err := serviceauth.ParseKeys(cfg.ServiceKeys)
+	if err != nil {
+		log.Error("Invalid SERVICE_KEYS: %v", err)
+		panic(err)
+	}
+	if len(serviceKeys) == 0 {
+		log.Warn("SERVICE_KEYS is empty; internal notification routes will reject every request")
+	}
+	serviceVerifier := serviceauth.NewVerifier(serviceKeys, serviceauth.NewRedisNonceStore(redisClient))
 
 	// Initialize Repository
 	notificationRepo := persistent.NewNotificationRepository(db)
@@ -38,7 +50,7 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, redisClient, queueClient, log)
 
 	// Initialize HTTP handlers
-	notificationHandler := notificationHTTP.NewNotificationHandler(notificationUseCase, redisClient, log, jwtService)
+	notificationHandler := notificationHTTP.NewNotificationHandler(notificationUseCase, redisClient, log, jwtService, denylist)
 
 	// Setup router
 	r := gin.Default()
@@ -64,7 +76,7 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	api := r.Group("/api/v1")
 	// Protected routes - require authentication
 	protected := api.Group("")
-	protected.Use(middleware.AuthMiddleware(jwtService))
+	protected.Use(middleware.AuthMiddleware(jwtService, denylist))
 	{
 		protected.GET("/notifications", notificationHandler.GetNotifications)
 		protected.DELETE("/notifications/:post_id", notificationHandler.DeleteNotificationByPostID)
@@ -74,11 +86,14 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	}
 	// WebSocket endpoint - handles authentication internally via query parameter
 	api.GET("/notifications/ws", notificationHandler.HandleWebSocket)
-	// Admin routes - no auth required (for internal service calls)
+	// Internal routes - called only by other services. Callers must sign every
+	// request as a trusted service with one of SERVICE_KEYS; others get 401.
+	internal := api.Group("")
+	internal.Use(middleware.ServiceAuthMiddleware(serviceVerifier))
 	{
-		api.POST("/notifications/send", notificationHandler.SendNotification)
-		api.POST("/notifications/broadcast", notificationHandler.BroadcastNotification)
-		api.POST("/notifications/process-queue", notificationHandler.ProcessNotificationQueue)
+		internal.POST("/notifications/send", notificationHandler.SendNotification)
+		internal.POST("/notifications/broadcast", notificationHandler.BroadcastNotification)
+		internal.POST("/notifications/process-queue", notificationHandler.ProcessNotificationQueue)
 	}
 
 	// Create HTTP server
@@ -112,6 +127,14 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 				return notificationUseCase.HandleLikeNotification(task)
 			case "subscription":
 				return notificationUseCase.HandleSubscriptionNotification(task)
+			case "post_moderated":
+				return notificationUseCase.HandlePostModeratedNotification(task)
+			case "refund":
+				return notificationUseCase.HandleRefundNotification(task)
+			case "comment":
+				return notificationUseCase.HandleCommentNotification(task)
+			case "creator_application":
+				return notificationUseCase.HandleCreatorApplicationNotification(task)
 			default:
 				log.Error("[NOTIFICATION HANDLER] Unknown notification type: %s, task=%+v", notificationType, task)
 				return fmt.Errorf("unknown notification type: %s", notificationType)
@@ -26,14 +26,16 @@ type NotificationHandler struct {
 	redisClient         *redis.Client
 	logger              *logger.Logger
 	jwtService          *jwt.Service
+	denylist            *jwt.Denylist
 }
 
-func NewNotificationHandler(notificationUseCase usecase.NotificationUseCase, redisClient *redis.Client, logger *logger.Logger, jwtService *jwt.Service) *NotificationHandler {
+func NewNotificationHandler(notificationUseCase usecase.NotificationUseCase, redisClient *redis.Client, logger *logger.Logger, jwtService *jwt.Service, denylist *jwt.Denylist) *NotificationHandler {
 	return &NotificationHandler{
 		notificationUseCase: notificationUseCase,
 		redisClient:         redisClient,
 		logger:               logger,
 		jwtService:           jwtService,
+		denylist:             denylist,
 	}
 }
 
@@ -101,8 +103,9 @@ func (h *NotificationHandler) BroadcastNotification(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        limit query int false "Number of notifications to return (max 100)"
-// @Param        offset query int false "Offset for pagination"
+// @Param        cursor query string false "next_cursor from the previous page"
 // @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /notifications [get]
 func (h *NotificationHandler) GetNotifications(c *gin.Context) {
@@ -119,15 +122,12 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		}
 	}
 
-	offset := 0
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
-	}
-
-	notifications, totalCount, err := h.notificationUseCase.GetNotifications(userID, limit, offset)
+	notifications, nextCursor, totalCount, err := h.notificationUseCase.GetNotifications(userID, limit, c.Query("cursor"))
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to get notifications: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notifications"})
 		return
@@ -137,7 +137,7 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		"notifications": notifications,
 		"count":         len(notifications),
 		"total":         totalCount,
-		"offset":        offset,
+		"next_cursor":   nextCursor,
 	})
 }
 
@@ -296,6 +296,17 @@ func (h *NotificationHandler) HandleWebSocket(c *gin.Context) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			return
 		}
+
+		// Revocation is checked when connecting; an open connection isn't closed
+		revoked, err := h.denylist.IsRevoked(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Session check failed"})
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			return
+		}
 		
 		userID = claims.UserID
 	}
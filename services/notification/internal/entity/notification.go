@@ -2,6 +2,7 @@ package entity
 
 // Notification represents a notification sent to a user
 type Notification struct {
+	ID        string                 `json:"id"`
 	UserID    string                 `json:"user_id"`
 	Title     string                 `json:"title"`
 	Message   string                 `json:"message"`
@@ -11,6 +11,7 @@ type NotificationRepository interface {
 	GetSubscribers(creatorID string) ([]string, error)
 	GetLikerUsername(likerID string) (string, error)
 	GetSubscriberUsername(subscriberID string) (string, error)
+	GetCommenterUsername(commenterID string) (string, error)
 }
 
 type notificationRepository struct {
@@ -47,6 +48,15 @@ func (r *notificationRepository) GetLikerUsername(likerID string) (string, error
 	return ToUserEntity(&userModel), nil
 }
 
+func (r *notificationRepository) GetCommenterUsername(commenterID string) (string, error) {
+	var userModel model.UserModel
+	err := r.db.Where("id = ?", commenterID).Select("username").First(&userModel).Error
+	if err != nil {
+		return "", err
+	}
+	return ToUserEntity(&userModel), nil
+}
+
 func (r *notificationRepository) GetSubscriberUsername(subscriberID string) (string, error) {
 	var userModel model.UserModel
 	err := r.db.Where("id = ?", subscriberID).Select("username").First(&userModel).Error
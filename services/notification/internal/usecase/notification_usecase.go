@@ -7,17 +7,19 @@ import (
 	"time"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/services/notification/internal/entity"
 	"lick-scroll/services/notification/internal/repo/persistent"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type NotificationUseCase interface {
 	SendNotification(userID, title, message, notificationType string, data map[string]interface{}) (*entity.Notification, error)
 	BroadcastNotification(userIDs []string, title, message, notificationType string, data map[string]interface{}) (int, error)
-	GetNotifications(userID string, limit, offset int) ([]entity.Notification, int64, error)
+	GetNotifications(userID string, limit int, cursor string) ([]entity.Notification, string, int64, error)
 	DeleteNotificationByPostID(userID, postID string) (int, error)
 	GetNotificationSettings(userID, creatorID string) (bool, error)
 	EnableNotifications(userID, creatorID string) error
@@ -26,6 +28,10 @@ type NotificationUseCase interface {
 	HandleNewPostNotification(task map[string]interface{}) error
 	HandleLikeNotification(task map[string]interface{}) error
 	HandleSubscriptionNotification(task map[string]interface{}) error
+	HandlePostModeratedNotification(task map[string]interface{}) error
+	HandleRefundNotification(task map[string]interface{}) error
+	HandleCommentNotification(task map[string]interface{}) error
+	HandleCreatorApplicationNotification(task map[string]interface{}) error
 }
 
 type notificationUseCase struct {
@@ -86,26 +92,63 @@ func (uc *notificationUseCase) BroadcastNotification(userIDs []string, title, me
 	return sentCount, nil
 }
 
-func (uc *notificationUseCase) GetNotifications(userID string, limit, offset int) ([]entity.Notification, int64, error) {
+func (uc *notificationUseCase) GetNotifications(userID string, limit int, cursor string) ([]entity.Notification, string, int64, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("invalid cursor")
+	}
+
 	ctx := context.Background()
 	userNotificationsKey := fmt.Sprintf("notifications:%s", userID)
 
-	allNotifications, err := uc.redisClient.LRange(ctx, userNotificationsKey, int64(offset), int64(offset+limit-1)).Result()
+	// The list only keeps the last 100 notifications, newest first, so it is
+	// read whole and the cursor applied here
+	allNotifications, err := uc.redisClient.LRange(ctx, userNotificationsKey, 0, -1).Result()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to get notifications: %w", err)
 	}
 
 	var notifications []entity.Notification
+	passedCursor := after == nil
 	for _, notifJSON := range allNotifications {
 		var notification entity.Notification
-		if err := json.Unmarshal([]byte(notifJSON), &notification); err == nil {
-			notifications = append(notifications, notification)
+		if err := json.Unmarshal([]byte(notifJSON), &notification); err != nil {
+			continue
+		}
+
+		// Skip up to the notification the cursor points at, or past anything
+		// newer than it if that one has since been deleted
+		if !passedCursor {
+			if notification.ID != "" && notification.ID == after.ID {
+				passedCursor = true
+				continue
+			}
+			if !notificationTime(notification).Before(after.CreatedAt) {
+				continue
+			}
+			passedCursor = true
+		}
+
+		notifications = append(notifications, notification)
+		if len(notifications) == limit {
+			break
 		}
 	}
 
+	nextCursor := ""
+	if len(notifications) > 0 && len(notifications) == limit {
+		last := notifications[len(notifications)-1]
+		nextCursor = pagination.Encode(notificationTime(last), last.ID)
+	}
+
 	totalCount, _ := uc.redisClient.LLen(ctx, userNotificationsKey).Result()
 
-	return notifications, totalCount, nil
+	return notifications, nextCursor, totalCount, nil
+}
+
+func notificationTime(notification entity.Notification) time.Time {
+	createdAt, _ := time.Parse(time.RFC3339, notification.CreatedAt)
+	return createdAt
 }
 
 func (uc *notificationUseCase) DeleteNotificationByPostID(userID, postID string) (int, error) {
@@ -356,7 +399,187 @@ func (uc *notificationUseCase) HandleSubscriptionNotification(task map[string]in
 	return nil
 }
 
+func (uc *notificationUseCase) HandlePostModeratedNotification(task map[string]interface{}) error {
+	userID, _ := task["user_id"].(string) // Creator of the post (recipient)
+	postID, _ := task["post_id"].(string)
+	status, _ := task["status"].(string)
+	reason, _ := task["reason"].(string)
+
+	if userID == "" || postID == "" || status == "" {
+		uc.logger.Error("[NOTIFICATION HANDLER] Invalid post_moderated task: missing user_id, post_id or status, task=%+v", task)
+		return fmt.Errorf("invalid task: missing required fields")
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Processing post_moderated notification: user_id=%s, post_id=%s, status=%s", userID, postID, status)
+
+	title := "Post Approved"
+	message := "Your post has been approved and is now visible to everyone"
+	if status == "rejected" {
+		title = "Post Rejected"
+		message = fmt.Sprintf("Your post was rejected: %s", reason)
+	}
+
+	notification := &entity.Notification{
+		UserID:    userID,
+		Title:     title,
+		Message:   message,
+		Type:      "post_moderated",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data: map[string]interface{}{
+			"post_id": postID,
+			"status":  status,
+			"reason":  reason,
+		},
+	}
+
+	if err := uc.sendNotificationToRedis(notification); err != nil {
+		uc.logger.Error("[NOTIFICATION HANDLER] Failed to send post_moderated notification to user %s: %v", userID, err)
+		return err
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Successfully sent post_moderated notification to user %s", userID)
+	return nil
+}
+
+func (uc *notificationUseCase) HandleCreatorApplicationNotification(task map[string]interface{}) error {
+	userID, _ := task["user_id"].(string) // Applicant (recipient)
+	applicationID, _ := task["application_id"].(string)
+	status, _ := task["status"].(string)
+	reason, _ := task["reason"].(string)
+
+	if userID == "" || applicationID == "" || status == "" {
+		uc.logger.Error("[NOTIFICATION HANDLER] Invalid creator_application task: missing user_id, application_id or status, task=%+v", task)
+		return fmt.Errorf("invalid task: missing required fields")
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Processing creator_application notification: user_id=%s, application_id=%s, status=%s", userID, applicationID, status)
+
+	title := "You are now a creator"
+	message := "Your creator application has been approved. You can now publish posts"
+	if status == "rejected" {
+		title = "Creator Application Rejected"
+		message = fmt.Sprintf("Your creator application was rejected: %s", reason)
+	}
+
+	notification := &entity.Notification{
+		UserID:    userID,
+		Title:     title,
+		Message:   message,
+		Type:      "creator_application",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data: map[string]interface{}{
+			"application_id": applicationID,
+			"status":         status,
+			"reason":         reason,
+		},
+	}
+
+	if err := uc.sendNotificationToRedis(notification); err != nil {
+		uc.logger.Error("[NOTIFICATION HANDLER] Failed to send creator_application notification to user %s: %v", userID, err)
+		return err
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Successfully sent creator_application notification to user %s", userID)
+	return nil
+}
+
+func (uc *notificationUseCase) HandleRefundNotification(task map[string]interface{}) error {
+	userID, _ := task["user_id"].(string) // Donor or creator (recipient)
+	transactionID, _ := task["transaction_id"].(string)
+	postID, _ := task["post_id"].(string)
+	amount, _ := task["amount"].(float64) // Positive for the donor, negative for the creator
+
+	if userID == "" || transactionID == "" {
+		uc.logger.Error("[NOTIFICATION HANDLER] Invalid refund task: missing user_id or transaction_id, task=%+v", task)
+		return fmt.Errorf("invalid task: missing required fields")
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Processing refund notification: user_id=%s, transaction_id=%s, amount=%d", userID, transactionID, int(amount))
+
+	title := "Donation Refunded"
+	message := fmt.Sprintf("Your donation of %d was refunded to your wallet", int(amount))
+	if amount < 0 {
+		title = "Donation Reversed"
+		message = fmt.Sprintf("A donation of %d to your post was refunded to the donor", int(-amount))
+	}
+
+	notification := &entity.Notification{
+		UserID:    userID,
+		Title:     title,
+		Message:   message,
+		Type:      "refund",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data: map[string]interface{}{
+			"transaction_id":        transactionID,
+			"refund_transaction_id": task["refund_transaction_id"],
+			"post_id":               postID,
+			"amount":                int(amount),
+		},
+	}
+
+	if err := uc.sendNotificationToRedis(notification); err != nil {
+		uc.logger.Error("[NOTIFICATION HANDLER] Failed to send refund notification to user %s: %v", userID, err)
+		return err
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Successfully sent refund notification to user %s", userID)
+	return nil
+}
+
+func (uc *notificationUseCase) HandleCommentNotification(task map[string]interface{}) error {
+	userID, _ := task["user_id"].(string)           // Post creator or parent comment author (recipient)
+	commenterID, _ := task["commenter_id"].(string) // User who commented
+	postID, _ := task["post_id"].(string)
+	commentID, _ := task["comment_id"].(string)
+	isReply, _ := task["is_reply"].(bool)
+
+	if userID == "" || commenterID == "" || postID == "" || commentID == "" {
+		uc.logger.Error("[NOTIFICATION HANDLER] Invalid comment task: missing user_id, commenter_id, post_id or comment_id, task=%+v", task)
+		return fmt.Errorf("invalid task: missing required fields")
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Processing comment notification: user_id=%s, commenter_id=%s, post_id=%s, comment_id=%s", userID, commenterID, postID, commentID)
+
+	commenterUsername, err := uc.notificationRepo.GetCommenterUsername(commenterID)
+	if err != nil {
+		commenterUsername = "Someone"
+	}
+
+	title := "New Comment!"
+	message := fmt.Sprintf("%s commented on your post", commenterUsername)
+	if isReply {
+		title = "New Reply!"
+		message = fmt.Sprintf("%s replied to your comment", commenterUsername)
+	}
+
+	notification := &entity.Notification{
+		UserID:    userID,
+		Title:     title,
+		Message:   message,
+		Type:      "comment",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data: map[string]interface{}{
+			"post_id":      postID,
+			"comment_id":   commentID,
+			"commenter_id": commenterID,
+			"is_reply":     isReply,
+		},
+	}
+
+	if err := uc.sendNotificationToRedis(notification); err != nil {
+		uc.logger.Error("[NOTIFICATION HANDLER] Failed to send comment notification to user %s: %v", userID, err)
+		return err
+	}
+
+	uc.logger.Info("[NOTIFICATION HANDLER] Successfully sent comment notification to user %s", userID)
+	return nil
+}
+
 func (uc *notificationUseCase) sendNotificationToRedis(notification *entity.Notification) error {
+	if notification.ID == "" {
+		notification.ID = uuid.New().String()
+	}
+
 	notificationJSON, err := json.Marshal(notification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal notification: %w", err)
@@ -12,6 +12,7 @@ import (
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/models"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/pkg/s3"
 	postHTTP "lick-scroll/services/post/internal/controller/http"
@@ -29,7 +30,8 @@ import (
 )
 
 func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, s3Client *s3.Client, queueClient *queue.Client, redisClient *redis.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
 
 	// Initialize repositories
 	postRepo := persistent.NewPostRepository(db)
@@ -62,11 +64,15 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, s3Client *s3.Clien
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(jwtService))
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
 	api.Use(middleware.RateLimitMiddleware(redisClient, 100, time.Minute))
 
+	creatorOnly := middleware.RequireRole(models.RoleCreator)
+
 	{
-		api.POST("/posts", postHandler.CreatePost)
+		api.POST("/posts", creatorOnly, postHandler.CreatePost)
+		api.POST("/posts/uploads", creatorOnly, postHandler.CreateUpload)
+		api.POST("/posts/uploads/:upload_id/finalize", creatorOnly, postHandler.FinalizeUpload)
 		api.GET("/posts/:id", postHandler.GetPost)
 		api.GET("/posts", postHandler.ListPosts)
 		api.PUT("/posts/:id", postHandler.UpdatePost)
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/upload"
 	"lick-scroll/services/post/internal/entity"
 	"lick-scroll/services/post/internal/usecase"
 
@@ -32,18 +34,21 @@ func NewPostHandler(postUseCase usecase.PostUseCase, redisClient *redis.Client,
 
 func (h *PostHandler) formatPostResponse(post *entity.Post, likeCount int64) map[string]interface{} {
 	response := map[string]interface{}{
-		"id":          post.ID,
-		"creator_id":  post.CreatorID,
-		"title":       post.Title,
-		"description": post.Description,
-		"type":        post.Type,
-		"category":    post.Category,
-		"status":      post.Status,
-		"views":       post.Views,
-		"likes_count": likeCount,
-		"images":      post.Images,
-		"created_at":  post.CreatedAt,
-		"updated_at":  post.UpdatedAt,
+		"id":              post.ID,
+		"creator_id":      post.CreatorID,
+		"title":           post.Title,
+		"description":     post.Description,
+		"type":            post.Type,
+		"category":        post.Category,
+		"price":           post.Price,
+		"is_locked":       post.IsLocked,
+		"subscriber_only": post.SubscriberOnly,
+		"status":          post.Status,
+		"views":           post.Views,
+		"likes_count":     likeCount,
+		"images":          post.Images,
+		"created_at":      post.CreatedAt,
+		"updated_at":      post.UpdatedAt,
 	}
 
 	if post.MediaURL != "" && len(post.Images) == 0 {
@@ -54,19 +59,25 @@ func (h *PostHandler) formatPostResponse(post *entity.Post, likeCount int64) map
 		response["thumbnail_url"] = post.ThumbnailURL
 	}
 
+	if post.PreviewURL != "" {
+		response["preview_url"] = post.PreviewURL
+	}
+
 	return response
 }
 
 type CreatePostRequest struct {
-	Title       string `form:"title" binding:"required"`
-	Description string `form:"description"`
-	Type        string `form:"type" binding:"required,oneof=photo video"`
-	Category    string `form:"category"`
+	Title          string `form:"title" binding:"required"`
+	Description    string `form:"description"`
+	Type           string `form:"type" binding:"required,oneof=photo video"`
+	Category       string `form:"category"`
+	Price          int    `form:"price" binding:"min=0"`
+	SubscriberOnly bool   `form:"subscriber_only"`
 }
 
 // CreatePost godoc
 // @Summary      Create a new post
-// @Description  Create a new post with media files. For photo posts, you can upload multiple images. For video posts, upload one video file (up to 30s).
+// @Description  Create a new post with media files. For photo posts, you can upload multiple images. For video posts, upload one video file (up to 30s). Creators only.
 // @Tags         posts
 // @Accept       multipart/form-data
 // @Produce      json
@@ -75,11 +86,15 @@ type CreatePostRequest struct {
 // @Param        description formData string false "Post description"
 // @Param        type formData string true "Post type (photo or video)" Enums(photo, video)
 // @Param        category formData string false "Post category"
-// @Param        media formData file false "Media file (for video: mp4/mov/avi, for photo: jpg/jpeg/png) - deprecated, use images[] instead"
-// @Param        images formData file false "Image files (jpg/jpeg/png) - multiple files allowed for photo posts"
+// @Param        price formData int false "Price in coins to unlock the post (0 = free)"
+// @Param        subscriber_only formData bool false "Only show the post to paid subscribers"
+// @Param        media formData file false "Media file (for video: mp4/mov/avi up to 100 MB, for photo: jpg/jpeg/png/gif/webp) - deprecated, use images[] instead"
+// @Param        images formData file false "Image files (jpg/jpeg/png/gif/webp up to 20 MB each) - multiple files allowed for photo posts"
 // @Success      201  {object}  models.Post
-// @Failure      400  {object}  map[string]string
+// @Failure      400  {object}  map[string]interface{}
 // @Failure      403  {object}  map[string]string
+// @Failure      413  {object}  map[string]interface{}
+// @Failure      415  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]string
 // @Router       /posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
@@ -124,8 +139,13 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		imageFiles = files
 	}
 
-	post, err := h.postUseCase.CreatePost(userID, req.Title, req.Description, req.Type, req.Category, mediaFile, imageFiles)
+	post, err := h.postUseCase.CreatePost(userID, req.Title, req.Description, req.Type, req.Category, req.Price, req.SubscriberOnly, mediaFile, imageFiles)
 	if err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			c.JSON(uploadErr.Status, uploadErr.Body())
+			return
+		}
 		h.logger.Error("Failed to create post: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -134,9 +154,118 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	c.JSON(http.StatusCreated, post)
 }
 
+type CreateUploadRequest struct {
+	Filename string `json:"filename" binding:"required"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+}
+
+// CreateUpload godoc
+// @Summary      Start a direct video upload
+// @Description  Get a presigned URL to upload a video (mp4/mov/avi up to 100 MB) straight to storage. PUT the file to upload_url with the returned headers before expires_at, then call the finalize endpoint to create the post. Creators only.
+// @Tags         posts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateUploadRequest true "File name and size in bytes"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Failure      413  {object}  map[string]interface{}
+// @Failure      415  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /posts/uploads [post]
+func (h *PostHandler) CreateUpload(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upl, err := h.postUseCase.CreateUpload(userID, req.Filename, req.Size)
+	if err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			c.JSON(uploadErr.Status, uploadErr.Body())
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"upload_id":  upl.ID,
+		"upload_url": upl.UploadURL,
+		"method":     http.MethodPut,
+		"headers": gin.H{
+			"Content-Type": upl.ContentType,
+		},
+		"expires_at": upl.ExpiresAt,
+	})
+}
+
+type FinalizeUploadRequest struct {
+	Title          string `json:"title" binding:"required"`
+	Description    string `json:"description"`
+	Category       string `json:"category"`
+	Price          int    `json:"price" binding:"min=0"`
+	SubscriberOnly bool   `json:"subscriber_only"`
+}
+
+// FinalizeUpload godoc
+// @Summary      Finalize a direct video upload
+// @Description  Check the uploaded video and create a video post from it. Uploads that fail validation are deleted. Creators only.
+// @Tags         posts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        upload_id path string true "Upload ID"
+// @Param        request body FinalizeUploadRequest true "Post details"
+// @Success      201  {object}  models.Post
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      415  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /posts/uploads/{upload_id}/finalize [post]
+func (h *PostHandler) FinalizeUpload(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req FinalizeUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.postUseCase.FinalizeUpload(userID, c.Param("upload_id"), req.Title, req.Description, req.Category, req.Price, req.SubscriberOnly)
+	if err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			c.JSON(uploadErr.Status, uploadErr.Body())
+			return
+		}
+		switch err.Error() {
+		case "upload not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "file has not been uploaded":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "uploaded file size does not match", "price cannot be negative":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to finalize upload: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, post)
+}
+
 // GetPost godoc
 // @Summary      Get post by ID
-// @Description  Get post details by ID and increment view count
+// @Description  Get post details by ID and increment view count. Paid posts the viewer has not purchased are returned locked, without media URLs. Subscriber-only posts are not found for viewers without an active paid subscription.
 // @Tags         posts
 // @Accept       json
 // @Produce      json
@@ -156,21 +285,25 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"id":           post.ID,
-		"creator_id":   post.CreatorID,
-		"title":        post.Title,
-		"description":  post.Description,
-		"type":         post.Type,
-		"media_url":    post.MediaURL,
-		"thumbnail_url": post.ThumbnailURL,
-		"category":     post.Category,
-		"status":       post.Status,
-		"views":        post.Views,
-		"likes_count":  likeCount,
-		"is_liked":     isLiked,
-		"images":       post.Images,
-		"created_at":   post.CreatedAt,
-		"updated_at":   post.UpdatedAt,
+		"id":              post.ID,
+		"creator_id":      post.CreatorID,
+		"title":           post.Title,
+		"description":     post.Description,
+		"type":            post.Type,
+		"media_url":       post.MediaURL,
+		"thumbnail_url":   post.ThumbnailURL,
+		"preview_url":     post.PreviewURL,
+		"category":        post.Category,
+		"price":           post.Price,
+		"is_locked":       post.IsLocked,
+		"subscriber_only": post.SubscriberOnly,
+		"status":          post.Status,
+		"views":           post.Views,
+		"likes_count":     likeCount,
+		"is_liked":        isLiked,
+		"images":          post.Images,
+		"created_at":      post.CreatedAt,
+		"updated_at":      post.UpdatedAt,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -184,16 +317,24 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        category query string false "Filter by category"
+// @Param        limit query int false "Number of posts to return (max 100)"
+// @Param        cursor query string false "next_cursor from the previous page"
 // @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /posts [get]
 func (h *PostHandler) ListPosts(c *gin.Context) {
-	limit := 20
-	offset := 0
+	limit := pageLimit(c)
 	category := c.Query("category")
 
-	posts, err := h.postUseCase.ListPosts(limit, offset, category)
+	userID := c.GetString("user_id")
+
+	posts, nextCursor, err := h.postUseCase.ListPosts(userID, limit, c.Query("cursor"), category)
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to list posts: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
@@ -205,7 +346,7 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 		postsWithLikes[i] = h.formatPostResponse(post, likeCount)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": postsWithLikes, "count": len(postsWithLikes)})
+	c.JSON(http.StatusOK, gin.H{"posts": postsWithLikes, "count": len(postsWithLikes), "next_cursor": nextCursor})
 }
 
 // UpdatePost godoc
@@ -216,7 +357,7 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "Post ID"
-// @Param        request body object true "Update data" SchemaExample({"title":"Updated title","description":"Updated description","category":"fetish"})
+// @Param        request body object true "Update data" SchemaExample({"title":"Updated title","description":"Updated description","category":"fetish","price":100,"subscriber_only":true})
 // @Success      200  {object}  models.Post
 // @Failure      400  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
@@ -228,9 +369,11 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	userID := c.GetString("user_id")
 
 	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Category    string `json:"category"`
+		Title          string `json:"title"`
+		Description    string `json:"description"`
+		Category       string `json:"category"`
+		Price          *int   `json:"price"`
+		SubscriberOnly *bool  `json:"subscriber_only"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -249,12 +392,16 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		category = &req.Category
 	}
 
-	post, err := h.postUseCase.UpdatePost(postID, userID, title, description, category)
+	post, err := h.postUseCase.UpdatePost(postID, userID, title, description, category, req.Price, req.SubscriberOnly)
 	if err != nil {
 		if err.Error() == "you can only update your own posts" {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "price cannot be negative" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to update post: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
 		return
@@ -307,16 +454,23 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        creator_id path string true "Creator ID"
+// @Param        limit query int false "Number of posts to return (max 100)"
+// @Param        cursor query string false "next_cursor from the previous page"
 // @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /posts/creator/{creator_id} [get]
 func (h *PostHandler) GetCreatorPosts(c *gin.Context) {
 	creatorID := c.Param("creator_id")
-	limit := 20
-	offset := 0
+	userID := c.GetString("user_id")
+	limit := pageLimit(c)
 
-	posts, err := h.postUseCase.GetCreatorPosts(creatorID, limit, offset)
+	posts, nextCursor, err := h.postUseCase.GetCreatorPosts(creatorID, userID, limit, c.Query("cursor"))
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to get creator posts: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
@@ -328,7 +482,15 @@ func (h *PostHandler) GetCreatorPosts(c *gin.Context) {
 		postsWithLikes[i] = h.formatPostResponse(post, likeCount)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": postsWithLikes, "count": len(postsWithLikes)})
+	c.JSON(http.StatusOK, gin.H{"posts": postsWithLikes, "count": len(postsWithLikes), "next_cursor": nextCursor})
+}
+
+// pageLimit reads the limit query parameter, defaulting to 20 posts.
+func pageLimit(c *gin.Context) int {
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		return l
+	}
+	return 20
 }
 
 // LikePost godoc
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/upload"
 	"lick-scroll/services/post/internal/entity"
 	"lick-scroll/services/post/internal/usecase"
 
@@ -23,8 +24,24 @@ type MockPostUseCase struct {
 	mock.Mock
 }
 
-func (m *MockPostUseCase) CreatePost(userID string, title, description, postType, category string, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error) {
-	args := m.Called(userID, title, description, postType, category, mediaFile, imageFiles)
+func (m *MockPostUseCase) CreatePost(userID string, title, description, postType, category string, price int, subscriberOnly bool, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error) {
+	args := m.Called(userID, title, description, postType, category, price, subscriberOnly, mediaFile, imageFiles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostUseCase) CreateUpload(userID, filename string, size int64) (*entity.Upload, error) {
+	args := m.Called(userID, filename, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Upload), args.Error(1)
+}
+
+func (m *MockPostUseCase) FinalizeUpload(userID, uploadID, title, description, category string, price int, subscriberOnly bool) (*entity.Post, error) {
+	args := m.Called(userID, uploadID, title, description, category, price, subscriberOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -44,16 +61,16 @@ func (m *MockPostUseCase) GetLikeCount(postID string) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockPostUseCase) ListPosts(limit, offset int, category string) ([]*entity.Post, error) {
-	args := m.Called(limit, offset, category)
+func (m *MockPostUseCase) ListPosts(viewerID string, limit int, cursor, category string) ([]*entity.Post, string, error) {
+	args := m.Called(viewerID, limit, cursor, category)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, "", args.Error(2)
 	}
-	return args.Get(0).([]*entity.Post), args.Error(1)
+	return args.Get(0).([]*entity.Post), args.String(1), args.Error(2)
 }
 
-func (m *MockPostUseCase) UpdatePost(postID, userID string, title, description, category *string) (*entity.Post, error) {
-	args := m.Called(postID, userID, title, description, category)
+func (m *MockPostUseCase) UpdatePost(postID, userID string, title, description, category *string, price *int, subscriberOnly *bool) (*entity.Post, error) {
+	args := m.Called(postID, userID, title, description, category, price, subscriberOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -65,12 +82,12 @@ func (m *MockPostUseCase) DeletePost(postID, userID string) error {
 	return args.Error(0)
 }
 
-func (m *MockPostUseCase) GetCreatorPosts(creatorID string, limit, offset int) ([]*entity.Post, error) {
-	args := m.Called(creatorID, limit, offset)
+func (m *MockPostUseCase) GetCreatorPosts(creatorID, viewerID string, limit int, cursor string) ([]*entity.Post, string, error) {
+	args := m.Called(creatorID, viewerID, limit, cursor)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, "", args.Error(2)
 	}
-	return args.Get(0).([]*entity.Post), args.Error(1)
+	return args.Get(0).([]*entity.Post), args.String(1), args.Error(2)
 }
 
 func (m *MockPostUseCase) LikePost(userID, postID string) (bool, error) {
@@ -134,6 +151,98 @@ func TestLikePost_Like(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestCreatePost_RejectedUpload(t *testing.T) {
+	mockUseCase := new(MockPostUseCase)
+	logger := logger.New()
+	handler := NewPostHandler(mockUseCase, nil, logger)
+
+	router := setupTestRouter()
+	router.POST("/posts", func(c *gin.Context) {
+		c.Set("user_id", "creator-123")
+		handler.CreatePost(c)
+	})
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("title", "Title")
+	writer.WriteField("type", "photo")
+	part, _ := writer.CreateFormFile("images", "photo.jpg")
+	part.Write([]byte("not an image"))
+	writer.Close()
+
+	rejected := &upload.Error{Status: http.StatusUnsupportedMediaType, Code: upload.CodeUnsupportedType, Message: "unsupported file type", Filename: "photo.jpg"}
+	mockUseCase.On("CreatePost", "creator-123", "Title", "", "photo", "", 0, false, (*multipart.FileHeader)(nil), mock.Anything).Return(nil, rejected)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/posts", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, upload.CodeUnsupportedType, response["code"])
+	assert.Equal(t, "photo.jpg", response["file"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCreateUpload_Success(t *testing.T) {
+	mockUseCase := new(MockPostUseCase)
+	logger := logger.New()
+	handler := NewPostHandler(mockUseCase, nil, logger)
+
+	router := setupTestRouter()
+	router.POST("/posts/uploads", func(c *gin.Context) {
+		c.Set("user_id", "creator-123")
+		handler.CreateUpload(c)
+	})
+
+	mockUseCase.On("CreateUpload", "creator-123", "clip.mp4", int64(5000)).Return(&entity.Upload{
+		ID:          "upload-123",
+		UploadURL:   "http://localhost:9000/bucket/posts/creator-123/clip.mp4?X-Amz-Signature=abc",
+		ContentType: "video/mp4",
+		Size:        5000,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/posts/uploads", bytes.NewBufferString(`{"filename":"clip.mp4","size":5000}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "upload-123", response["upload_id"])
+	assert.Equal(t, "PUT", response["method"])
+	assert.Equal(t, map[string]interface{}{"Content-Type": "video/mp4"}, response["headers"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestFinalizeUpload_NotUploaded(t *testing.T) {
+	mockUseCase := new(MockPostUseCase)
+	logger := logger.New()
+	handler := NewPostHandler(mockUseCase, nil, logger)
+
+	router := setupTestRouter()
+	router.POST("/posts/uploads/:upload_id/finalize", func(c *gin.Context) {
+		c.Set("user_id", "creator-123")
+		handler.FinalizeUpload(c)
+	})
+
+	mockUseCase.On("FinalizeUpload", "creator-123", "upload-123", "Title", "", "", 10, false).Return(nil, errors.New("file has not been uploaded"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/posts/uploads/upload-123/finalize", bytes.NewBufferString(`{"title":"Title","price":10}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUpdatePost_Success(t *testing.T) {
 	mockUseCase := new(MockPostUseCase)
 	logger := logger.New()
@@ -157,7 +266,7 @@ func TestUpdatePost_Success(t *testing.T) {
 	}
 
 	title := "New Title"
-	mockUseCase.On("UpdatePost", postID, userID, &title, (*string)(nil), (*string)(nil)).Return(mockPost, nil)
+	mockUseCase.On("UpdatePost", postID, userID, &title, (*string)(nil), (*string)(nil), (*int)(nil), (*bool)(nil)).Return(mockPost, nil)
 	mockUseCase.On("GetLikeCount", postID).Return(int64(0), nil)
 
 	updateJSON := `{"title":"New Title"}`
@@ -183,7 +292,7 @@ func TestUpdatePost_NotFound(t *testing.T) {
 	userID := ""
 
 	title := "New Title"
-	mockUseCase.On("UpdatePost", postID, userID, &title, (*string)(nil), (*string)(nil)).Return(nil, errors.New("post not found"))
+	mockUseCase.On("UpdatePost", postID, userID, &title, (*string)(nil), (*string)(nil), (*int)(nil), (*bool)(nil)).Return(nil, errors.New("post not found"))
 
 	updateJSON := `{"title":"New Title"}`
 	w := httptest.NewRecorder()
@@ -344,6 +453,51 @@ func TestFormatPostResponse_WithImages(t *testing.T) {
 	assert.NotNil(t, response["images"])
 }
 
+func TestFormatPostResponse_LockedPaidPost(t *testing.T) {
+	logger := logger.New()
+	handler := NewPostHandler(nil, nil, logger)
+
+	post := &entity.Post{
+		ID:           "post-123",
+		CreatorID:    "creator-123",
+		Type:         entity.PostTypeVideo,
+		ThumbnailURL: "https://example.com/preview.jpg",
+		Price:        150,
+		IsLocked:     true,
+	}
+
+	response := handler.formatPostResponse(post, 0)
+
+	assert.Equal(t, 150, response["price"])
+	assert.Equal(t, true, response["is_locked"])
+	assert.NotContains(t, response, "media_url")
+	assert.Equal(t, post.ThumbnailURL, response["thumbnail_url"])
+}
+
+func TestUpdatePost_NegativePrice(t *testing.T) {
+	mockUseCase := new(MockPostUseCase)
+	logger := logger.New()
+	handler := NewPostHandler(mockUseCase, nil, logger)
+
+	router := setupTestRouter()
+	router.PUT("/posts/:id", func(c *gin.Context) {
+		c.Set("user_id", "user-123")
+		handler.UpdatePost(c)
+	})
+
+	price := -10
+	mockUseCase.On("UpdatePost", "post-123", "user-123", (*string)(nil), (*string)(nil), (*string)(nil), &price, (*bool)(nil)).Return(nil, errors.New("price cannot be negative"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/posts/post-123", bytes.NewBufferString(`{"price":-10}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestListPosts_Success(t *testing.T) {
 	mockUseCase := new(MockPostUseCase)
 	logger := logger.New()
@@ -369,7 +523,7 @@ func TestListPosts_Success(t *testing.T) {
 		},
 	}
 
-	mockUseCase.On("ListPosts", 20, 0, "").Return(mockPosts, nil)
+	mockUseCase.On("ListPosts", "", 20, "", "").Return(mockPosts, "", nil)
 	mockUseCase.On("GetLikeCount", "post-1").Return(int64(5), nil)
 	mockUseCase.On("GetLikeCount", "post-2").Return(int64(3), nil)
 
@@ -383,7 +537,27 @@ func TestListPosts_Success(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &response)
 	posts := response["posts"].([]interface{})
 	assert.GreaterOrEqual(t, len(posts), 0)
+	assert.Equal(t, "", response["next_cursor"])
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestListPosts_InvalidCursor(t *testing.T) {
+	mockUseCase := new(MockPostUseCase)
+	logger := logger.New()
+	handler := NewPostHandler(mockUseCase, nil, logger)
+
+	router := setupTestRouter()
+	router.GET("/posts", handler.ListPosts)
+
+	mockUseCase.On("ListPosts", "", 20, "garbage", "").Return(nil, "", errors.New("invalid cursor"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/posts?cursor=garbage", nil)
+
+	router.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 	mockUseCase.AssertExpectations(t)
 }
 
@@ -429,12 +603,12 @@ func TestGetCreatorPosts_Success(t *testing.T) {
 		},
 	}
 
-	mockUseCase.On("GetCreatorPosts", creatorID, 20, 0).Return(mockPosts, nil)
+	mockUseCase.On("GetCreatorPosts", creatorID, "", 2, "").Return(mockPosts, "next-page", nil)
 	mockUseCase.On("GetLikeCount", "post-1").Return(int64(5), nil)
 	mockUseCase.On("GetLikeCount", "post-2").Return(int64(3), nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/creators/"+creatorID+"/posts", nil)
+	req, _ := http.NewRequest("GET", "/creators/"+creatorID+"/posts?limit=2", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -443,6 +617,7 @@ func TestGetCreatorPosts_Success(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &response)
 	posts := response["posts"].([]interface{})
 	assert.Equal(t, 2, len(posts))
+	assert.Equal(t, "next-page", response["next_cursor"])
 
 	mockUseCase.AssertExpectations(t)
 }
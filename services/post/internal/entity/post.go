@@ -18,28 +18,38 @@ const (
 )
 
 type Post struct {
-	ID           string      `json:"id"`
-	CreatorID   string      `json:"creator_id"`
-	Title        string      `json:"title"`
-	Description  string      `json:"description"`
-	Type         PostType    `json:"type"`
-	MediaURL     string      `json:"media_url"`
-	ThumbnailURL string      `json:"thumbnail_url"`
-	Category     string      `json:"category"`
-	Status       PostStatus  `json:"status"`
-	Views        int         `json:"views"`
-	Purchases    int         `json:"purchases"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	Images       []PostImage `json:"images,omitempty"`
+	ID             string      `json:"id"`
+	CreatorID      string      `json:"creator_id"`
+	Title          string      `json:"title"`
+	Description    string      `json:"description"`
+	Type           PostType    `json:"type"`
+	MediaURL       string      `json:"media_url"`
+	ThumbnailURL   string      `json:"thumbnail_url"`
+	PreviewURL     string      `json:"preview_url,omitempty"`
+	Category       string      `json:"category"`
+	Price          int         `json:"price"`
+	IsLocked       bool        `json:"is_locked"`
+	SubscriberOnly bool        `json:"subscriber_only"`
+	Status         PostStatus  `json:"status"`
+	Views          int         `json:"views"`
+	Purchases      int         `json:"purchases"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	Images         []PostImage `json:"images,omitempty"`
 }
 
+// PostImage is an uploaded photo. The media worker fills in ThumbnailURL,
+// Variants (resized copies keyed by name) and PreviewURL (a blurred copy)
+// once ProcessingStatus is "processed".
 type PostImage struct {
-	ID           string    `json:"id"`
-	PostID       string    `json:"post_id"`
-	ImageURL     string    `json:"image_url"`
-	ThumbnailURL string    `json:"thumbnail_url"`
-	Order        int       `json:"order"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID               string            `json:"id"`
+	PostID           string            `json:"post_id"`
+	ImageURL         string            `json:"image_url"`
+	ThumbnailURL     string            `json:"thumbnail_url"`
+	PreviewURL       string            `json:"preview_url,omitempty"`
+	Variants         map[string]string `json:"variants,omitempty"`
+	ProcessingStatus string            `json:"processing_status"`
+	Order            int               `json:"order"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
 }
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Upload is a direct-to-S3 upload in progress. The client PUTs the file to
+// UploadURL with the given content type before ExpiresAt, then finalizes
+// the upload to create the post.
+type Upload struct {
+	ID          string    `json:"upload_id"`
+	UploadURL   string    `json:"upload_url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -8,21 +8,24 @@ import (
 )
 
 type PostModel struct {
-	ID           string           `gorm:"type:uuid;primary_key" json:"id"`
-	CreatorID    string           `gorm:"type:uuid;not null;index" json:"creator_id"`
-	Title        string           `gorm:"type:varchar(255);not null" json:"title"`
-	Description  string           `gorm:"type:text" json:"description"`
-	Type         string           `gorm:"type:varchar(20);not null" json:"type"`
-	MediaURL     string           `gorm:"type:varchar(500)" json:"media_url"`
-	ThumbnailURL string           `gorm:"type:varchar(500)" json:"thumbnail_url"`
-	Category     string           `gorm:"type:varchar(100)" json:"category"`
-	Status       string           `gorm:"type:varchar(20);default:'pending'" json:"status"`
-	Views        int              `gorm:"default:0" json:"views"`
-	Purchases    int              `gorm:"default:0" json:"purchases"`
-	CreatedAt    time.Time        `json:"created_at"`
-	UpdatedAt    time.Time        `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt   `gorm:"index" json:"-"`
-	Images       []PostImageModel `gorm:"foreignKey:PostID" json:"images,omitempty"`
+	ID             string           `gorm:"type:uuid;primary_key" json:"id"`
+	CreatorID      string           `gorm:"type:uuid;not null;index" json:"creator_id"`
+	Title          string           `gorm:"type:varchar(255);not null" json:"title"`
+	Description    string           `gorm:"type:text" json:"description"`
+	Type           string           `gorm:"type:varchar(20);not null" json:"type"`
+	MediaURL       string           `gorm:"type:varchar(500)" json:"media_url"`
+	ThumbnailURL   string           `gorm:"type:varchar(500)" json:"thumbnail_url"`
+	PreviewURL     string           `gorm:"type:varchar(500)" json:"preview_url"`
+	Category       string           `gorm:"type:varchar(100)" json:"category"`
+	Price          int              `gorm:"default:0" json:"price"`
+	SubscriberOnly bool             `gorm:"default:false" json:"subscriber_only"`
+	Status         string           `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Views          int              `gorm:"default:0" json:"views"`
+	Purchases      int              `gorm:"default:0" json:"purchases"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt   `gorm:"index" json:"-"`
+	Images         []PostImageModel `gorm:"foreignKey:PostID" json:"images,omitempty"`
 }
 
 func (PostModel) TableName() string {
@@ -37,14 +40,17 @@ func (p *PostModel) BeforeCreate(tx *gorm.DB) error {
 }
 
 type PostImageModel struct {
-	ID           string         `gorm:"type:uuid;primary_key" json:"id"`
-	PostID       string         `gorm:"type:uuid;not null;index" json:"post_id"`
-	ImageURL     string         `gorm:"type:varchar(500);not null" json:"image_url"`
-	ThumbnailURL string         `gorm:"type:varchar(500)" json:"thumbnail_url"`
-	Order        int            `gorm:"default:0;index" json:"order"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               string            `gorm:"type:uuid;primary_key" json:"id"`
+	PostID           string            `gorm:"type:uuid;not null;index" json:"post_id"`
+	ImageURL         string            `gorm:"type:varchar(500);not null" json:"image_url"`
+	ThumbnailURL     string            `gorm:"type:varchar(500)" json:"thumbnail_url"`
+	PreviewURL       string            `gorm:"type:varchar(500)" json:"preview_url"`
+	Variants         map[string]string `gorm:"type:jsonb;serializer:json" json:"variants,omitempty"`
+	ProcessingStatus string            `gorm:"type:varchar(20);not null;default:'pending'" json:"processing_status"`
+	Order            int               `gorm:"default:0;index" json:"order"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt    `gorm:"index" json:"-"`
 }
 
 func (PostImageModel) TableName() string {
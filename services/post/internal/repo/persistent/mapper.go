@@ -11,19 +11,22 @@ func ToPostEntity(m *model.PostModel) *entity.Post {
 	}
 
 	post := &entity.Post{
-		ID:           m.ID,
-		CreatorID:    m.CreatorID,
-		Title:        m.Title,
-		Description:  m.Description,
-		Type:         entity.PostType(m.Type),
-		MediaURL:     m.MediaURL,
-		ThumbnailURL: m.ThumbnailURL,
-		Category:     m.Category,
-		Status:       entity.PostStatus(m.Status),
-		Views:        m.Views,
-		Purchases:    m.Purchases,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:             m.ID,
+		CreatorID:      m.CreatorID,
+		Title:          m.Title,
+		Description:    m.Description,
+		Type:           entity.PostType(m.Type),
+		MediaURL:       m.MediaURL,
+		ThumbnailURL:   m.ThumbnailURL,
+		PreviewURL:     m.PreviewURL,
+		Category:       m.Category,
+		Price:          m.Price,
+		SubscriberOnly: m.SubscriberOnly,
+		Status:         entity.PostStatus(m.Status),
+		Views:          m.Views,
+		Purchases:      m.Purchases,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
 	}
 
 	if len(m.Images) > 0 {
@@ -42,19 +45,22 @@ func ToPostModel(e *entity.Post) *model.PostModel {
 	}
 
 	post := &model.PostModel{
-		ID:           e.ID,
-		CreatorID:    e.CreatorID,
-		Title:        e.Title,
-		Description:  e.Description,
-		Type:         string(e.Type),
-		MediaURL:     e.MediaURL,
-		ThumbnailURL: e.ThumbnailURL,
-		Category:     e.Category,
-		Status:       string(e.Status),
-		Views:        e.Views,
-		Purchases:    e.Purchases,
-		CreatedAt:    e.CreatedAt,
-		UpdatedAt:    e.UpdatedAt,
+		ID:             e.ID,
+		CreatorID:      e.CreatorID,
+		Title:          e.Title,
+		Description:    e.Description,
+		Type:           string(e.Type),
+		MediaURL:       e.MediaURL,
+		ThumbnailURL:   e.ThumbnailURL,
+		PreviewURL:     e.PreviewURL,
+		Category:       e.Category,
+		Price:          e.Price,
+		SubscriberOnly: e.SubscriberOnly,
+		Status:         string(e.Status),
+		Views:          e.Views,
+		Purchases:      e.Purchases,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
 	}
 
 	if len(e.Images) > 0 {
@@ -73,13 +79,16 @@ func ToPostImageEntity(m *model.PostImageModel) entity.PostImage {
 	}
 
 	return entity.PostImage{
-		ID:           m.ID,
-		PostID:       m.PostID,
-		ImageURL:     m.ImageURL,
-		ThumbnailURL: m.ThumbnailURL,
-		Order:        m.Order,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:               m.ID,
+		PostID:           m.PostID,
+		ImageURL:         m.ImageURL,
+		ThumbnailURL:     m.ThumbnailURL,
+		PreviewURL:       m.PreviewURL,
+		Variants:         m.Variants,
+		ProcessingStatus: m.ProcessingStatus,
+		Order:            m.Order,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
 	}
 }
 
@@ -89,13 +98,16 @@ func ToPostImageModel(e *entity.PostImage) *model.PostImageModel {
 	}
 
 	return &model.PostImageModel{
-		ID:           e.ID,
-		PostID:       e.PostID,
-		ImageURL:     e.ImageURL,
-		ThumbnailURL: e.ThumbnailURL,
-		Order:        e.Order,
-		CreatedAt:    e.CreatedAt,
-		UpdatedAt:    e.UpdatedAt,
+		ID:               e.ID,
+		PostID:           e.PostID,
+		ImageURL:         e.ImageURL,
+		ThumbnailURL:     e.ThumbnailURL,
+		PreviewURL:       e.PreviewURL,
+		Variants:         e.Variants,
+		ProcessingStatus: e.ProcessingStatus,
+		Order:            e.Order,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
 	}
 }
 
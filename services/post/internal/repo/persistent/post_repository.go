@@ -1,6 +1,9 @@
 package persistent
 
 import (
+	"time"
+
+	"lick-scroll/pkg/pagination"
 	"lick-scroll/services/post/internal/entity"
 	"lick-scroll/services/post/internal/model"
 
@@ -12,8 +15,8 @@ import (
 type PostRepository interface {
 	Create(post *entity.Post) error
 	GetByID(id string) (*entity.Post, error)
-	GetByCreatorID(creatorID string, limit, offset int) ([]*entity.Post, error)
-	List(limit, offset int, category string, status entity.PostStatus) ([]*entity.Post, error)
+	GetByCreatorID(creatorID, viewerID string, status entity.PostStatus, limit int, after *pagination.Cursor) ([]*entity.Post, error)
+	List(viewerID string, limit int, after *pagination.Cursor, category string, status entity.PostStatus) ([]*entity.Post, error)
 	Update(post *entity.Post) error
 	Delete(id string) error
 	IncrementViews(id string) error
@@ -24,6 +27,8 @@ type PostRepository interface {
 	GetLikedPosts(userID string, limit, offset int) ([]*entity.Post, error)
 	GetLikeCount(postID string) (int64, error)
 	GetSubscription(userID, creatorID string) (*entity.Subscription, error)
+	GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error)
+	HasActiveSubscription(viewerID, creatorID string) (bool, error)
 }
 
 type postRepository struct {
@@ -82,13 +87,16 @@ func (r *postRepository) GetByID(id string) (*entity.Post, error) {
 	return ToPostEntity(&postModel), nil
 }
 
-func (r *postRepository) GetByCreatorID(creatorID string, limit, offset int) ([]*entity.Post, error) {
+func (r *postRepository) GetByCreatorID(creatorID, viewerID string, status entity.PostStatus, limit int, after *pagination.Cursor) ([]*entity.Post, error) {
 	var postModels []model.PostModel
 	query := r.db.Preload("Images", func(db *gorm.DB) *gorm.DB {
 		return db.Order("post_images.order ASC")
-	}).Where("creator_id = ?", creatorID).Order("created_at DESC")
+	}).Scopes(visibleTo(viewerID), pagination.Scope("posts.created_at", "posts.id", after)).Where("creator_id = ?", creatorID)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
 	if limit > 0 {
-		query = query.Limit(limit).Offset(offset)
+		query = query.Limit(limit)
 	}
 	if err := query.Find(&postModels).Error; err != nil {
 		return nil, err
@@ -101,18 +109,18 @@ func (r *postRepository) GetByCreatorID(creatorID string, limit, offset int) ([]
 	return posts, nil
 }
 
-func (r *postRepository) List(limit, offset int, category string, status entity.PostStatus) ([]*entity.Post, error) {
+func (r *postRepository) List(viewerID string, limit int, after *pagination.Cursor, category string, status entity.PostStatus) ([]*entity.Post, error) {
 	var postModels []model.PostModel
 	query := r.db.Preload("Images", func(db *gorm.DB) *gorm.DB {
 		return db.Order("post_images.order ASC")
-	}).Where("status = ?", string(status)).Order("created_at DESC")
+	}).Scopes(visibleTo(viewerID), pagination.Scope("posts.created_at", "posts.id", after)).Where("status = ?", string(status))
 
 	if category != "" {
 		query = query.Where("category = ?", category)
 	}
 
 	if limit > 0 {
-		query = query.Limit(limit).Offset(offset)
+		query = query.Limit(limit)
 	}
 
 	if err := query.Find(&postModels).Error; err != nil {
@@ -126,9 +134,11 @@ func (r *postRepository) List(limit, offset int, category string, status entity.
 	return posts, nil
 }
 
+// Update saves the post's own fields. The thumbnail, preview and images are
+// left to the media worker, which may finish while the post is being edited.
 func (r *postRepository) Update(post *entity.Post) error {
 	postModel := ToPostModel(post)
-	return r.db.Save(postModel).Error
+	return r.db.Omit("thumbnail_url", "preview_url", "Images").Save(postModel).Error
 }
 
 func (r *postRepository) Delete(id string) error {
@@ -181,6 +191,7 @@ func (r *postRepository) GetLikedPosts(userID string, limit, offset int) ([]*ent
 			return db.Order("post_images.order ASC")
 		}).
 		Joins("INNER JOIN likes ON posts.id = likes.post_id").
+		Scopes(visibleTo(userID)).
 		Where("likes.user_id = ? AND likes.deleted_at IS NULL", userID).
 		Order("likes.created_at DESC")
 
@@ -213,3 +224,51 @@ func (r *postRepository) GetSubscription(userID, creatorID string) (*entity.Subs
 	}
 	return ToSubscriptionEntity(&subscriptionModel), nil
 }
+
+func (r *postRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	purchased := make(map[string]bool)
+	if userID == "" || len(postIDs) == 0 {
+		return purchased, nil
+	}
+
+	var ids []string
+	err := r.db.Table("transactions").
+		Where("user_id = ? AND post_id IN ? AND type = ?", userID, postIDs, "purchase").
+		Distinct("post_id").
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		purchased[id] = true
+	}
+	return purchased, nil
+}
+
+func (r *postRepository) HasActiveSubscription(viewerID, creatorID string) (bool, error) {
+	if viewerID == "" {
+		return false, nil
+	}
+
+	var count int64
+	err := r.db.Table("subscriptions").
+		Where("viewer_id = ? AND creator_id = ? AND type = ? AND current_period_end > ? AND deleted_at IS NULL", viewerID, creatorID, "paid", time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// visibleTo hides subscriber-only posts unless the viewer created them or has
+// an active paid subscription to their creator.
+func visibleTo(viewerID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewerID == "" {
+			return db.Where("posts.subscriber_only = ?", false)
+		}
+		return db.Where(`(posts.subscriber_only = ? OR posts.creator_id = ? OR EXISTS (
+			SELECT 1 FROM subscriptions
+			WHERE subscriptions.viewer_id = ? AND subscriptions.creator_id = posts.creator_id
+			AND subscriptions.type = ? AND subscriptions.current_period_end > ? AND subscriptions.deleted_at IS NULL))`,
+			false, viewerID, viewerID, "paid", time.Now())
+	}
+}
@@ -3,13 +3,17 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"time"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/pagination"
 	"lick-scroll/pkg/queue"
 	"lick-scroll/pkg/s3"
+	"lick-scroll/pkg/upload"
 	"lick-scroll/services/post/internal/entity"
 	"lick-scroll/services/post/internal/repo/persistent"
 
@@ -18,22 +22,52 @@ import (
 )
 
 type PostUseCase interface {
-	CreatePost(userID string, title, description, postType, category string, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error)
+	CreatePost(userID string, title, description, postType, category string, price int, subscriberOnly bool, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error)
+	CreateUpload(userID, filename string, size int64) (*entity.Upload, error)
+	FinalizeUpload(userID, uploadID, title, description, category string, price int, subscriberOnly bool) (*entity.Post, error)
 	GetPost(postID, userID string) (*entity.Post, int64, bool, error)
 	GetLikeCount(postID string) (int64, error)
-	ListPosts(limit, offset int, category string) ([]*entity.Post, error)
-	UpdatePost(postID, userID string, title, description, category *string) (*entity.Post, error)
+	ListPosts(viewerID string, limit int, cursor, category string) ([]*entity.Post, string, error)
+	UpdatePost(postID, userID string, title, description, category *string, price *int, subscriberOnly *bool) (*entity.Post, error)
 	DeletePost(postID, userID string) error
-	GetCreatorPosts(creatorID string, limit, offset int) ([]*entity.Post, error)
+	GetCreatorPosts(creatorID, viewerID string, limit int, cursor string) ([]*entity.Post, string, error)
 	LikePost(userID, postID string) (bool, error)
 	IsLiked(userID, postID string) (bool, error)
 	GetLikedPosts(userID string, limit, offset int) ([]*entity.Post, error)
 	IncrementView(postID string) error
 }
 
+const (
+	// uploadURLTTL is how long a presigned upload URL stays valid.
+	uploadURLTTL = 15 * time.Minute
+	// uploadSessionTTL leaves time to finish a slow upload that started just
+	// before the URL expired.
+	uploadSessionTTL = time.Hour
+)
+
+// uploadSession is what is kept in Redis between creating and finalizing a
+// direct upload.
+type uploadSession struct {
+	UserID      string `json:"user_id"`
+	Key         string `json:"key"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Storage is the part of the S3 client the post service uses.
+type Storage interface {
+	UploadFile(key string, reader io.Reader, contentType string) error
+	DeleteFile(key string) error
+	GetPresignedUploadURL(key, contentType string, size int64, duration time.Duration) (string, error)
+	GetFileSize(key string) (int64, error)
+	ObjectReader(key string) io.ReaderAt
+	MediaURL(key string) string
+}
+
 type postUseCase struct {
 	postRepo    persistent.PostRepository
-	s3Client    *s3.Client
+	s3Client    Storage
 	redisClient *redis.Client
 	queueClient *queue.Client
 	logger      *logger.Logger
@@ -41,7 +75,7 @@ type postUseCase struct {
 
 func NewPostUseCase(
 	postRepo persistent.PostRepository,
-	s3Client *s3.Client,
+	s3Client Storage,
 	redisClient *redis.Client,
 	queueClient *queue.Client,
 	logger *logger.Logger,
@@ -55,8 +89,12 @@ func NewPostUseCase(
 	}
 }
 
-func (uc *postUseCase) CreatePost(userID string, title, description, postType, category string, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error) {
-	var mediaURL string
+func (uc *postUseCase) CreatePost(userID string, title, description, postType, category string, price int, subscriberOnly bool, mediaFile *multipart.FileHeader, imageFiles []*multipart.FileHeader) (*entity.Post, error) {
+	if price < 0 {
+		return nil, fmt.Errorf("price cannot be negative")
+	}
+
+	var mediaKey string
 	var postImages []entity.PostImage
 
 	if postType == "video" {
@@ -64,23 +102,22 @@ func (uc *postUseCase) CreatePost(userID string, title, description, postType, c
 			return nil, fmt.Errorf("media file is required for video posts")
 		}
 
+		video, err := upload.Validate(mediaFile, upload.Videos)
+		if err != nil {
+			return nil, err
+		}
+
 		src, err := mediaFile.Open()
 		if err != nil {
 			return nil, fmt.Errorf("failed to open file: %w", err)
 		}
 		defer src.Close()
 
-		fileKey := fmt.Sprintf("posts/%s/%s%s", userID, uuid.New().String(), getFileExtension(mediaFile.Filename))
-		contentType := mediaFile.Header.Get("Content-Type")
-		if contentType == "" {
-			contentType = "video/mp4"
-		}
-
-		uploadedURL, err := uc.s3Client.UploadFile(fileKey, src, contentType)
-		if err != nil {
+		fileKey := fmt.Sprintf("posts/%s/%s%s", userID, uuid.New().String(), video.Extension)
+		if err := uc.s3Client.UploadFile(fileKey, src, video.ContentType); err != nil {
 			return nil, fmt.Errorf("failed to upload file to S3: %w", err)
 		}
-		mediaURL = uploadedURL
+		mediaKey = fileKey
 	} else {
 		if len(imageFiles) == 0 {
 			return nil, fmt.Errorf("at least one image file is required for photo posts")
@@ -90,63 +127,229 @@ func (uc *postUseCase) CreatePost(userID string, title, description, postType, c
 			return nil, fmt.Errorf("maximum 10 images allowed per post")
 		}
 
+		// Validate every image before uploading any of them
+		images := make([]*upload.File, len(imageFiles))
 		for i, file := range imageFiles {
-			src, err := file.Open()
+			image, err := upload.Validate(file, upload.Images)
 			if err != nil {
-				return nil, fmt.Errorf("failed to open file: %w", err)
+				return nil, err
 			}
+			images[i] = image
+		}
 
-			fileKey := fmt.Sprintf("posts/%s/%s%s", userID, uuid.New().String(), getFileExtension(file.Filename))
-			contentType := file.Header.Get("Content-Type")
-			if contentType == "" {
-				contentType = "image/jpeg"
+		for i, file := range imageFiles {
+			src, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open file: %w", err)
 			}
 
-			imageURL, err := uc.s3Client.UploadFile(fileKey, src, contentType)
+			fileKey := fmt.Sprintf("posts/%s/%s%s", userID, uuid.New().String(), images[i].Extension)
+			err = uc.s3Client.UploadFile(fileKey, src, images[i].ContentType)
 			src.Close()
 			if err != nil {
 				return nil, fmt.Errorf("failed to upload file to S3: %w", err)
 			}
 
 			postImages = append(postImages, entity.PostImage{
-				ID:       uuid.New().String(),
-				ImageURL: imageURL,
-				Order:    i,
+				ID:               uuid.New().String(),
+				ImageURL:         fileKey,
+				ProcessingStatus: "pending",
+				Order:            i,
 			})
 		}
 	}
 
 	post := &entity.Post{
-		CreatorID:   userID,
-		Title:       title,
-		Description: description,
-		Type:        entity.PostType(postType),
-		MediaURL:    mediaURL,
-		Category:    category,
-		Status:      entity.StatusPending,
-		Images:      postImages,
+		CreatorID:      userID,
+		Title:          title,
+		Description:    description,
+		Type:           entity.PostType(postType),
+		MediaURL:       mediaKey,
+		Category:       category,
+		Price:          price,
+		SubscriberOnly: subscriberOnly,
+		Status:         entity.StatusPending,
+		Images:         postImages,
 	}
 
 	if err := uc.postRepo.Create(post); err != nil {
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
+	// The post enters the moderation queue; it is added to feeds and
+	// subscribers are notified once a moderator approves it.
 	uc.cachePost(post)
-	uc.addToFeed(post)
 
-	if uc.queueClient != nil {
-		go uc.publishNotification(post)
+	if uc.queueClient != nil && len(post.Images) > 0 {
+		tasks := make([]map[string]interface{}, 0, len(post.Images))
+		for _, image := range post.Images {
+			tasks = append(tasks, map[string]interface{}{
+				"post_id":  post.ID,
+				"image_id": image.ID,
+				"key":      image.ImageURL,
+			})
+		}
+		go func() {
+			for _, task := range tasks {
+				if err := uc.queueClient.PublishMediaTask(task); err != nil {
+					uc.logger.Error("Failed to publish media task: %v (post_id=%s, image_id=%s)", err, post.ID, task["image_id"])
+				}
+			}
+		}()
 	}
 
+	uc.signMedia([]*entity.Post{post})
 	return post, nil
 }
 
+// CreateUpload starts a direct upload of a video to S3, so large files
+// don't pass through the service. The declared name and size are checked
+// now; the content is checked by FinalizeUpload.
+func (uc *postUseCase) CreateUpload(userID, filename string, size int64) (*entity.Upload, error) {
+	video, err := upload.CheckDeclared(filename, size, upload.Videos)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID := uuid.New().String()
+	session := uploadSession{
+		UserID:      userID,
+		Key:         fmt.Sprintf("posts/%s/%s%s", userID, uuid.New().String(), video.Extension),
+		Filename:    filename,
+		ContentType: video.ContentType,
+		Size:        size,
+	}
+
+	uploadURL, err := uc.s3Client.GetPresignedUploadURL(session.Key, session.ContentType, session.Size, uploadURLTTL)
+	if err != nil {
+		uc.logger.Error("Failed to presign upload: %v", err)
+		return nil, fmt.Errorf("failed to create upload")
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload")
+	}
+	if err := uc.redisClient.Set(context.Background(), uploadSessionKey(uploadID), sessionJSON, uploadSessionTTL).Err(); err != nil {
+		uc.logger.Error("Failed to save upload session: %v", err)
+		return nil, fmt.Errorf("failed to create upload")
+	}
+
+	return &entity.Upload{
+		ID:          uploadID,
+		UploadURL:   uploadURL,
+		ContentType: session.ContentType,
+		Size:        session.Size,
+		ExpiresAt:   time.Now().Add(uploadURLTTL),
+	}, nil
+}
+
+// FinalizeUpload checks the uploaded object and creates a video post from
+// it. Objects that fail validation are deleted.
+func (uc *postUseCase) FinalizeUpload(userID, uploadID, title, description, category string, price int, subscriberOnly bool) (*entity.Post, error) {
+	if price < 0 {
+		return nil, fmt.Errorf("price cannot be negative")
+	}
+
+	ctx := context.Background()
+	sessionKey := uploadSessionKey(uploadID)
+	sessionJSON, err := uc.redisClient.Get(ctx, sessionKey).Bytes()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("upload not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	var session uploadSession
+	if err := json.Unmarshal(sessionJSON, &session); err != nil || session.UserID != userID {
+		return nil, fmt.Errorf("upload not found")
+	}
+
+	size, err := uc.s3Client.GetFileSize(session.Key)
+	if errors.Is(err, s3.ErrObjectNotFound) {
+		return nil, fmt.Errorf("file has not been uploaded")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check uploaded file: %w", err)
+	}
+	if size != session.Size {
+		uc.discardUpload(sessionKey, session.Key)
+		return nil, fmt.Errorf("uploaded file size does not match")
+	}
+
+	if _, err := upload.ValidateContent(session.Filename, uc.s3Client.ObjectReader(session.Key), size, upload.Videos); err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			uc.discardUpload(sessionKey, session.Key)
+		}
+		return nil, err
+	}
+
+	// Claim the upload so finalizing it twice can't create two posts
+	claimed, err := uc.redisClient.Del(ctx, sessionKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim upload: %w", err)
+	}
+	if claimed == 0 {
+		return nil, fmt.Errorf("upload not found")
+	}
+
+	post := &entity.Post{
+		CreatorID:      userID,
+		Title:          title,
+		Description:    description,
+		Type:           entity.PostTypeVideo,
+		MediaURL:       session.Key,
+		Category:       category,
+		Price:          price,
+		SubscriberOnly: subscriberOnly,
+		Status:         entity.StatusPending,
+	}
+
+	if err := uc.postRepo.Create(post); err != nil {
+		return nil, fmt.Errorf("failed to create post: %w", err)
+	}
+
+	uc.cachePost(post)
+
+	uc.signMedia([]*entity.Post{post})
+	return post, nil
+}
+
+func (uc *postUseCase) discardUpload(sessionKey, objectKey string) {
+	uc.redisClient.Del(context.Background(), sessionKey)
+	if err := uc.s3Client.DeleteFile(objectKey); err != nil {
+		uc.logger.Error("Failed to delete rejected upload %s: %v", objectKey, err)
+	}
+}
+
+func uploadSessionKey(uploadID string) string {
+	return fmt.Sprintf("post:upload:%s", uploadID)
+}
+
 func (uc *postUseCase) GetPost(postID, userID string) (*entity.Post, int64, bool, error) {
 	post, err := uc.postRepo.GetByID(postID)
 	if err != nil {
 		return nil, 0, false, err
 	}
 
+	if post.Status != entity.StatusApproved && post.CreatorID != userID {
+		return nil, 0, false, fmt.Errorf("post not found")
+	}
+
+	if post.SubscriberOnly && post.CreatorID != userID {
+		subscribed, err := uc.postRepo.HasActiveSubscription(userID, post.CreatorID)
+		if err != nil || !subscribed {
+			return nil, 0, false, fmt.Errorf("post not found")
+		}
+	}
+
+	if err := uc.applyPaywall([]*entity.Post{post}, userID); err != nil {
+		return nil, 0, false, err
+	}
+	uc.signMedia([]*entity.Post{post})
+
 	likeCount, _ := uc.postRepo.GetLikeCount(postID)
 
 	isLiked := false
@@ -157,35 +360,25 @@ func (uc *postUseCase) GetPost(postID, userID string) (*entity.Post, int64, bool
 	return post, likeCount, isLiked, nil
 }
 
-func (uc *postUseCase) ListPosts(limit, offset int, category string) ([]*entity.Post, error) {
-	approvedPosts, err := uc.postRepo.List(limit*2, 0, category, entity.StatusApproved)
+func (uc *postUseCase) ListPosts(viewerID string, limit int, cursor, category string) ([]*entity.Post, string, error) {
+	after, err := pagination.Decode(cursor)
 	if err != nil {
-		return nil, err
+		return nil, "", fmt.Errorf("invalid cursor")
 	}
 
-	pendingPosts, err := uc.postRepo.List(limit*2, 0, category, entity.StatusPending)
+	posts, err := uc.postRepo.List(viewerID, limit, after, category, entity.StatusApproved)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	result := approvedPosts
-	result = append(result, pendingPosts...)
-
-	start := offset
-	end := offset + limit
-	if start > len(result) {
-		result = []*entity.Post{}
-	} else {
-		if end > len(result) {
-			end = len(result)
-		}
-		result = result[start:end]
+	if err := uc.applyPaywall(posts, viewerID); err != nil {
+		return nil, "", err
 	}
-
-	return result, nil
+	uc.signMedia(posts)
+	return posts, nextCursor(posts, limit), nil
 }
 
-func (uc *postUseCase) UpdatePost(postID, userID string, title, description, category *string) (*entity.Post, error) {
+func (uc *postUseCase) UpdatePost(postID, userID string, title, description, category *string, price *int, subscriberOnly *bool) (*entity.Post, error) {
 	post, err := uc.postRepo.GetByID(postID)
 	if err != nil {
 		return nil, err
@@ -204,14 +397,41 @@ func (uc *postUseCase) UpdatePost(postID, userID string, title, description, cat
 	if category != nil {
 		post.Category = *category
 	}
+	if price != nil {
+		if *price < 0 {
+			return nil, fmt.Errorf("price cannot be negative")
+		}
+		post.Price = *price
+	}
+	if subscriberOnly != nil {
+		post.SubscriberOnly = *subscriberOnly
+	}
 
 	if err := uc.postRepo.Update(post); err != nil {
 		return nil, err
 	}
 
+	// The category feed reads price and visibility from the cached copy
+	uc.cachePost(post)
+
+	if uc.queueClient != nil {
+		go func() {
+			event := map[string]interface{}{
+				"post_id":    post.ID,
+				"creator_id": post.CreatorID,
+			}
+			if err := uc.queueClient.PublishEvent(queue.EventPostUpdated, event); err != nil {
+				uc.logger.Error("Failed to publish %s event: %v (post_id=%s)", queue.EventPostUpdated, err, post.ID)
+			}
+		}()
+	}
+
+	uc.signMedia([]*entity.Post{post})
 	return post, nil
 }
 
+// DeletePost soft-deletes the post. Its media stays in S3 for the grace
+// period of cmd/s3gc, which removes it afterwards.
 func (uc *postUseCase) DeletePost(postID, userID string) error {
 	post, err := uc.postRepo.GetByID(postID)
 	if err != nil {
@@ -222,11 +442,57 @@ func (uc *postUseCase) DeletePost(postID, userID string) error {
 		return fmt.Errorf("you can only delete your own posts")
 	}
 
-	return uc.postRepo.Delete(postID)
+	if err := uc.postRepo.Delete(postID); err != nil {
+		return err
+	}
+
+	if uc.queueClient != nil {
+		go func() {
+			event := map[string]interface{}{
+				"post_id":    post.ID,
+				"creator_id": post.CreatorID,
+			}
+			if err := uc.queueClient.PublishEvent(queue.EventPostDeleted, event); err != nil {
+				uc.logger.Error("Failed to publish %s event: %v (post_id=%s)", queue.EventPostDeleted, err, post.ID)
+			}
+		}()
+	}
+
+	return nil
 }
 
-func (uc *postUseCase) GetCreatorPosts(creatorID string, limit, offset int) ([]*entity.Post, error) {
-	return uc.postRepo.GetByCreatorID(creatorID, limit, offset)
+func (uc *postUseCase) GetCreatorPosts(creatorID, viewerID string, limit int, cursor string) ([]*entity.Post, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor")
+	}
+
+	// Creators see their whole catalogue, including posts still in moderation
+	status := entity.StatusApproved
+	if creatorID == viewerID {
+		status = ""
+	}
+
+	posts, err := uc.postRepo.GetByCreatorID(creatorID, viewerID, status, limit, after)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.applyPaywall(posts, viewerID); err != nil {
+		return nil, "", err
+	}
+	uc.signMedia(posts)
+	return posts, nextCursor(posts, limit), nil
+}
+
+// nextCursor points after the last post of a full page; a shorter page is the
+// last one.
+func nextCursor(posts []*entity.Post, limit int) string {
+	if len(posts) == 0 || len(posts) < limit {
+		return ""
+	}
+	last := posts[len(posts)-1]
+	return pagination.Encode(last.CreatedAt, last.ID)
 }
 
 func (uc *postUseCase) LikePost(userID, postID string) (bool, error) {
@@ -253,7 +519,16 @@ func (uc *postUseCase) IsLiked(userID, postID string) (bool, error) {
 }
 
 func (uc *postUseCase) GetLikedPosts(userID string, limit, offset int) ([]*entity.Post, error) {
-	return uc.postRepo.GetLikedPosts(userID, limit, offset)
+	posts, err := uc.postRepo.GetLikedPosts(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.applyPaywall(posts, userID); err != nil {
+		return nil, err
+	}
+	uc.signMedia(posts)
+	return posts, nil
 }
 
 func (uc *postUseCase) IncrementView(postID string) error {
@@ -264,71 +539,93 @@ func (uc *postUseCase) GetLikeCount(postID string) (int64, error) {
 	return uc.postRepo.GetLikeCount(postID)
 }
 
-func (uc *postUseCase) cachePost(post *entity.Post) {
-	ctx := context.Background()
-	postKey := fmt.Sprintf("post:%s", post.ID)
-	postData := map[string]interface{}{
-		"id":          post.ID,
-		"creator_id":  post.CreatorID,
-		"title":       post.Title,
-		"description": post.Description,
-		"type":        string(post.Type),
-		"media_url":   post.MediaURL,
-		"category":    post.Category,
-		"status":      string(post.Status),
+// applyPaywall strips every media key but the blurred previews from paid
+// posts the viewer has not unlocked. Thumbnails are downscaled copies of the
+// original, so they go too. Creators always see their own posts.
+func (uc *postUseCase) applyPaywall(posts []*entity.Post, viewerID string) error {
+	var paidPostIDs []string
+	for _, post := range posts {
+		if post.Price > 0 && post.CreatorID != viewerID {
+			paidPostIDs = append(paidPostIDs, post.ID)
+		}
+	}
+	if len(paidPostIDs) == 0 {
+		return nil
 	}
 
-	if len(post.Images) > 0 {
-		imagesJSON, _ := json.Marshal(post.Images)
-		postData["images"] = string(imagesJSON)
+	purchased, err := uc.postRepo.GetPurchasedPostIDs(viewerID, paidPostIDs)
+	if err != nil {
+		return fmt.Errorf("failed to check purchases: %w", err)
 	}
 
-	for k, v := range postData {
-		uc.redisClient.HSet(ctx, postKey, k, v)
+	for _, post := range posts {
+		if post.Price == 0 || post.CreatorID == viewerID || purchased[post.ID] {
+			continue
+		}
+		post.IsLocked = true
+		post.MediaURL = ""
+		post.ThumbnailURL = ""
+		for i := range post.Images {
+			post.Images[i].ImageURL = ""
+			post.Images[i].ThumbnailURL = ""
+			post.Images[i].Variants = nil
+		}
 	}
-	uc.redisClient.Expire(ctx, postKey, 24*time.Hour)
+	return nil
 }
 
-func (uc *postUseCase) addToFeed(post *entity.Post) {
-	ctx := context.Background()
-	globalFeedKey := "feed:global"
-	uc.redisClient.LPush(ctx, globalFeedKey, post.ID)
-	uc.redisClient.LTrim(ctx, globalFeedKey, 0, 9999)
-	uc.redisClient.Expire(ctx, globalFeedKey, 7*24*time.Hour)
-
-	if post.Category != "" {
-		categoryFeedKey := fmt.Sprintf("feed:global:%s", post.Category)
-		uc.redisClient.LPush(ctx, categoryFeedKey, post.ID)
-		uc.redisClient.LTrim(ctx, categoryFeedKey, 0, 9999)
-		uc.redisClient.Expire(ctx, categoryFeedKey, 7*24*time.Hour)
+// signMedia replaces the object keys of the posts with presigned URLs. It
+// runs after applyPaywall: of a locked post only the blurred previews are
+// signed, and unlocked posts don't need them. Posts have to be cached before
+// signing; the cache keeps the keys.
+func (uc *postUseCase) signMedia(posts []*entity.Post) {
+	for _, post := range posts {
+		if post.IsLocked {
+			post.PreviewURL = uc.s3Client.MediaURL(post.PreviewURL)
+			for i := range post.Images {
+				post.Images[i].PreviewURL = uc.s3Client.MediaURL(post.Images[i].PreviewURL)
+			}
+			continue
+		}
+		post.PreviewURL = ""
+		post.MediaURL = uc.s3Client.MediaURL(post.MediaURL)
+		post.ThumbnailURL = uc.s3Client.MediaURL(post.ThumbnailURL)
+		for i := range post.Images {
+			image := &post.Images[i]
+			image.PreviewURL = ""
+			image.ImageURL = uc.s3Client.MediaURL(image.ImageURL)
+			image.ThumbnailURL = uc.s3Client.MediaURL(image.ThumbnailURL)
+			for name, key := range image.Variants {
+				image.Variants[name] = uc.s3Client.MediaURL(key)
+			}
+		}
 	}
 }
 
-func (uc *postUseCase) publishNotification(post *entity.Post) {
-	task := map[string]interface{}{
-		"type":       "new_post",
-		"post_id":    post.ID,
-		"creator_id": post.CreatorID,
-		"category":   post.Category,
-		"priority":   5,
+func (uc *postUseCase) cachePost(post *entity.Post) {
+	ctx := context.Background()
+	postKey := fmt.Sprintf("post:%s", post.ID)
+	postData := map[string]interface{}{
+		"id":              post.ID,
+		"creator_id":      post.CreatorID,
+		"title":           post.Title,
+		"description":     post.Description,
+		"type":            string(post.Type),
+		"media_url":       post.MediaURL,
+		"preview_url":     post.PreviewURL,
+		"category":        post.Category,
+		"price":           post.Price,
+		"subscriber_only": post.SubscriberOnly,
+		"status":          string(post.Status),
 	}
 
-	uc.logger.Info("[NOTIFICATION QUEUE] Publishing notification task to RabbitMQ: post_id=%s, creator_id=%s", post.ID, post.CreatorID)
-	if err := uc.queueClient.PublishNotificationTask(task); err != nil {
-		uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish notification task to RabbitMQ: %v (post_id=%s, creator_id=%s)", err, post.ID, post.CreatorID)
-	} else {
-		uc.logger.Info("[NOTIFICATION QUEUE] Successfully published notification task to RabbitMQ: post_id=%s, creator_id=%s", post.ID, post.CreatorID)
+	if len(post.Images) > 0 {
+		imagesJSON, _ := json.Marshal(post.Images)
+		postData["images"] = string(imagesJSON)
 	}
-}
 
-func getFileExtension(filename string) string {
-	if len(filename) == 0 {
-		return ""
-	}
-	for i := len(filename) - 1; i >= 0; i-- {
-		if filename[i] == '.' {
-			return filename[i:]
-		}
+	for k, v := range postData {
+		uc.redisClient.HSet(ctx, postKey, k, v)
 	}
-	return ""
+	uc.redisClient.Expire(ctx, postKey, 24*time.Hour)
 }
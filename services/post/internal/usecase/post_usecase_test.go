@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"lick-scroll/services/post/internal/entity"
+	"lick-scroll/services/post/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePostRepository struct {
+	persistent.PostRepository
+	purchased map[string]bool
+}
+
+func (r *fakePostRepository) GetPurchasedPostIDs(userID string, postIDs []string) (map[string]bool, error) {
+	return r.purchased, nil
+}
+
+// fakeStorage signs keys by prefixing them. Methods the tests don't need
+// panic through the nil embedded interface.
+type fakeStorage struct {
+	Storage
+}
+
+func (fakeStorage) MediaURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed.test/" + key
+}
+
+// mediaURLs returns every string in the JSON form of v that points at a
+// stored object.
+func mediaURLs(t *testing.T, v interface{}) []string {
+	t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(t, err)
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+
+	var urls []string
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, field := range v {
+				walk(field)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			if strings.Contains(v, "posts/") {
+				urls = append(urls, v)
+			}
+		}
+	}
+	walk(decoded)
+	return urls
+}
+
+func TestApplyPaywall_LockedPostShowsOnlyBlurredPreviews(t *testing.T) {
+	uc := &postUseCase{
+		postRepo: &fakePostRepository{purchased: map[string]bool{}},
+		s3Client: fakeStorage{},
+	}
+	post := &entity.Post{
+		ID:           "post-1",
+		CreatorID:    "creator-1",
+		Type:         entity.PostTypePhoto,
+		MediaURL:     "posts/creator-1/original.jpg",
+		ThumbnailURL: "posts/creator-1/original_thumbnail.jpg",
+		PreviewURL:   "posts/creator-1/original_preview.jpg",
+		Price:        100,
+		Images: []entity.PostImage{{
+			ID:           "image-1",
+			ImageURL:     "posts/creator-1/original.jpg",
+			ThumbnailURL: "posts/creator-1/original_thumbnail.jpg",
+			PreviewURL:   "posts/creator-1/original_preview.jpg",
+			Variants:     map[string]string{"small": "posts/creator-1/original_small.jpg"},
+		}},
+	}
+
+	require.NoError(t, uc.applyPaywall([]*entity.Post{post}, "viewer-1"))
+	uc.signMedia([]*entity.Post{post})
+
+	assert.True(t, post.IsLocked)
+	assert.ElementsMatch(t, []string{
+		"https://signed.test/posts/creator-1/original_preview.jpg",
+		"https://signed.test/posts/creator-1/original_preview.jpg",
+	}, mediaURLs(t, post))
+}
+
+func TestApplyPaywall_UnlockedPostHasNoPreview(t *testing.T) {
+	uc := &postUseCase{
+		postRepo: &fakePostRepository{purchased: map[string]bool{"post-1": true}},
+		s3Client: fakeStorage{},
+	}
+	post := &entity.Post{
+		ID:         "post-1",
+		CreatorID:  "creator-1",
+		MediaURL:   "posts/creator-1/original.jpg",
+		PreviewURL: "posts/creator-1/original_preview.jpg",
+		Price:      100,
+	}
+
+	require.NoError(t, uc.applyPaywall([]*entity.Post{post}, "viewer-1"))
+	uc.signMedia([]*entity.Post{post})
+
+	assert.False(t, post.IsLocked)
+	assert.Equal(t, "https://signed.test/posts/creator-1/original.jpg", post.MediaURL)
+	assert.Empty(t, post.PreviewURL)
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"lick-scroll/pkg/config"
+	"lick-scroll/pkg/jwt"
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/pkg/s3"
+	searchHTTP "lick-scroll/services/search/internal/controller/http"
+	"lick-scroll/services/search/internal/repo/persistent"
+	"lick-scroll/services/search/internal/usecase"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const searchQueueName = "search_queue"
+
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, s3Client *s3.Client, queueClient *queue.Client) {
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
+
+	// Initialize Repository
+	searchRepo := persistent.NewSearchRepository(db)
+
+	// Initialize UseCase
+	searchUseCase := usecase.NewSearchUseCase(searchRepo, s3Client, log)
+
+	// Initialize HTTP handlers
+	searchHandler := searchHTTP.NewSearchHandler(searchUseCase, log)
+
+	// Setup router
+	r := gin.Default()
+
+	// CORS middleware
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000", "*"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: true,
+		MaxAge:           12 * 3600,
+	}))
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	api := r.Group("/api/v1")
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
+	api.Use(middleware.RateLimitMiddleware(redisClient, 100, time.Minute)) // 100 requests per minute
+
+	{
+		api.GET("/search/posts", searchHandler.SearchPosts)
+		api.GET("/search/creators", searchHandler.SearchCreators)
+	}
+
+	// Keep the search index in sync with posts
+	routingKeys := []string{
+		queue.EventPostCreated,
+		queue.EventPostUpdated,
+		queue.EventPostDeleted,
+	}
+	err := queueClient.ConsumeEvents(searchQueueName, routingKeys, func(routingKey string, event map[string]interface{}) error {
+		switch routingKey {
+		case queue.EventPostCreated, queue.EventPostUpdated:
+			return searchUseCase.HandlePostIndexed(event)
+		case queue.EventPostDeleted:
+			return searchUseCase.HandlePostDeleted(event)
+		default:
+			return fmt.Errorf("unknown event: %s", routingKey)
+		}
+	})
+	if err != nil {
+		log.Error("Failed to start event consumer: %v", err)
+		panic(err)
+	}
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Info("Search service starting on port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Failed to start server: %v", err)
+			panic(err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info("Shutting down search service...")
+
+	// The context is used to inform the server it has 5 seconds to finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Close RabbitMQ connection first so no event is half-processed on shutdown
+	queueClient.Close()
+
+	// Close database connection
+	sqlDB, err := db.DB()
+	if err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Error("Error closing database: %v", err)
+		}
+	}
+
+	// Close Redis connection
+	if err := redisClient.Close(); err != nil {
+		log.Error("Error closing Redis: %v", err)
+	}
+
+	// Shutdown server
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("Server forced to shutdown: %v", err)
+		panic(err)
+	}
+
+	log.Info("Search service exited")
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/search/internal/entity"
+	"lick-scroll/services/search/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchUseCase usecase.SearchUseCase
+	logger        *logger.Logger
+}
+
+func NewSearchHandler(searchUseCase usecase.SearchUseCase, logger *logger.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchUseCase: searchUseCase,
+		logger:        logger,
+	}
+}
+
+// SearchPosts godoc
+// @Summary      Search posts
+// @Description  Full-text search over post titles, descriptions, categories and creator usernames. A query that starts a creator's username also matches their posts. Results are ranked by relevance, then by recency.
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q query string true "Search query; supports quoted phrases, OR and -word"
+// @Param        type query string false "Post type" Enums(photo, video)
+// @Param        category query string false "Category"
+// @Param        from query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param        to query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Param        limit query int false "Number of posts to return (max 100)"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Router       /search/posts [get]
+func (h *SearchHandler) SearchPosts(c *gin.Context) {
+	limit, offset := pageParams(c)
+	filter := entity.PostFilter{
+		Query:    c.Query("q"),
+		Type:     c.Query("type"),
+		Category: c.Query("category"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	var ok bool
+	if filter.From, ok = parseDate(c.Query("from")); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+		return
+	}
+	if filter.To, ok = parseDate(c.Query("to")); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+		return
+	}
+
+	posts, err := h.searchUseCase.SearchPosts(c.GetString("user_id"), filter)
+	if err != nil {
+		switch err.Error() {
+		case "query is required", "query is too long", "invalid post type", "invalid date range":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search posts"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "offset": offset})
+}
+
+// SearchCreators godoc
+// @Summary      Search creators
+// @Description  Find creators whose username starts with the query (case-insensitive, a leading @ is ignored)
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q query string true "Username prefix"
+// @Param        limit query int false "Number of creators to return (max 100)"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Router       /search/creators [get]
+func (h *SearchHandler) SearchCreators(c *gin.Context) {
+	limit, offset := pageParams(c)
+
+	creators, err := h.searchUseCase.SearchCreators(c.Query("q"), limit, offset)
+	if err != nil {
+		switch err.Error() {
+		case "query is required", "query is too long":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search creators"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"creators": creators, "count": len(creators), "offset": offset})
+}
+
+func pageParams(c *gin.Context) (limit, offset int) {
+	limit = 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset
+}
+
+// parseDate accepts RFC 3339 timestamps and plain dates; an empty value
+// leaves the filter unset.
+func parseDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// PlatformUserID is the system account that owns the platform fee wallet. It
+// is never returned as a creator.
+const PlatformUserID = "00000000-0000-0000-0000-000000000001"
+
+// PostFilter narrows a post search. Zero values leave a filter unset.
+type PostFilter struct {
+	Query    string
+	Type     string
+	Category string
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
+
+type PostResult struct {
+	ID              string    `json:"id"`
+	CreatorID       string    `json:"creator_id"`
+	CreatorUsername string    `json:"creator_username"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Type            string    `json:"type"`
+	Category        string    `json:"category"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	PreviewURL      string    `json:"preview_url,omitempty"`
+	Price           int       `json:"price"`
+	CreatedAt       time.Time `json:"created_at"`
+	Rank            float64   `json:"rank"`
+}
+
+type CreatorResult struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
@@ -0,0 +1,133 @@
+package persistent
+
+import (
+	"strings"
+	"time"
+
+	"lick-scroll/services/search/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepository interface {
+	IndexPost(postID string) (bool, error)
+	RemovePost(postID string) error
+	SearchPosts(viewerID string, filter entity.PostFilter) ([]*entity.PostResult, error)
+	SearchCreators(query string, limit, offset int) ([]*entity.CreatorResult, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// IndexPost rebuilds the post's search document from its current row. Posts
+// that are not approved or were deleted are dropped from the index instead;
+// indexed is false for them.
+func (r *searchRepository) IndexPost(postID string) (indexed bool, err error) {
+	result := r.db.Exec(`
+		INSERT INTO post_search_documents (post_id, creator_username, document, updated_at)
+		SELECT posts.id, users.username, post_search_vector(posts.title, posts.description, posts.category, users.username), NOW()
+		FROM posts
+		JOIN users ON users.id = posts.creator_id
+		WHERE posts.id = ? AND posts.status = ? AND posts.deleted_at IS NULL
+		ON CONFLICT (post_id) DO UPDATE SET
+			creator_username = EXCLUDED.creator_username,
+			document = EXCLUDED.document,
+			updated_at = EXCLUDED.updated_at`,
+		postID, "approved")
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+	return false, r.RemovePost(postID)
+}
+
+func (r *searchRepository) RemovePost(postID string) error {
+	return r.db.Exec("DELETE FROM post_search_documents WHERE post_id = ?", postID).Error
+}
+
+// SearchPosts matches the query against the post's words and its creator's
+// username prefix, best matches first. The index can lag behind moderation
+// and subscriptions, so status and visibility are checked on the posts table.
+func (r *searchRepository) SearchPosts(viewerID string, filter entity.PostFilter) ([]*entity.PostResult, error) {
+	prefix := prefixPattern(filter.Query)
+
+	// A username match ranks above any text match
+	query := r.db.Table("post_search_documents").
+		Select(`posts.id, posts.creator_id, post_search_documents.creator_username, posts.title,
+			COALESCE(posts.description, '') AS description, posts.type, COALESCE(posts.category, '') AS category,
+			COALESCE(posts.thumbnail_url, '') AS thumbnail_url, COALESCE(posts.preview_url, '') AS preview_url, COALESCE(posts.price, 0) AS price, posts.created_at,
+			ts_rank_cd(post_search_documents.document, websearch_to_tsquery('simple', ?)) +
+			CASE WHEN LOWER(post_search_documents.creator_username) LIKE ? THEN 1 ELSE 0 END AS rank`,
+			filter.Query, prefix).
+		Joins("JOIN posts ON posts.id = post_search_documents.post_id").
+		Where("(post_search_documents.document @@ websearch_to_tsquery('simple', ?) OR LOWER(post_search_documents.creator_username) LIKE ?)", filter.Query, prefix).
+		Where("posts.deleted_at IS NULL AND posts.status = ?", "approved").
+		Scopes(visibleTo(viewerID))
+
+	if filter.Type != "" {
+		query = query.Where("posts.type = ?", filter.Type)
+	}
+	if filter.Category != "" {
+		query = query.Where("posts.category = ?", filter.Category)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("posts.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("posts.created_at < ?", filter.To)
+	}
+
+	var results []*entity.PostResult
+	err := query.
+		Order("rank DESC, posts.created_at DESC, posts.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&results).Error
+	return results, err
+}
+
+// SearchCreators returns active creators whose username starts with the
+// query, shortest usernames first so exact matches come on top.
+func (r *searchRepository) SearchCreators(query string, limit, offset int) ([]*entity.CreatorResult, error) {
+	var results []*entity.CreatorResult
+	err := r.db.Table("users").
+		Select("id, username, COALESCE(avatar_url, '') AS avatar_url").
+		Where("LOWER(username) LIKE ? AND role = ? AND is_active = ? AND deleted_at IS NULL", prefixPattern(query), "creator", true).
+		Where("id <> ?", entity.PlatformUserID).
+		Order("LENGTH(username), username").
+		Limit(limit).
+		Offset(offset).
+		Scan(&results).Error
+	return results, err
+}
+
+// visibleTo hides subscriber-only posts unless the user created them or has
+// an active paid subscription to their creator.
+func visibleTo(userID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID == "" {
+			return db.Where("posts.subscriber_only = ?", false)
+		}
+		return db.Where(`(posts.subscriber_only = ? OR posts.creator_id = ? OR EXISTS (
+			SELECT 1 FROM subscriptions
+			WHERE subscriptions.viewer_id = ? AND subscriptions.creator_id = posts.creator_id
+			AND subscriptions.type = ? AND subscriptions.current_period_end > ? AND subscriptions.deleted_at IS NULL))`,
+			false, userID, userID, "paid", time.Now())
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// prefixPattern turns a query into a case-insensitive LIKE prefix pattern.
+// A leading @ is dropped so "@name" finds the creator too.
+func prefixPattern(query string) string {
+	query = strings.ToLower(strings.TrimPrefix(query, "@"))
+	return likeEscaper.Replace(query) + "%"
+}
@@ -0,0 +1,13 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixPattern(t *testing.T) {
+	assert.Equal(t, "alice%", prefixPattern("Alice"))
+	assert.Equal(t, "alice%", prefixPattern("@alice"))
+	assert.Equal(t, `50\%\_off\\%`, prefixPattern(`50%_off\`))
+}
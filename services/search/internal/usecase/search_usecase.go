@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/search/internal/entity"
+	"lick-scroll/services/search/internal/repo/persistent"
+)
+
+// maxQueryLength keeps pathological queries away from the full-text parser.
+const maxQueryLength = 200
+
+type SearchUseCase interface {
+	SearchPosts(viewerID string, filter entity.PostFilter) ([]*entity.PostResult, error)
+	SearchCreators(query string, limit, offset int) ([]*entity.CreatorResult, error)
+	HandlePostIndexed(event map[string]interface{}) error
+	HandlePostDeleted(event map[string]interface{}) error
+}
+
+// MediaSigner turns object keys into URLs clients can load; *s3.Client
+// implements it.
+type MediaSigner interface {
+	MediaURL(key string) string
+}
+
+type searchUseCase struct {
+	searchRepo persistent.SearchRepository
+	signer     MediaSigner
+	logger     *logger.Logger
+}
+
+func NewSearchUseCase(searchRepo persistent.SearchRepository, signer MediaSigner, logger *logger.Logger) SearchUseCase {
+	return &searchUseCase{
+		searchRepo: searchRepo,
+		signer:     signer,
+		logger:     logger,
+	}
+}
+
+func (uc *searchUseCase) SearchPosts(viewerID string, filter entity.PostFilter) ([]*entity.PostResult, error) {
+	query, err := normalizeQuery(filter.Query)
+	if err != nil {
+		return nil, err
+	}
+	filter.Query = query
+
+	if filter.Type != "" && filter.Type != "photo" && filter.Type != "video" {
+		return nil, fmt.Errorf("invalid post type")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+
+	posts, err := uc.searchRepo.SearchPosts(viewerID, filter)
+	if err != nil {
+		uc.logger.Error("Failed to search posts: %v", err)
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	// Results only carry a preview. Purchases aren't checked here, so paid
+	// posts of other creators show their blurred one instead of the thumbnail.
+	for _, post := range posts {
+		if post.Price > 0 && post.CreatorID != viewerID {
+			post.ThumbnailURL = ""
+			post.PreviewURL = uc.signer.MediaURL(post.PreviewURL)
+			continue
+		}
+		post.ThumbnailURL = uc.signer.MediaURL(post.ThumbnailURL)
+		post.PreviewURL = ""
+	}
+	return posts, nil
+}
+
+func (uc *searchUseCase) SearchCreators(query string, limit, offset int) ([]*entity.CreatorResult, error) {
+	query, err := normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	creators, err := uc.searchRepo.SearchCreators(query, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to search creators: %v", err)
+		return nil, fmt.Errorf("failed to search creators: %w", err)
+	}
+	return creators, nil
+}
+
+// HandlePostIndexed refreshes the search document of a post that was
+// approved or edited. A post that is no longer approved leaves the index.
+func (uc *searchUseCase) HandlePostIndexed(event map[string]interface{}) error {
+	postID, _ := event["post_id"].(string)
+	if postID == "" {
+		uc.logger.Error("Invalid post event: %+v", event)
+		return nil
+	}
+
+	indexed, err := uc.searchRepo.IndexPost(postID)
+	if err != nil {
+		return fmt.Errorf("failed to index post %s: %w", postID, err)
+	}
+	if !indexed {
+		uc.logger.Info("Post %s is not approved, removed from search index", postID)
+	}
+	return nil
+}
+
+func (uc *searchUseCase) HandlePostDeleted(event map[string]interface{}) error {
+	postID, _ := event["post_id"].(string)
+	if postID == "" {
+		uc.logger.Error("Invalid post deleted event: %+v", event)
+		return nil
+	}
+
+	if err := uc.searchRepo.RemovePost(postID); err != nil {
+		return fmt.Errorf("failed to remove post %s from search index: %w", postID, err)
+	}
+	return nil
+}
+
+func normalizeQuery(query string) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" || query == "@" {
+		return "", fmt.Errorf("query is required")
+	}
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		return "", fmt.Errorf("query is too long")
+	}
+	return query, nil
+}
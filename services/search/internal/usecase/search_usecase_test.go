@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/search/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchRepository records the calls the use case makes.
+type fakeSearchRepository struct {
+	approved map[string]bool
+	indexed  map[string]bool
+	filters  []entity.PostFilter
+	results  []*entity.PostResult
+}
+
+func newFakeSearchRepository(approved ...string) *fakeSearchRepository {
+	r := &fakeSearchRepository{approved: map[string]bool{}, indexed: map[string]bool{}}
+	for _, postID := range approved {
+		r.approved[postID] = true
+	}
+	return r
+}
+
+func (r *fakeSearchRepository) IndexPost(postID string) (bool, error) {
+	if !r.approved[postID] {
+		delete(r.indexed, postID)
+		return false, nil
+	}
+	r.indexed[postID] = true
+	return true, nil
+}
+
+func (r *fakeSearchRepository) RemovePost(postID string) error {
+	delete(r.indexed, postID)
+	return nil
+}
+
+func (r *fakeSearchRepository) SearchPosts(viewerID string, filter entity.PostFilter) ([]*entity.PostResult, error) {
+	r.filters = append(r.filters, filter)
+	return r.results, nil
+}
+
+func (r *fakeSearchRepository) SearchCreators(query string, limit, offset int) ([]*entity.CreatorResult, error) {
+	return nil, nil
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) MediaURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed.test/" + key
+}
+
+func TestSearchPosts_Validation(t *testing.T) {
+	repo := newFakeSearchRepository()
+	uc := NewSearchUseCase(repo, fakeSigner{}, logger.New())
+
+	_, err := uc.SearchPosts("viewer", entity.PostFilter{Query: "   "})
+	assert.EqualError(t, err, "query is required")
+
+	_, err = uc.SearchPosts("viewer", entity.PostFilter{Query: "cats", Type: "audio"})
+	assert.EqualError(t, err, "invalid post type")
+
+	now := time.Now()
+	_, err = uc.SearchPosts("viewer", entity.PostFilter{Query: "cats", From: now, To: now.Add(-time.Hour)})
+	assert.EqualError(t, err, "invalid date range")
+
+	_, err = uc.SearchCreators("@", 20, 0)
+	assert.EqualError(t, err, "query is required")
+
+	_, err = uc.SearchPosts("viewer", entity.PostFilter{Query: "  black \t cats ", Type: "photo", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, repo.filters, 1)
+	assert.Equal(t, "black cats", repo.filters[0].Query)
+}
+
+func TestSearchPosts_SignsThumbnails(t *testing.T) {
+	repo := newFakeSearchRepository()
+	repo.results = []*entity.PostResult{{ID: "post", ThumbnailURL: "posts/creator/photo_thumbnail.jpg"}, {ID: "video"}}
+	uc := NewSearchUseCase(repo, fakeSigner{}, logger.New())
+
+	posts, err := uc.SearchPosts("viewer", entity.PostFilter{Query: "cats"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://signed.test/posts/creator/photo_thumbnail.jpg", posts[0].ThumbnailURL)
+	assert.Empty(t, posts[1].ThumbnailURL)
+}
+
+func TestSearchPosts_PaidPostsShowBlurredPreview(t *testing.T) {
+	repo := newFakeSearchRepository()
+	repo.results = []*entity.PostResult{
+		{ID: "paid", CreatorID: "creator", Price: 100, ThumbnailURL: "posts/creator/paid_thumbnail.jpg", PreviewURL: "posts/creator/paid_preview.jpg"},
+		{ID: "own", CreatorID: "viewer", Price: 100, ThumbnailURL: "posts/viewer/own_thumbnail.jpg", PreviewURL: "posts/viewer/own_preview.jpg"},
+	}
+	uc := NewSearchUseCase(repo, fakeSigner{}, logger.New())
+
+	posts, err := uc.SearchPosts("viewer", entity.PostFilter{Query: "cats"})
+	require.NoError(t, err)
+	assert.Empty(t, posts[0].ThumbnailURL)
+	assert.Equal(t, "https://signed.test/posts/creator/paid_preview.jpg", posts[0].PreviewURL)
+	assert.Equal(t, "https://signed.test/posts/viewer/own_thumbnail.jpg", posts[1].ThumbnailURL)
+	assert.Empty(t, posts[1].PreviewURL)
+}
+
+func TestHandlePostEvents(t *testing.T) {
+	repo := newFakeSearchRepository("approved")
+	uc := NewSearchUseCase(repo, fakeSigner{}, logger.New())
+
+	require.NoError(t, uc.HandlePostIndexed(map[string]interface{}{"post_id": "approved"}))
+	require.NoError(t, uc.HandlePostIndexed(map[string]interface{}{"post_id": "pending"}))
+	assert.Equal(t, map[string]bool{"approved": true}, repo.indexed)
+
+	// An approved post that is later hidden leaves the index on its next update
+	repo.approved["approved"] = false
+	require.NoError(t, uc.HandlePostIndexed(map[string]interface{}{"post_id": "approved"}))
+	assert.Empty(t, repo.indexed)
+
+	repo.approved["approved"] = true
+	require.NoError(t, uc.HandlePostIndexed(map[string]interface{}{"post_id": "approved"}))
+	require.NoError(t, uc.HandlePostDeleted(map[string]interface{}{"post_id": "approved"}))
+	assert.Empty(t, repo.indexed)
+
+	// Malformed events are dropped rather than requeued forever
+	assert.NoError(t, uc.HandlePostIndexed(map[string]interface{}{}))
+	assert.NoError(t, uc.HandlePostDeleted(map[string]interface{}{}))
+}
@@ -5,6 +5,7 @@ import (
 	"lick-scroll/pkg/config"
 	"lick-scroll/pkg/database"
 	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
 	walletApp "lick-scroll/services/wallet/internal/app"
 
 	"github.com/gin-gonic/gin"
@@ -29,11 +30,6 @@ func main() {
 		panic(err)
 	}
 
-	// Validate JWT_SECRET for services that use JWT
-	if cfg.JWTSecret == "your-secret-key-change-in-production" || cfg.JWTSecret == "" {
-		panic("JWT_SECRET must be set in environment variables")
-	}
-
 	log := logger.New()
 	db, err := database.NewPostgresDB(cfg)
 	if err != nil {
@@ -47,5 +43,11 @@ func main() {
 		panic(err)
 	}
 
-	walletApp.Run(cfg, log, db, redisClient)
+	queueClient, err := queue.NewRabbitMQClient(cfg, log)
+	if err != nil {
+		log.Error("Failed to connect to RabbitMQ: %v (continuing without queue)", err)
+		queueClient = nil // Allow service to start without RabbitMQ
+	}
+
+	walletApp.Run(cfg, log, db, redisClient, queueClient)
 }
@@ -12,8 +12,11 @@ import (
 	"lick-scroll/pkg/jwt"
 	"lick-scroll/pkg/logger"
 	"lick-scroll/pkg/middleware"
+	"lick-scroll/pkg/models"
+	"lick-scroll/pkg/queue"
 	walletHTTP "lick-scroll/services/wallet/internal/controller/http"
 	"lick-scroll/services/wallet/internal/repo/persistent"
+	"lick-scroll/services/wallet/internal/repo/webapi"
 	"lick-scroll/services/wallet/internal/usecase"
 
 	"github.com/gin-contrib/cors"
@@ -26,17 +29,34 @@ import (
 	_ "lick-scroll/services/wallet/docs" // Swagger docs
 )
 
-func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client) {
-	jwtService := jwt.NewService(cfg.JWTSecret)
+func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis.Client, queueClient *queue.Client) {
+	jwtService := jwt.NewVerifier(jwt.NewRemoteKeySet(cfg.JWKSURL))
+	denylist := jwt.NewDenylist(redisClient)
 
 	// Initialize repositories
 	walletRepo := persistent.NewWalletRepository(db)
+	idempotencyRepo := persistent.NewIdempotencyRepository(db)
+	subscriptionRepo := persistent.NewSubscriptionRepository(db)
+	payoutRepo := persistent.NewPayoutRepository(db)
+	feeRepo := persistent.NewFeeRepository(db)
+
+	// Payouts are only simulated until a real payment processor is integrated
+	payoutProvider := webapi.NewLocalPayoutProvider(log)
 
 	// Initialize UseCase
-	walletUseCase := usecase.NewWalletUseCase(walletRepo, redisClient, log)
+	feeUseCase := usecase.NewFeeUseCase(feeRepo, cfg.PlatformFeePercent, log)
+	walletUseCase := usecase.NewWalletUseCase(walletRepo, subscriptionRepo, feeUseCase, log)
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(idempotencyRepo, log)
+	subscriptionUseCase := usecase.NewSubscriptionUseCase(subscriptionRepo, feeUseCase, redisClient, log)
+	refundUseCase := usecase.NewRefundUseCase(walletRepo, queueClient, log)
+	payoutUseCase := usecase.NewPayoutUseCase(payoutRepo, payoutProvider, log)
 
 	// Initialize HTTP handlers
-	walletHandler := walletHTTP.NewWalletHandler(walletUseCase, log)
+	walletHandler := walletHTTP.NewWalletHandler(walletUseCase, idempotencyUseCase, log)
+	subscriptionHandler := walletHTTP.NewSubscriptionHandler(subscriptionUseCase, log)
+	refundHandler := walletHTTP.NewRefundHandler(refundUseCase, log)
+	payoutHandler := walletHTTP.NewPayoutHandler(payoutUseCase, log)
+	feeHandler := walletHTTP.NewFeeHandler(feeUseCase, log)
 
 	// Setup router
 	r := gin.Default()
@@ -45,8 +65,8 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000", "*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
 	}))
@@ -60,16 +80,37 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := r.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(jwtService))
+	api.Use(middleware.AuthMiddleware(jwtService, denylist))
 	api.Use(middleware.RateLimitMiddleware(redisClient, 100, time.Minute))
 
+	creatorOnly := middleware.RequireRole(models.RoleCreator)
+	moderatorOnly := middleware.RequireRole(models.RoleModerator)
+	// Moving money out of the wallet and moderating require a session opened
+	// with a second factor
+	requireMFA := middleware.RequireMFA()
+
 	{
 		api.GET("/wallet", walletHandler.GetWallet)
 		api.POST("/wallet/topup", walletHandler.TopUp)
 		api.POST("/wallet/donate/:post_id", walletHandler.DonateToPost)
+		api.POST("/wallet/purchase/:post_id", walletHandler.PurchasePost)
 		api.GET("/wallet/transactions", walletHandler.GetTransactions)
+		api.POST("/wallet/transactions/:id/refund", moderatorOnly, requireMFA, refundHandler.RefundTransaction)
+		api.POST("/wallet/subscriptions/:tier_id", subscriptionHandler.SubscribeToTier)
+		api.POST("/wallet/payouts", creatorOnly, requireMFA, payoutHandler.RequestPayout)
+		api.GET("/wallet/payouts", payoutHandler.GetPayouts)
+		api.GET("/wallet/payouts/pending", moderatorOnly, requireMFA, payoutHandler.GetPendingPayouts)
+		api.POST("/wallet/payouts/:id/approve", moderatorOnly, requireMFA, payoutHandler.ApprovePayout)
+		api.POST("/wallet/payouts/:id/reject", moderatorOnly, requireMFA, payoutHandler.RejectPayout)
+		api.GET("/wallet/fees/creators/:creator_id", moderatorOnly, requireMFA, feeHandler.GetCreatorFee)
+		api.PUT("/wallet/fees/creators/:creator_id", moderatorOnly, requireMFA, feeHandler.SetCreatorFee)
+		api.DELETE("/wallet/fees/creators/:creator_id", moderatorOnly, requireMFA, feeHandler.ClearCreatorFee)
 	}
 
+	// Charge paid subscriptions at the end of each period
+	renewalCtx, stopRenewals := context.WithCancel(context.Background())
+	go runSubscriptionRenewals(renewalCtx, subscriptionUseCase, log)
+
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -90,6 +131,7 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info("Shutting down wallet service...")
+	stopRenewals()
 
 	// The context is used to inform the server it has 5 seconds to finish
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -108,6 +150,11 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 		log.Error("Error closing Redis: %v", err)
 	}
 
+	// Close RabbitMQ connection
+	if queueClient != nil {
+		queueClient.Close()
+	}
+
 	// Shutdown server
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Error("Server forced to shutdown: %v", err)
@@ -116,3 +163,27 @@ func Run(cfg *config.Config, log *logger.Logger, db *gorm.DB, redisClient *redis
 
 	log.Info("Wallet service exited")
 }
+
+// subscriptionRenewalInterval is how often due subscriptions are charged. A
+// subscription is renewed at most this long after its period ends.
+const subscriptionRenewalInterval = 15 * time.Minute
+
+func runSubscriptionRenewals(ctx context.Context, subscriptionUseCase usecase.SubscriptionUseCase, log *logger.Logger) {
+	ticker := time.NewTicker(subscriptionRenewalInterval)
+	defer ticker.Stop()
+
+	for {
+		renewed, lapsed, err := subscriptionUseCase.RenewDueSubscriptions(time.Now())
+		if err != nil {
+			log.Error("Subscription renewal run failed: %v", err)
+		} else if renewed > 0 || lapsed > 0 {
+			log.Info("Subscription renewal run: %d renewed, %d lapsed", renewed, lapsed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeeHandler struct {
+	feeUseCase usecase.FeeUseCase
+	logger     *logger.Logger
+}
+
+func NewFeeHandler(feeUseCase usecase.FeeUseCase, logger *logger.Logger) *FeeHandler {
+	return &FeeHandler{
+		feeUseCase: feeUseCase,
+		logger:     logger,
+	}
+}
+
+type CreatorFeeRequest struct {
+	FeePercent *int `json:"fee_percent" binding:"required"`
+}
+
+// GetCreatorFee godoc
+// @Summary      Get creator fee
+// @Description  Get the platform fee percentage applied to a creator's earnings (moderators only). overridden is false when the platform default applies.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        creator_id path string true "Creator ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Router       /wallet/fees/creators/{creator_id} [get]
+func (h *FeeHandler) GetCreatorFee(c *gin.Context) {
+	creatorID := c.Param("creator_id")
+	percent, overridden, err := h.feeUseCase.GetCreatorFee(creatorID)
+	if err != nil {
+		h.logger.Error("Failed to get creator fee: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"creator_id":  creatorID,
+		"fee_percent": percent,
+		"overridden":  overridden,
+	})
+}
+
+// SetCreatorFee godoc
+// @Summary      Override creator fee
+// @Description  Set a per-creator platform fee percentage that replaces the platform default (moderators only)
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        creator_id path string true "Creator ID"
+// @Param        request body CreatorFeeRequest true "Fee percentage (0-100)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /wallet/fees/creators/{creator_id} [put]
+func (h *FeeHandler) SetCreatorFee(c *gin.Context) {
+	var req CreatorFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	creatorID := c.Param("creator_id")
+	if err := h.feeUseCase.SetCreatorFee(creatorID, *req.FeePercent); err != nil {
+		switch err.Error() {
+		case "fee percent must be between 0 and 100":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "creator not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to set creator fee: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"creator_id":  creatorID,
+		"fee_percent": *req.FeePercent,
+		"overridden":  true,
+	})
+}
+
+// ClearCreatorFee godoc
+// @Summary      Remove creator fee override
+// @Description  Remove a creator's fee override so the platform default applies again (moderators only)
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        creator_id path string true "Creator ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Router       /wallet/fees/creators/{creator_id} [delete]
+func (h *FeeHandler) ClearCreatorFee(c *gin.Context) {
+	if err := h.feeUseCase.ClearCreatorFee(c.Param("creator_id")); err != nil {
+		h.logger.Error("Failed to clear creator fee: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Creator fee override removed"})
+}
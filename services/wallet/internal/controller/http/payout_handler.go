@@ -0,0 +1,211 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayoutHandler struct {
+	payoutUseCase usecase.PayoutUseCase
+	logger        *logger.Logger
+}
+
+func NewPayoutHandler(payoutUseCase usecase.PayoutUseCase, logger *logger.Logger) *PayoutHandler {
+	return &PayoutHandler{
+		payoutUseCase: payoutUseCase,
+		logger:        logger,
+	}
+}
+
+type PayoutRequest struct {
+	Amount int `json:"amount" binding:"required,min=1"`
+}
+
+type RejectPayoutRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// RequestPayout godoc
+// @Summary      Request a payout
+// @Description  Withdraw part of the creator's balance (creators only). The amount is debited right away and held until a moderator approves or rejects the payout.
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body PayoutRequest true "Payout amount"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Router       /wallet/payouts [post]
+func (h *PayoutHandler) RequestPayout(c *gin.Context) {
+	var req PayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payout, err := h.payoutUseCase.RequestPayout(c.GetString("user_id"), req.Amount)
+	if err != nil {
+		switch err.Error() {
+		case "amount must be positive", "insufficient balance":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to request payout: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Payout requested successfully",
+		"payout":  payout,
+	})
+}
+
+// GetPayouts godoc
+// @Summary      Get my payouts
+// @Description  Get the authenticated creator's payout requests, newest first
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}  map[string]interface{}
+// @Router       /wallet/payouts [get]
+func (h *PayoutHandler) GetPayouts(c *gin.Context) {
+	limit, offset := payoutPagination(c)
+
+	payouts, err := h.payoutUseCase.GetPayouts(c.GetString("user_id"), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get payouts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payouts": payouts, "count": len(payouts)})
+}
+
+// GetPendingPayouts godoc
+// @Summary      Get pending payouts
+// @Description  Get payouts waiting for review, oldest first (moderators only)
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Router       /wallet/payouts/pending [get]
+func (h *PayoutHandler) GetPendingPayouts(c *gin.Context) {
+	limit, offset := payoutPagination(c)
+
+	payouts, err := h.payoutUseCase.GetPendingPayouts(limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get pending payouts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payouts": payouts, "count": len(payouts)})
+}
+
+// ApprovePayout godoc
+// @Summary      Approve a payout
+// @Description  Approve a pending payout and send it through the payout provider (moderators only). If the provider fails, the payout is marked failed and the funds are returned to the creator.
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Payout ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      502  {object}  map[string]string
+// @Router       /wallet/payouts/{id}/approve [post]
+func (h *PayoutHandler) ApprovePayout(c *gin.Context) {
+	payout, err := h.payoutUseCase.ApprovePayout(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		h.respondPayoutError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Payout completed",
+		"payout":  payout,
+	})
+}
+
+// RejectPayout godoc
+// @Summary      Reject a payout
+// @Description  Reject a pending payout and return the held funds to the creator's balance (moderators only)
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Payout ID"
+// @Param        request body RejectPayoutRequest true "Rejection reason"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /wallet/payouts/{id}/reject [post]
+func (h *PayoutHandler) RejectPayout(c *gin.Context) {
+	var req RejectPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payout, err := h.payoutUseCase.RejectPayout(c.GetString("user_id"), c.Param("id"), req.Reason)
+	if err != nil {
+		h.respondPayoutError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Payout rejected",
+		"payout":  payout,
+	})
+}
+
+func (h *PayoutHandler) respondPayoutError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "payout not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "payout is not pending":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "payout provider failed":
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to review payout: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func payoutPagination(c *gin.Context) (int, int) {
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	return limit, offset
+}
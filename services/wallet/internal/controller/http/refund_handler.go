@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefundHandler struct {
+	refundUseCase usecase.RefundUseCase
+	logger        *logger.Logger
+}
+
+func NewRefundHandler(refundUseCase usecase.RefundUseCase, logger *logger.Logger) *RefundHandler {
+	return &RefundHandler{
+		refundUseCase: refundUseCase,
+		logger:        logger,
+	}
+}
+
+// RefundTransaction godoc
+// @Summary      Refund a transaction
+// @Description  Reverse a donation (moderators only). The creator's earning is taken back and the viewer's balance restored as linked refund transactions; both parties are notified. A transaction can only be refunded once.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Transaction ID (either side of the donation)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /wallet/transactions/{id}/refund [post]
+func (h *RefundHandler) RefundTransaction(c *gin.Context) {
+	refunds, err := h.refundUseCase.RefundTransaction(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "transaction already refunded", "insufficient balance to reverse transaction":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "transaction cannot be refunded":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to refund transaction: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Transaction refunded successfully",
+		"transactions": refunds,
+	})
+}
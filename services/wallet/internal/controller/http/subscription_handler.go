@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	subscriptionUseCase usecase.SubscriptionUseCase
+	logger              *logger.Logger
+}
+
+func NewSubscriptionHandler(subscriptionUseCase usecase.SubscriptionUseCase, logger *logger.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionUseCase: subscriptionUseCase,
+		logger:              logger,
+	}
+}
+
+// SubscribeToTier godoc
+// @Summary      Subscribe to a paid tier
+// @Description  Subscribe to a creator's paid tier. The tier price is debited from the viewer's wallet now and again every month until the subscription is cancelled or lapses for lack of funds.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        tier_id path string true "Subscription tier ID"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /wallet/subscriptions/{tier_id} [post]
+func (h *SubscriptionHandler) SubscribeToTier(c *gin.Context) {
+	userID := c.GetString("user_id")
+	tierID := c.Param("tier_id")
+
+	subscription, err := h.subscriptionUseCase.SubscribeToTier(userID, tierID)
+	if err != nil {
+		switch err.Error() {
+		case "subscription tier not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "already subscribed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "cannot subscribe to yourself", "insufficient balance":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to subscribe to tier: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Subscribed successfully",
+		"subscription": subscription,
+	})
+}
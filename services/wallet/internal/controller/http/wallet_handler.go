@@ -1,27 +1,36 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
 	"lick-scroll/services/wallet/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
 type WalletHandler struct {
-	walletUseCase usecase.WalletUseCase
-	logger        *logger.Logger
+	walletUseCase      usecase.WalletUseCase
+	idempotencyUseCase usecase.IdempotencyUseCase
+	logger             *logger.Logger
 }
 
-func NewWalletHandler(walletUseCase usecase.WalletUseCase, logger *logger.Logger) *WalletHandler {
+func NewWalletHandler(walletUseCase usecase.WalletUseCase, idempotencyUseCase usecase.IdempotencyUseCase, logger *logger.Logger) *WalletHandler {
 	return &WalletHandler{
-		walletUseCase: walletUseCase,
-		logger:        logger,
+		walletUseCase:      walletUseCase,
+		idempotencyUseCase: idempotencyUseCase,
+		logger:             logger,
 	}
 }
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type TopUpRequest struct {
 	Amount int `json:"amount" binding:"required,min=1"`
 }
@@ -50,13 +59,16 @@ func (h *WalletHandler) GetWallet(c *gin.Context) {
 
 // TopUp godoc
 // @Summary      Top up wallet
-// @Description  Add funds to user wallet
+// @Description  Add funds to user wallet. Send an Idempotency-Key header to make retries safe: a repeated request with the same key within 24 hours returns the original response instead of charging again.
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        Idempotency-Key header string false "Unique key for safely retrying the request"
 // @Param        request body TopUpRequest true "Top up amount"
 // @Success      200  {object}  models.Wallet
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
 // @Router       /wallet/topup [post]
 func (h *WalletHandler) TopUp(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -67,14 +79,20 @@ func (h *WalletHandler) TopUp(c *gin.Context) {
 		return
 	}
 
-	wallet, err := h.walletUseCase.TopUp(userID, req.Amount)
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key != "" && h.replayIdempotentResponse(c, userID, key, req) {
+		return
+	}
+
+	wallet, transaction, err := h.walletUseCase.TopUp(userID, req.Amount)
 	if err != nil {
+		h.releaseIdempotencyKey(userID, key)
 		h.logger.Error("Failed to top up wallet: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, wallet)
+	h.respondIdempotent(c, userID, key, transaction, http.StatusOK, wallet)
 }
 
 type DonateRequest struct {
@@ -83,15 +101,18 @@ type DonateRequest struct {
 
 // DonateToPost godoc
 // @Summary      Donate to post creator
-// @Description  Donate to the creator of a post using wallet balance
+// @Description  Donate to the creator of a post using wallet balance. Send an Idempotency-Key header to make retries safe: a repeated request with the same key within 24 hours returns the original response instead of donating again.
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        Idempotency-Key header string false "Unique key for safely retrying the request"
 // @Param        post_id path string true "Post ID"
 // @Param        request body DonateRequest true "Donation amount"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
 // @Router       /wallet/donate/{post_id} [post]
 func (h *WalletHandler) DonateToPost(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -103,8 +124,14 @@ func (h *WalletHandler) DonateToPost(c *gin.Context) {
 		return
 	}
 
-	wallet, err := h.walletUseCase.DonateToPost(userID, postID, req.Amount)
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key != "" && h.replayIdempotentResponse(c, userID, key, req) {
+		return
+	}
+
+	wallet, transaction, err := h.walletUseCase.DonateToPost(userID, postID, req.Amount)
 	if err != nil {
+		h.releaseIdempotencyKey(userID, key)
 		if err.Error() == "post not found" || err.Error() == "cannot donate to your own post" || err.Error() == "insufficient balance" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
@@ -114,13 +141,53 @@ func (h *WalletHandler) DonateToPost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	h.respondIdempotent(c, userID, key, transaction, http.StatusOK, gin.H{
 		"message": "Donation sent successfully",
 		"wallet":  wallet,
 		"amount":  req.Amount,
 	})
 }
 
+// PurchasePost godoc
+// @Summary      Purchase paid post
+// @Description  Unlock a paid post. The post price is debited from the buyer's wallet and credited to the creator.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        post_id path string true "Post ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /wallet/purchase/{post_id} [post]
+func (h *WalletHandler) PurchasePost(c *gin.Context) {
+	userID := c.GetString("user_id")
+	postID := c.Param("post_id")
+
+	wallet, err := h.walletUseCase.PurchasePost(userID, postID)
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "post already purchased":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "cannot purchase your own post", "post is not for sale", "insufficient balance":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to purchase post: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post unlocked successfully",
+		"wallet":  wallet,
+		"post_id": postID,
+	})
+}
+
 // GetTransactions godoc
 // @Summary      Get transactions
 // @Description  Get transaction history for the authenticated user
@@ -158,3 +225,70 @@ func (h *WalletHandler) GetTransactions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"transactions": transactions, "count": len(transactions)})
 }
+
+// replayIdempotentResponse reserves the Idempotency-Key for this request. It
+// returns true when the response has already been written, either because an
+// earlier request with the same key completed or because the key can't be used.
+func (h *WalletHandler) replayIdempotentResponse(c *gin.Context, userID, key string, req interface{}) bool {
+	record, err := h.idempotencyUseCase.Begin(userID, key, idempotencyRequestHash(c, req))
+	if err != nil {
+		switch err.Error() {
+		case "idempotency key is too long":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "idempotency key was used for a different request":
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case "request with this idempotency key is still in progress":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to check idempotency key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return true
+	}
+
+	if record == nil {
+		return false
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(record.StatusCode, "application/json; charset=utf-8", []byte(record.ResponseBody))
+	return true
+}
+
+// respondIdempotent writes the response and, when the request carried an
+// Idempotency-Key, stores it together with the transaction for later replays.
+func (h *WalletHandler) respondIdempotent(c *gin.Context, userID, key string, transaction *entity.Transaction, statusCode int, body interface{}) {
+	if key == "" {
+		c.JSON(statusCode, body)
+		return
+	}
+
+	responseBody, err := json.Marshal(body)
+	if err != nil {
+		h.releaseIdempotencyKey(userID, key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactionID := ""
+	if transaction != nil {
+		transactionID = transaction.ID
+	}
+	if err := h.idempotencyUseCase.Complete(userID, key, transactionID, statusCode, responseBody); err != nil {
+		h.logger.Error("Failed to store response for idempotency key %s: %v", key, err)
+	}
+
+	c.Data(statusCode, "application/json; charset=utf-8", responseBody)
+}
+
+func (h *WalletHandler) releaseIdempotencyKey(userID, key string) {
+	if key != "" {
+		h.idempotencyUseCase.Release(userID, key)
+	}
+}
+
+func idempotencyRequestHash(c *gin.Context, req interface{}) string {
+	body, _ := json.Marshal(req)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s %s %s", c.Request.Method, c.Request.URL.Path, body)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package entity
+
+// PlatformUserID owns the platform wallet that collects fees. The account is
+// created by the add_platform_fees migration and can't log in.
+const PlatformUserID = "00000000-0000-0000-0000-000000000001"
+
+// FeeSplit is how a creator earning is divided between the creator and the
+// platform.
+type FeeSplit struct {
+	Gross      int
+	Net        int
+	Fee        int
+	FeePercent int
+}
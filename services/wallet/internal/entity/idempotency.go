@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a money-moving request so that
+// retries carrying the same Idempotency-Key get the original response back.
+type IdempotencyRecord struct {
+	ID            string
+	UserID        string
+	Key           string
+	RequestHash   string
+	TransactionID string
+	StatusCode    int
+	ResponseBody  string
+	CreatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.CompletedAt != nil
+}
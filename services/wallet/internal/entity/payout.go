@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending    PayoutStatus = "pending"
+	PayoutStatusProcessing PayoutStatus = "processing"
+	PayoutStatusCompleted  PayoutStatus = "completed"
+	PayoutStatusRejected   PayoutStatus = "rejected"
+	PayoutStatusFailed     PayoutStatus = "failed"
+)
+
+// Payout is a creator's withdrawal request. The amount leaves the spendable
+// balance as soon as it is requested and stays held until the payout either
+// completes or is rejected/failed, which returns it to the balance.
+type Payout struct {
+	ID                string       `json:"id"`
+	CreatorID         string       `json:"creator_id"`
+	Amount            int          `json:"amount"`
+	Status            PayoutStatus `json:"status"`
+	ReviewerID        string       `json:"reviewer_id,omitempty"`
+	Reason            string       `json:"reason,omitempty"`
+	ProviderReference string       `json:"provider_reference,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	CompletedAt       *time.Time   `json:"completed_at,omitempty"`
+}
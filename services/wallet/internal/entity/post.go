@@ -0,0 +1,9 @@
+package entity
+
+type Post struct {
+	ID             string
+	CreatorID      string
+	Price          int
+	Status         string
+	SubscriberOnly bool
+}
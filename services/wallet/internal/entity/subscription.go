@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+type SubscriptionType string
+
+const (
+	SubscriptionTypeFree   SubscriptionType = "free"
+	SubscriptionTypePaid   SubscriptionType = "paid"
+	SubscriptionTypeLapsed SubscriptionType = "lapsed"
+)
+
+type SubscriptionTier struct {
+	ID        string
+	CreatorID string
+	Name      string
+	Price     int
+	Deleted   bool
+}
+
+type Subscription struct {
+	ID               string           `json:"id"`
+	ViewerID         string           `json:"viewer_id"`
+	CreatorID        string           `json:"creator_id"`
+	Type             SubscriptionType `json:"type"`
+	TierID           string           `json:"tier_id,omitempty"`
+	CurrentPeriodEnd *time.Time       `json:"current_period_end,omitempty"`
+	// CancelAtPeriodEnd is set when the viewer unfollows during a paid
+	// period; the subscription is removed instead of renewed.
+	CancelAtPeriodEnd bool `json:"cancel_at_period_end"`
+}
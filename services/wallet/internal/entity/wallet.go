@@ -5,27 +5,45 @@ import "time"
 type TransactionType string
 
 const (
-	TransactionTypePurchase TransactionType = "purchase"
-	TransactionTypeEarn     TransactionType = "earn"
-	TransactionTypeRefund   TransactionType = "refund"
-	TransactionTypeDonation TransactionType = "donation"
+	TransactionTypePurchase      TransactionType = "purchase"
+	TransactionTypeEarn          TransactionType = "earn"
+	TransactionTypeRefund        TransactionType = "refund"
+	TransactionTypeDonation      TransactionType = "donation"
+	TransactionTypeSubscription  TransactionType = "subscription"
+	TransactionTypePayout        TransactionType = "payout"
+	TransactionTypePayoutRelease TransactionType = "payout_release"
+	TransactionTypeFee           TransactionType = "fee"
 )
 
 type Wallet struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Balance   int       `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Balance     int       `json:"balance"`
+	HeldBalance int       `json:"held_balance"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type Transaction struct {
-	ID            string          `json:"id"`
-	UserID        string          `json:"user_id"`
-	PostID        string          `json:"post_id,omitempty"`
-	Type          TransactionType  `json:"type"`
-	Amount        int             `json:"amount"`
-	BalanceBefore int             `json:"balance_before"`
-	BalanceAfter  int             `json:"balance_after"`
-	CreatedAt     time.Time       `json:"created_at"`
+	ID                    string          `json:"id"`
+	UserID                string          `json:"user_id"`
+	PostID                string          `json:"post_id,omitempty"`
+	Type                  TransactionType `json:"type"`
+	Amount                int             `json:"amount"`
+	BalanceBefore         int             `json:"balance_before"`
+	BalanceAfter          int             `json:"balance_after"`
+	TransferID            string          `json:"transfer_id,omitempty"`
+	RefundedTransactionID string          `json:"refunded_transaction_id,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
+}
+
+// LedgerEntry is one side of a balance movement. Positive amounts credit the
+// wallet, negative amounts debit it. A refund entry points at the transaction
+// it reverses.
+type LedgerEntry struct {
+	UserID                string
+	PostID                string
+	Type                  TransactionType
+	Amount                int
+	RefundedTransactionID string
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type CreatorFeeOverrideModel struct {
+	CreatorID  string    `gorm:"column:creator_id;type:uuid;primaryKey"`
+	FeePercent int       `gorm:"column:fee_percent;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+func (CreatorFeeOverrideModel) TableName() string {
+	return "creator_fee_overrides"
+}
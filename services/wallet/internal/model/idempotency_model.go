@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IdempotencyKeyModel struct {
+	ID             string     `gorm:"type:uuid;primary_key" json:"id"`
+	UserID         string     `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	IdempotencyKey string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key" json:"idempotency_key"`
+	RequestHash    string     `gorm:"type:varchar(64);not null" json:"request_hash"`
+	TransactionID  *string    `gorm:"type:uuid" json:"transaction_id,omitempty"`
+	StatusCode     int        `json:"status_code"`
+	ResponseBody   string     `gorm:"type:text" json:"response_body"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+func (IdempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+func (k *IdempotencyKeyModel) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type PayoutModel struct {
+	ID                string     `gorm:"column:id;type:uuid;primaryKey"`
+	CreatorID         string     `gorm:"column:creator_id;type:uuid;not null"`
+	Amount            int        `gorm:"column:amount;not null"`
+	Status            string     `gorm:"column:status;type:varchar(20);not null"`
+	ReviewerID        *string    `gorm:"column:reviewer_id;type:uuid"`
+	Reason            *string    `gorm:"column:reason;type:text"`
+	ProviderReference *string    `gorm:"column:provider_reference;type:varchar(255)"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+	CompletedAt       *time.Time `gorm:"column:completed_at;type:timestamp"`
+}
+
+func (PayoutModel) TableName() string {
+	return "payouts"
+}
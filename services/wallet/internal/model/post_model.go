@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type PostModel struct {
+	ID             string     `gorm:"column:id;type:uuid;primaryKey"`
+	CreatorID      string     `gorm:"column:creator_id;type:uuid;not null"`
+	Price          int        `gorm:"column:price;type:integer;default:0"`
+	Status         string     `gorm:"column:status;type:varchar(20)"`
+	Purchases      int        `gorm:"column:purchases;type:integer;default:0"`
+	SubscriberOnly bool       `gorm:"column:subscriber_only;default:false"`
+	DeletedAt      *time.Time `gorm:"column:deleted_at;type:timestamp"`
+}
+
+func (PostModel) TableName() string {
+	return "posts"
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type SubscriptionTierModel struct {
+	ID        string     `gorm:"column:id;type:uuid;primaryKey"`
+	CreatorID string     `gorm:"column:creator_id;type:uuid;not null"`
+	Name      string     `gorm:"column:name;type:varchar(100)"`
+	Price     int        `gorm:"column:price;type:integer"`
+	DeletedAt *time.Time `gorm:"column:deleted_at;type:timestamp"`
+}
+
+func (SubscriptionTierModel) TableName() string {
+	return "subscription_tiers"
+}
+
+type SubscriptionModel struct {
+	ID                string     `gorm:"column:id;type:uuid;primaryKey"`
+	ViewerID          string     `gorm:"column:viewer_id;type:uuid;not null"`
+	CreatorID         string     `gorm:"column:creator_id;type:uuid;not null"`
+	Type              string     `gorm:"column:type;type:varchar(10)"`
+	TierID            *string    `gorm:"column:tier_id;type:uuid"`
+	CurrentPeriodEnd  *time.Time `gorm:"column:current_period_end;type:timestamp"`
+	CancelAtPeriodEnd bool       `gorm:"column:cancel_at_period_end"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+	DeletedAt         *time.Time `gorm:"column:deleted_at;type:timestamp"`
+}
+
+func (SubscriptionModel) TableName() string {
+	return "subscriptions"
+}
@@ -8,11 +8,12 @@ import (
 )
 
 type WalletModel struct {
-	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
-	UserID    string    `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
-	Balance   int       `gorm:"default:0" json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `gorm:"type:uuid;primary_key" json:"id"`
+	UserID      string    `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
+	Balance     int       `gorm:"default:0" json:"balance"`
+	HeldBalance int       `gorm:"default:0" json:"held_balance"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 func (WalletModel) TableName() string {
@@ -27,14 +28,16 @@ func (w *WalletModel) BeforeCreate(tx *gorm.DB) error {
 }
 
 type TransactionModel struct {
-	ID            string    `gorm:"type:uuid;primary_key" json:"id"`
-	UserID        string    `gorm:"type:uuid;not null;index" json:"user_id"`
-	PostID        string    `gorm:"type:uuid;index" json:"post_id,omitempty"`
-	Type          string    `gorm:"type:varchar(20);not null" json:"type"`
-	Amount        int       `gorm:"not null" json:"amount"`
-	BalanceBefore int       `json:"balance_before"`
-	BalanceAfter  int       `json:"balance_after"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                    string    `gorm:"type:uuid;primary_key" json:"id"`
+	UserID                string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	PostID                *string   `gorm:"type:uuid;index" json:"post_id,omitempty"`
+	Type                  string    `gorm:"type:varchar(20);not null" json:"type"`
+	Amount                int       `gorm:"not null" json:"amount"`
+	BalanceBefore         int       `json:"balance_before"`
+	BalanceAfter          int       `json:"balance_after"`
+	TransferID            *string   `gorm:"type:uuid;index" json:"transfer_id,omitempty"`
+	RefundedTransactionID *string   `gorm:"type:uuid" json:"refunded_transaction_id,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
 }
 
 func (TransactionModel) TableName() string {
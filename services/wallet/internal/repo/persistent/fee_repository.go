@@ -0,0 +1,59 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/wallet/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCreatorNotFound = errors.New("creator not found")
+
+type FeeRepository interface {
+	GetCreatorFeePercent(creatorID string) (percent int, found bool, err error)
+	SetCreatorFeePercent(creatorID string, percent int) error
+	DeleteCreatorFeePercent(creatorID string) error
+}
+
+type feeRepository struct {
+	db *gorm.DB
+}
+
+func NewFeeRepository(db *gorm.DB) FeeRepository {
+	return &feeRepository{db: db}
+}
+
+func (r *feeRepository) GetCreatorFeePercent(creatorID string) (int, bool, error) {
+	var override model.CreatorFeeOverrideModel
+	err := r.db.Where("creator_id = ?", creatorID).First(&override).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return override.FeePercent, true, nil
+}
+
+func (r *feeRepository) SetCreatorFeePercent(creatorID string, percent int) error {
+	override := model.CreatorFeeOverrideModel{
+		CreatorID:  creatorID,
+		FeePercent: percent,
+		UpdatedAt:  time.Now(),
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "creator_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fee_percent", "updated_at"}),
+	}).Create(&override).Error
+	if isForeignKeyViolation(err) {
+		return ErrCreatorNotFound
+	}
+	return err
+}
+
+func (r *feeRepository) DeleteCreatorFeePercent(creatorID string) error {
+	return r.db.Where("creator_id = ?", creatorID).Delete(&model.CreatorFeeOverrideModel{}).Error
+}
@@ -0,0 +1,81 @@
+package persistent
+
+import (
+	"time"
+
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Reserve(userID, key, requestHash string, expiredBefore time.Time) (*entity.IdempotencyRecord, bool, error)
+	Complete(userID, key, transactionID string, statusCode int, responseBody string) error
+	Release(userID, key string) error
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve claims the key for a new request. When the key is already taken it
+// returns the existing record and false. Records created before expiredBefore
+// are discarded, so a key can be reused once the replay window has passed.
+func (r *idempotencyRepository) Reserve(userID, key, requestHash string, expiredBefore time.Time) (*entity.IdempotencyRecord, bool, error) {
+	var record model.IdempotencyKeyModel
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND idempotency_key = ? AND created_at < ?", userID, key, expiredBefore).
+			Delete(&model.IdempotencyKeyModel{}).Error; err != nil {
+			return err
+		}
+
+		record = model.IdempotencyKeyModel{
+			UserID:         userID,
+			IdempotencyKey: key,
+			RequestHash:    requestHash,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			created = true
+			return nil
+		}
+
+		record = model.IdempotencyKeyModel{}
+		return tx.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return ToIdempotencyRecordEntity(&record), created, nil
+}
+
+func (r *idempotencyRepository) Complete(userID, key, transactionID string, statusCode int, responseBody string) error {
+	updates := map[string]interface{}{
+		"status_code":   statusCode,
+		"response_body": responseBody,
+		"completed_at":  time.Now(),
+	}
+	if transactionID != "" {
+		updates["transaction_id"] = transactionID
+	}
+	return r.db.Model(&model.IdempotencyKeyModel{}).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Updates(updates).Error
+}
+
+func (r *idempotencyRepository) Release(userID, key string) error {
+	return r.db.Where("user_id = ? AND idempotency_key = ? AND completed_at IS NULL", userID, key).
+		Delete(&model.IdempotencyKeyModel{}).Error
+}
@@ -0,0 +1,64 @@
+package persistent
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserve_ConcurrentRetriesClaimKeyOnce(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewIdempotencyRepository(db)
+	userID := createTestUsers(t, db, 1)[0]
+
+	const retries = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claimed := 0
+
+	for i := 0; i < retries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, created, err := repo.Reserve(userID, "retry-key", "hash", time.Now().Add(-time.Hour))
+			assert.NoError(t, err)
+			if created {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, claimed, "only one request may claim the idempotency key")
+}
+
+func TestReserve_ReturnsCompletedRecordAndReleasesFailedOnes(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewIdempotencyRepository(db)
+	userID := createTestUsers(t, db, 1)[0]
+	window := time.Now().Add(-time.Hour)
+
+	_, created, err := repo.Reserve(userID, "failed-key", "hash", window)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NoError(t, repo.Release(userID, "failed-key"))
+
+	_, created, err = repo.Reserve(userID, "failed-key", "hash", window)
+	require.NoError(t, err)
+	assert.True(t, created, "a released key must be claimable again")
+
+	require.NoError(t, repo.Complete(userID, "failed-key", "", 200, `{"balance":100}`))
+	require.NoError(t, repo.Release(userID, "failed-key"))
+
+	record, created, err := repo.Reserve(userID, "failed-key", "hash", window)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.True(t, record.IsCompleted())
+	assert.Equal(t, 200, record.StatusCode)
+	assert.Equal(t, `{"balance":100}`, record.ResponseBody)
+}
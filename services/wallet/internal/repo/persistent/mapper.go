@@ -11,11 +11,12 @@ func ToWalletEntity(m *model.WalletModel) *entity.Wallet {
 	}
 
 	return &entity.Wallet{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		Balance:   m.Balance,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Balance:     m.Balance,
+		HeldBalance: m.HeldBalance,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
 	}
 }
 
@@ -25,11 +26,12 @@ func ToWalletModel(e *entity.Wallet) *model.WalletModel {
 	}
 
 	return &model.WalletModel{
-		ID:        e.ID,
-		UserID:    e.UserID,
-		Balance:   e.Balance,
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Balance:     e.Balance,
+		HeldBalance: e.HeldBalance,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
 	}
 }
 
@@ -38,16 +40,25 @@ func ToTransactionEntity(m *model.TransactionModel) *entity.Transaction {
 		return nil
 	}
 
-	return &entity.Transaction{
+	transaction := &entity.Transaction{
 		ID:            m.ID,
 		UserID:        m.UserID,
-		PostID:        m.PostID,
 		Type:          entity.TransactionType(m.Type),
 		Amount:        m.Amount,
 		BalanceBefore: m.BalanceBefore,
 		BalanceAfter:  m.BalanceAfter,
 		CreatedAt:     m.CreatedAt,
 	}
+	if m.PostID != nil {
+		transaction.PostID = *m.PostID
+	}
+	if m.TransferID != nil {
+		transaction.TransferID = *m.TransferID
+	}
+	if m.RefundedTransactionID != nil {
+		transaction.RefundedTransactionID = *m.RefundedTransactionID
+	}
+	return transaction
 }
 
 func ToTransactionModel(e *entity.Transaction) *model.TransactionModel {
@@ -55,14 +66,117 @@ func ToTransactionModel(e *entity.Transaction) *model.TransactionModel {
 		return nil
 	}
 
-	return &model.TransactionModel{
+	transaction := &model.TransactionModel{
 		ID:            e.ID,
 		UserID:        e.UserID,
-		PostID:        e.PostID,
 		Type:          string(e.Type),
 		Amount:        e.Amount,
 		BalanceBefore: e.BalanceBefore,
 		BalanceAfter:  e.BalanceAfter,
 		CreatedAt:     e.CreatedAt,
 	}
+	if e.PostID != "" {
+		transaction.PostID = &e.PostID
+	}
+	if e.TransferID != "" {
+		transaction.TransferID = &e.TransferID
+	}
+	if e.RefundedTransactionID != "" {
+		transaction.RefundedTransactionID = &e.RefundedTransactionID
+	}
+	return transaction
+}
+
+func ToPostEntity(m *model.PostModel) *entity.Post {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.Post{
+		ID:             m.ID,
+		CreatorID:      m.CreatorID,
+		Price:          m.Price,
+		Status:         m.Status,
+		SubscriberOnly: m.SubscriberOnly,
+	}
+}
+
+func ToIdempotencyRecordEntity(m *model.IdempotencyKeyModel) *entity.IdempotencyRecord {
+	if m == nil {
+		return nil
+	}
+
+	record := &entity.IdempotencyRecord{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		Key:          m.IdempotencyKey,
+		RequestHash:  m.RequestHash,
+		StatusCode:   m.StatusCode,
+		ResponseBody: m.ResponseBody,
+		CreatedAt:    m.CreatedAt,
+		CompletedAt:  m.CompletedAt,
+	}
+	if m.TransactionID != nil {
+		record.TransactionID = *m.TransactionID
+	}
+	return record
+}
+
+func ToSubscriptionTierEntity(m *model.SubscriptionTierModel) *entity.SubscriptionTier {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.SubscriptionTier{
+		ID:        m.ID,
+		CreatorID: m.CreatorID,
+		Name:      m.Name,
+		Price:     m.Price,
+		Deleted:   m.DeletedAt != nil,
+	}
+}
+
+func ToSubscriptionEntity(m *model.SubscriptionModel) *entity.Subscription {
+	if m == nil {
+		return nil
+	}
+
+	subscription := &entity.Subscription{
+		ID:                m.ID,
+		ViewerID:          m.ViewerID,
+		CreatorID:         m.CreatorID,
+		Type:              entity.SubscriptionType(m.Type),
+		CurrentPeriodEnd:  m.CurrentPeriodEnd,
+		CancelAtPeriodEnd: m.CancelAtPeriodEnd,
+	}
+	if m.TierID != nil {
+		subscription.TierID = *m.TierID
+	}
+	return subscription
+}
+
+func ToPayoutEntity(m *model.PayoutModel) *entity.Payout {
+	if m == nil {
+		return nil
+	}
+
+	payout := &entity.Payout{
+		ID:          m.ID,
+		CreatorID:   m.CreatorID,
+		Amount:      m.Amount,
+		Status:      entity.PayoutStatus(m.Status),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		CompletedAt: m.CompletedAt,
+	}
+	if m.ReviewerID != nil {
+		payout.ReviewerID = *m.ReviewerID
+	}
+	if m.Reason != nil {
+		payout.Reason = *m.Reason
+	}
+	if m.ProviderReference != nil {
+		payout.ProviderReference = *m.ProviderReference
+	}
+	return payout
 }
@@ -0,0 +1,203 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrPayoutChanged = errors.New("payout changed")
+
+type PayoutRepository interface {
+	Request(creatorID string, amount int) (*entity.Payout, error)
+	Get(payoutID string) (*entity.Payout, error)
+	ListByCreator(creatorID string, limit, offset int) ([]*entity.Payout, error)
+	ListByStatus(status entity.PayoutStatus, limit, offset int) ([]*entity.Payout, error)
+	StartProcessing(payoutID, reviewerID string) (*entity.Payout, error)
+	Complete(payoutID, providerReference string) (*entity.Payout, error)
+	Release(payoutID string, from, to entity.PayoutStatus, reviewerID, reason string) (*entity.Payout, error)
+}
+
+type payoutRepository struct {
+	db *gorm.DB
+}
+
+func NewPayoutRepository(db *gorm.DB) PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+// Request debits the amount from the creator's balance and holds it for the
+// new pending payout in a single transaction.
+func (r *payoutRepository) Request(creatorID string, amount int) (*entity.Payout, error) {
+	payoutModel := model.PayoutModel{
+		ID:        uuid.New().String(),
+		CreatorID: creatorID,
+		Amount:    amount,
+		Status:    string(entity.PayoutStatusPending),
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		entries := []entity.LedgerEntry{
+			{UserID: creatorID, Type: entity.TransactionTypePayout, Amount: -amount},
+		}
+		if _, err := applyEntries(tx, entries); err != nil {
+			return err
+		}
+		if err := adjustHeldBalance(tx, creatorID, amount); err != nil {
+			return err
+		}
+		return tx.Create(&payoutModel).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ToPayoutEntity(&payoutModel), nil
+}
+
+func (r *payoutRepository) Get(payoutID string) (*entity.Payout, error) {
+	return getPayout(r.db, payoutID)
+}
+
+func (r *payoutRepository) ListByCreator(creatorID string, limit, offset int) ([]*entity.Payout, error) {
+	return r.list(r.db.Where("creator_id = ?", creatorID).Order("created_at DESC"), limit, offset)
+}
+
+// ListByStatus returns the oldest payouts first so reviewers work through the
+// queue in the order creators asked.
+func (r *payoutRepository) ListByStatus(status entity.PayoutStatus, limit, offset int) ([]*entity.Payout, error) {
+	return r.list(r.db.Where("status = ?", string(status)).Order("created_at ASC"), limit, offset)
+}
+
+func (r *payoutRepository) list(query *gorm.DB, limit, offset int) ([]*entity.Payout, error) {
+	var payoutModels []model.PayoutModel
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Find(&payoutModels).Error; err != nil {
+		return nil, err
+	}
+
+	payouts := make([]*entity.Payout, len(payoutModels))
+	for i := range payoutModels {
+		payouts[i] = ToPayoutEntity(&payoutModels[i])
+	}
+	return payouts, nil
+}
+
+// StartProcessing claims a pending payout for sending. Only one reviewer can
+// claim it; everyone else gets ErrPayoutChanged. A payout left in processing
+// (e.g. the service died mid-send) has to be reconciled with the provider.
+func (r *payoutRepository) StartProcessing(payoutID, reviewerID string) (*entity.Payout, error) {
+	var payout *entity.Payout
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := transitionPayout(tx, payoutID, entity.PayoutStatusPending, map[string]interface{}{
+			"status":      string(entity.PayoutStatusProcessing),
+			"reviewer_id": reviewerID,
+		})
+		if err != nil {
+			return err
+		}
+		payout, err = getPayout(tx, payoutID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// Complete records that the provider sent the money; the held funds leave the
+// wallet for good.
+func (r *payoutRepository) Complete(payoutID, providerReference string) (*entity.Payout, error) {
+	var payout *entity.Payout
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := transitionPayout(tx, payoutID, entity.PayoutStatusProcessing, map[string]interface{}{
+			"status":             string(entity.PayoutStatusCompleted),
+			"provider_reference": providerReference,
+			"completed_at":       time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if payout, err = getPayout(tx, payoutID); err != nil {
+			return err
+		}
+		return adjustHeldBalance(tx, payout.CreatorID, -payout.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// Release moves a payout from one status to a terminal one and credits the
+// held funds back to the creator's balance as a payout_release transaction.
+func (r *payoutRepository) Release(payoutID string, from, to entity.PayoutStatus, reviewerID, reason string) (*entity.Payout, error) {
+	updates := map[string]interface{}{
+		"status": string(to),
+		"reason": reason,
+	}
+	if reviewerID != "" {
+		updates["reviewer_id"] = reviewerID
+	}
+
+	var payout *entity.Payout
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionPayout(tx, payoutID, from, updates); err != nil {
+			return err
+		}
+		var err error
+		if payout, err = getPayout(tx, payoutID); err != nil {
+			return err
+		}
+		if err := adjustHeldBalance(tx, payout.CreatorID, -payout.Amount); err != nil {
+			return err
+		}
+		entries := []entity.LedgerEntry{
+			{UserID: payout.CreatorID, Type: entity.TransactionTypePayoutRelease, Amount: payout.Amount},
+		}
+		_, err = applyEntries(tx, entries)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+func getPayout(db *gorm.DB, payoutID string) (*entity.Payout, error) {
+	var payoutModel model.PayoutModel
+	if err := db.Where("id = ?", payoutID).First(&payoutModel).Error; err != nil {
+		return nil, err
+	}
+	return ToPayoutEntity(&payoutModel), nil
+}
+
+// transitionPayout updates a payout only if it is still in the expected
+// status, so concurrent approve/reject calls can't both succeed.
+func transitionPayout(tx *gorm.DB, payoutID string, from entity.PayoutStatus, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	result := tx.Model(&model.PayoutModel{}).
+		Where("id = ? AND status = ?", payoutID, string(from)).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPayoutChanged
+	}
+	return nil
+}
+
+func adjustHeldBalance(tx *gorm.DB, userID string, amount int) error {
+	return tx.Model(&model.WalletModel{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"held_balance": gorm.Expr("held_balance + ?", amount),
+			"updated_at":   time.Now(),
+		}).Error
+}
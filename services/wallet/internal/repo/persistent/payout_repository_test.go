@@ -0,0 +1,80 @@
+package persistent
+
+import (
+	"sync"
+	"testing"
+
+	"lick-scroll/services/wallet/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayout_ConcurrentReviewsSettleOnce(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	walletRepo := NewWalletRepository(db)
+	repo := NewPayoutRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	creatorID, moderatorID := userIDs[0], userIDs[1]
+
+	_, err := walletRepo.ApplyEntries([]entity.LedgerEntry{{UserID: creatorID, Type: entity.TransactionTypeEarn, Amount: 500}})
+	require.NoError(t, err)
+
+	_, err = repo.Request(creatorID, 800)
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+	payout, err := repo.Request(creatorID, 300)
+	require.NoError(t, err)
+
+	wallet, err := walletRepo.GetOrCreateWallet(creatorID)
+	require.NoError(t, err)
+	assert.Equal(t, 200, wallet.Balance)
+	assert.Equal(t, 300, wallet.HeldBalance)
+
+	// Half of the reviewers reject, half try to claim the payout for sending
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(reject bool) {
+			defer wg.Done()
+			var err error
+			if reject {
+				_, err = repo.Release(payout.ID, entity.PayoutStatusPending, entity.PayoutStatusRejected, moderatorID, "rejected")
+			} else {
+				_, err = repo.StartProcessing(payout.ID, moderatorID)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if err != ErrPayoutChanged {
+				t.Errorf("unexpected review error: %v", err)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+
+	payout, err = repo.Get(payout.ID)
+	require.NoError(t, err)
+	if payout.Status == entity.PayoutStatusProcessing {
+		payout, err = repo.Complete(payout.ID, "test-reference")
+		require.NoError(t, err)
+		assert.Equal(t, entity.PayoutStatusCompleted, payout.Status)
+	}
+
+	wallet, err = walletRepo.GetOrCreateWallet(creatorID)
+	require.NoError(t, err)
+	assert.Zero(t, wallet.HeldBalance)
+	if payout.Status == entity.PayoutStatusRejected {
+		assert.Equal(t, 500, wallet.Balance)
+	} else {
+		assert.Equal(t, 200, wallet.Balance)
+	}
+
+	assertLedgerConsistent(t, db, userIDs)
+}
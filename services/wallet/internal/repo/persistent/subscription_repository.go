@@ -0,0 +1,197 @@
+package persistent
+
+import (
+	"errors"
+	"time"
+
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAlreadySubscribed   = errors.New("already subscribed")
+	ErrSubscriptionChanged = errors.New("subscription changed")
+)
+
+type SubscriptionRepository interface {
+	GetTier(tierID string) (*entity.SubscriptionTier, error)
+	Subscribe(viewerID string, tier *entity.SubscriptionTier, periodEnd time.Time, entries []entity.LedgerEntry) (*entity.Subscription, error)
+	ListDue(now time.Time, limit int) ([]*entity.Subscription, error)
+	Renew(subscription *entity.Subscription, periodEnd time.Time, entries []entity.LedgerEntry) error
+	Lapse(subscription *entity.Subscription) error
+	HasActiveSubscription(viewerID, creatorID string) (bool, error)
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+// GetTier also returns deleted tiers so renewals can tell a removed tier from
+// a missing one.
+func (r *subscriptionRepository) GetTier(tierID string) (*entity.SubscriptionTier, error) {
+	var tierModel model.SubscriptionTierModel
+	if err := r.db.Where("id = ?", tierID).First(&tierModel).Error; err != nil {
+		return nil, err
+	}
+	return ToSubscriptionTierEntity(&tierModel), nil
+}
+
+// Subscribe charges the first period and turns the viewer's subscription to
+// the creator into a paid one in a single transaction. An existing free or
+// lapsed subscription is upgraded in place. A paid one the viewer cancelled
+// during its period is resumed instead: nothing is charged and it renews on
+// the chosen tier when the period ends.
+func (r *subscriptionRepository) Subscribe(viewerID string, tier *entity.SubscriptionTier, periodEnd time.Time, entries []entity.LedgerEntry) (*entity.Subscription, error) {
+	var subscriptionModel model.SubscriptionModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("viewer_id = ? AND creator_id = ?", viewerID, tier.CreatorID).
+			First(&subscriptionModel).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		tierID := tier.ID
+		if exists && subscriptionModel.DeletedAt == nil && isActivePaid(&subscriptionModel, time.Now()) {
+			if !subscriptionModel.CancelAtPeriodEnd {
+				return ErrAlreadySubscribed
+			}
+			subscriptionModel.TierID = &tierID
+			subscriptionModel.CancelAtPeriodEnd = false
+			return tx.Model(&model.SubscriptionModel{}).Where("id = ?", subscriptionModel.ID).Updates(map[string]interface{}{
+				"tier_id":              tierID,
+				"cancel_at_period_end": false,
+				"updated_at":           time.Now(),
+			}).Error
+		}
+
+		if _, err := applyEntries(tx, entries); err != nil {
+			return err
+		}
+
+		if !exists {
+			subscriptionModel = model.SubscriptionModel{
+				ID:               uuid.New().String(),
+				ViewerID:         viewerID,
+				CreatorID:        tier.CreatorID,
+				Type:             string(entity.SubscriptionTypePaid),
+				TierID:           &tierID,
+				CurrentPeriodEnd: &periodEnd,
+			}
+			return tx.Create(&subscriptionModel).Error
+		}
+
+		subscriptionModel.Type = string(entity.SubscriptionTypePaid)
+		subscriptionModel.TierID = &tierID
+		subscriptionModel.CurrentPeriodEnd = &periodEnd
+		subscriptionModel.CancelAtPeriodEnd = false
+		subscriptionModel.DeletedAt = nil
+		return tx.Model(&model.SubscriptionModel{}).Where("id = ?", subscriptionModel.ID).Updates(map[string]interface{}{
+			"type":                 subscriptionModel.Type,
+			"tier_id":              tierID,
+			"current_period_end":   periodEnd,
+			"cancel_at_period_end": false,
+			"deleted_at":           nil,
+			"updated_at":           time.Now(),
+		}).Error
+	})
+	if err != nil {
+		// Two first-time subscribes racing for the same creator
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadySubscribed
+		}
+		return nil, err
+	}
+	return ToSubscriptionEntity(&subscriptionModel), nil
+}
+
+// ListDue returns paid subscriptions whose period has ended, oldest first.
+func (r *subscriptionRepository) ListDue(now time.Time, limit int) ([]*entity.Subscription, error) {
+	var subscriptionModels []model.SubscriptionModel
+	err := r.db.Where("type = ? AND current_period_end <= ? AND deleted_at IS NULL", string(entity.SubscriptionTypePaid), now).
+		Order("current_period_end ASC").
+		Limit(limit).
+		Find(&subscriptionModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*entity.Subscription, len(subscriptionModels))
+	for i := range subscriptionModels {
+		subscriptions[i] = ToSubscriptionEntity(&subscriptionModels[i])
+	}
+	return subscriptions, nil
+}
+
+// Renew extends the period and charges for it atomically. The period update is
+// conditional on the period end the caller saw and on the subscription not being
+// cancelled, so a subscription renewed or cancelled concurrently returns
+// ErrSubscriptionChanged and is never charged twice or after cancellation.
+func (r *subscriptionRepository) Renew(subscription *entity.Subscription, periodEnd time.Time, entries []entity.LedgerEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SubscriptionModel{}).
+			Where("id = ? AND type = ? AND current_period_end = ? AND cancel_at_period_end = FALSE AND deleted_at IS NULL",
+				subscription.ID, string(entity.SubscriptionTypePaid), subscription.CurrentPeriodEnd).
+			Updates(map[string]interface{}{
+				"current_period_end": periodEnd,
+				"updated_at":         time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubscriptionChanged
+		}
+
+		_, err := applyEntries(tx, entries)
+		return err
+	})
+}
+
+// Lapse drops a paid subscription back to follow-only, or removes it entirely
+// if the viewer cancelled it. Like Renew it only touches the subscription if
+// nobody renewed it in the meantime.
+func (r *subscriptionRepository) Lapse(subscription *entity.Subscription) error {
+	query := r.db.Model(&model.SubscriptionModel{}).
+		Where("id = ? AND type = ? AND current_period_end = ?",
+			subscription.ID, string(entity.SubscriptionTypePaid), subscription.CurrentPeriodEnd)
+
+	var result *gorm.DB
+	if subscription.CancelAtPeriodEnd {
+		result = query.Where("cancel_at_period_end = TRUE").Delete(&model.SubscriptionModel{})
+	} else {
+		result = query.Updates(map[string]interface{}{
+			"type":       string(entity.SubscriptionTypeLapsed),
+			"updated_at": time.Now(),
+		})
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionChanged
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) HasActiveSubscription(viewerID, creatorID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.SubscriptionModel{}).
+		Where("viewer_id = ? AND creator_id = ? AND type = ? AND current_period_end > ? AND deleted_at IS NULL",
+			viewerID, creatorID, string(entity.SubscriptionTypePaid), time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func isActivePaid(m *model.SubscriptionModel, now time.Time) bool {
+	return m.Type == string(entity.SubscriptionTypePaid) && m.CurrentPeriodEnd != nil && m.CurrentPeriodEnd.After(now)
+}
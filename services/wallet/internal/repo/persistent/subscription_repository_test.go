@@ -0,0 +1,109 @@
+package persistent
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"lick-scroll/services/wallet/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe_ConcurrentRequestsChargeOnce(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	walletRepo := NewWalletRepository(db)
+	repo := NewSubscriptionRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	viewerID, creatorID := userIDs[0], userIDs[1]
+
+	tierID := uuid.New().String()
+	require.NoError(t, db.Exec("INSERT INTO subscription_tiers (id, creator_id, name, price) VALUES (?, ?, ?, ?)", tierID, creatorID, "Gold", 100).Error)
+
+	_, err := walletRepo.ApplyEntries([]entity.LedgerEntry{{UserID: viewerID, Type: entity.TransactionTypeEarn, Amount: 1000}})
+	require.NoError(t, err)
+
+	tier, err := repo.GetTier(tierID)
+	require.NoError(t, err)
+	entries := []entity.LedgerEntry{
+		{UserID: viewerID, Type: entity.TransactionTypeSubscription, Amount: -tier.Price},
+		{UserID: creatorID, Type: entity.TransactionTypeEarn, Amount: tier.Price},
+	}
+
+	const attempts = 5
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Subscribe(viewerID, tier, time.Now().AddDate(0, 1, 0), entries)
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, ErrAlreadySubscribed)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded, "only one subscribe may charge the viewer")
+	assert.Equal(t, 1100, assertLedgerConsistent(t, db, userIDs))
+
+	subscribed, err := repo.HasActiveSubscription(viewerID, creatorID)
+	require.NoError(t, err)
+	assert.True(t, subscribed)
+}
+
+func TestSubscribe_ResumesCancelledSubscriptionWithoutCharging(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	walletRepo := NewWalletRepository(db)
+	repo := NewSubscriptionRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	viewerID, creatorID := userIDs[0], userIDs[1]
+
+	tierID := uuid.New().String()
+	require.NoError(t, db.Exec("INSERT INTO subscription_tiers (id, creator_id, name, price) VALUES (?, ?, ?, ?)", tierID, creatorID, "Gold", 100).Error)
+
+	_, err := walletRepo.ApplyEntries([]entity.LedgerEntry{{UserID: viewerID, Type: entity.TransactionTypeEarn, Amount: 1000}})
+	require.NoError(t, err)
+
+	tier, err := repo.GetTier(tierID)
+	require.NoError(t, err)
+	entries := []entity.LedgerEntry{
+		{UserID: viewerID, Type: entity.TransactionTypeSubscription, Amount: -tier.Price},
+		{UserID: creatorID, Type: entity.TransactionTypeEarn, Amount: tier.Price},
+	}
+
+	first, err := repo.Subscribe(viewerID, tier, time.Now().AddDate(0, 1, 0), entries)
+	require.NoError(t, err)
+
+	// The viewer unsubscribes during the paid period
+	require.NoError(t, db.Exec("UPDATE subscriptions SET cancel_at_period_end = TRUE WHERE id = ?", first.ID).Error)
+
+	resumed, err := repo.Subscribe(viewerID, tier, time.Now().AddDate(0, 1, 0), entries)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, resumed.ID)
+	assert.False(t, resumed.CancelAtPeriodEnd)
+	require.NotNil(t, resumed.CurrentPeriodEnd)
+	assert.WithinDuration(t, *first.CurrentPeriodEnd, *resumed.CurrentPeriodEnd, time.Second, "resuming keeps the paid period")
+
+	wallet, err := walletRepo.GetOrCreateWallet(viewerID)
+	require.NoError(t, err)
+	assert.Equal(t, 900, wallet.Balance, "resuming charges nothing")
+	assertLedgerConsistent(t, db, userIDs)
+
+	var cancelled bool
+	require.NoError(t, db.Raw("SELECT cancel_at_period_end FROM subscriptions WHERE id = ?", first.ID).Scan(&cancelled).Error)
+	assert.False(t, cancelled)
+
+	_, err = repo.Subscribe(viewerID, tier, time.Now().AddDate(0, 1, 0), entries)
+	assert.ErrorIs(t, err, ErrAlreadySubscribed)
+}
@@ -1,18 +1,35 @@
 package persistent
 
 import (
+	"errors"
+	"sort"
+	"time"
+
 	"lick-scroll/services/wallet/internal/entity"
 	"lick-scroll/services/wallet/internal/model"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrAlreadyPurchased    = errors.New("post already purchased")
+	ErrAlreadyRefunded     = errors.New("transaction already refunded")
 )
 
 type WalletRepository interface {
 	GetOrCreateWallet(userID string) (*entity.Wallet, error)
-	UpdateWallet(wallet *entity.Wallet) error
-	CreateTransaction(transaction *entity.Transaction) error
+	ApplyEntries(entries []entity.LedgerEntry) ([]*entity.Transaction, error)
+	PurchasePost(postID string, entries []entity.LedgerEntry) ([]*entity.Transaction, error)
+	Refund(entries []entity.LedgerEntry) ([]*entity.Transaction, error)
+	GetTransaction(transactionID string) (*entity.Transaction, error)
+	GetTransfer(transferID string) ([]*entity.Transaction, error)
 	GetTransactions(userID string, limit, offset int) ([]*entity.Transaction, error)
+	GetPost(postID string) (*entity.Post, error)
+	HasPurchased(userID, postID string) (bool, error)
 }
 
 type walletRepository struct {
@@ -24,35 +41,88 @@ func NewWalletRepository(db *gorm.DB) WalletRepository {
 }
 
 func (r *walletRepository) GetOrCreateWallet(userID string) (*entity.Wallet, error) {
+	if err := ensureWallet(r.db, userID); err != nil {
+		return nil, err
+	}
+
 	var walletModel model.WalletModel
 	if err := r.db.Where("user_id = ?", userID).First(&walletModel).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			walletModel = model.WalletModel{
-				ID:      uuid.New().String(),
-				UserID:  userID,
-				Balance: 0,
-			}
-			if err := r.db.Create(&walletModel).Error; err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 	return ToWalletEntity(&walletModel), nil
 }
 
-func (r *walletRepository) UpdateWallet(wallet *entity.Wallet) error {
-	walletModel := ToWalletModel(wallet)
-	return r.db.Save(walletModel).Error
+// ApplyEntries moves balances and records one transaction per entry inside a
+// single database transaction. Either every entry is applied or none is.
+func (r *walletRepository) ApplyEntries(entries []entity.LedgerEntry) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transactions, err = applyEntries(tx, entries)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// PurchasePost applies the purchase entries and bumps the post purchase counter
+// atomically. A second purchase of the same post by the same user is rejected
+// by the idx_transactions_purchase_unique index.
+func (r *walletRepository) PurchasePost(postID string, entries []entity.LedgerEntry) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transactions, err = applyEntries(tx, entries)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.PostModel{}).Where("id = ?", postID).UpdateColumn("purchases", clause.Expr{SQL: "purchases + ?", Vars: []interface{}{1}}).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyPurchased
+		}
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// Refund applies reversing entries. Each entry references the transaction it
+// reverses, and idx_transactions_refund_unique rejects a second refund of the
+// same transaction even when two moderators act at once.
+func (r *walletRepository) Refund(entries []entity.LedgerEntry) ([]*entity.Transaction, error) {
+	transactions, err := r.ApplyEntries(entries)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyRefunded
+		}
+		return nil, err
+	}
+	return transactions, nil
 }
 
-func (r *walletRepository) CreateTransaction(transaction *entity.Transaction) error {
-	transactionModel := ToTransactionModel(transaction)
-	if transactionModel.ID == "" {
-		transactionModel.ID = uuid.New().String()
+func (r *walletRepository) GetTransaction(transactionID string) (*entity.Transaction, error) {
+	var transactionModel model.TransactionModel
+	if err := r.db.Where("id = ?", transactionID).First(&transactionModel).Error; err != nil {
+		return nil, err
+	}
+	return ToTransactionEntity(&transactionModel), nil
+}
+
+// GetTransfer returns every ledger row written by the same ApplyEntries call.
+func (r *walletRepository) GetTransfer(transferID string) ([]*entity.Transaction, error) {
+	var transactionModels []model.TransactionModel
+	if err := r.db.Where("transfer_id = ?", transferID).Order("created_at ASC").Find(&transactionModels).Error; err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*entity.Transaction, len(transactionModels))
+	for i := range transactionModels {
+		transactions[i] = ToTransactionEntity(&transactionModels[i])
 	}
-	return r.db.Create(transactionModel).Error
+	return transactions, nil
 }
 
 func (r *walletRepository) GetTransactions(userID string, limit, offset int) ([]*entity.Transaction, error) {
@@ -71,3 +141,99 @@ func (r *walletRepository) GetTransactions(userID string, limit, offset int) ([]
 	}
 	return transactions, nil
 }
+
+func (r *walletRepository) GetPost(postID string) (*entity.Post, error) {
+	var postModel model.PostModel
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", postID).First(&postModel).Error; err != nil {
+		return nil, err
+	}
+	return ToPostEntity(&postModel), nil
+}
+
+func (r *walletRepository) HasPurchased(userID, postID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.TransactionModel{}).
+		Where("user_id = ? AND post_id = ? AND type = ?", userID, postID, string(entity.TransactionTypePurchase)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// applyEntries must run inside a transaction. Wallet rows are updated in user ID
+// order so that concurrent transfers between the same wallets cannot deadlock.
+// Debits use a conditional UPDATE, so a balance can never go below zero even
+// when several requests race for the same wallet. All rows written by one call
+// share a transfer ID so the movement can be looked up (and reversed) as a whole.
+func applyEntries(tx *gorm.DB, entries []entity.LedgerEntry) ([]*entity.Transaction, error) {
+	ordered := make([]entity.LedgerEntry, len(entries))
+	copy(ordered, entries)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	transferID := uuid.New().String()
+	transactions := make([]*entity.Transaction, 0, len(ordered))
+	for _, entry := range ordered {
+		if err := ensureWallet(tx, entry.UserID); err != nil {
+			return nil, err
+		}
+
+		result := tx.Model(&model.WalletModel{}).
+			Where("user_id = ? AND balance + ? >= 0", entry.UserID, entry.Amount).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", entry.Amount),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, ErrInsufficientBalance
+		}
+
+		var walletModel model.WalletModel
+		if err := tx.Where("user_id = ?", entry.UserID).First(&walletModel).Error; err != nil {
+			return nil, err
+		}
+
+		transaction := &entity.Transaction{
+			ID:                    uuid.New().String(),
+			UserID:                entry.UserID,
+			PostID:                entry.PostID,
+			Type:                  entry.Type,
+			Amount:                entry.Amount,
+			BalanceBefore:         walletModel.Balance - entry.Amount,
+			BalanceAfter:          walletModel.Balance,
+			TransferID:            transferID,
+			RefundedTransactionID: entry.RefundedTransactionID,
+		}
+		transactionModel := ToTransactionModel(transaction)
+		if err := tx.Create(transactionModel).Error; err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, ToTransactionEntity(transactionModel))
+	}
+
+	return transactions, nil
+}
+
+func ensureWallet(db *gorm.DB, userID string) error {
+	walletModel := model.WalletModel{
+		ID:      uuid.New().String(),
+		UserID:  userID,
+		Balance: 0,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&walletModel).Error
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,301 @@
+package persistent
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+
+	"lick-scroll/services/wallet/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// These tests exercise the ledger against a real PostgreSQL database because the
+// guarantees under test (row locks, conditional updates, unique indexes) only exist
+// there. Point WALLET_TEST_DSN at a migrated database to run them, e.g.
+//
+//	WALLET_TEST_DSN="host=localhost user=postgres password=postgres dbname=lickscroll_test port=5432 sslmode=disable" make test-integration
+func setupLedgerTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("WALLET_TEST_DSN")
+	if dsn == "" {
+		t.Skip("Skipping ledger integration test - WALLET_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func createTestUsers(t *testing.T, db *gorm.DB, count int) []string {
+	userIDs := make([]string, count)
+	for i := range userIDs {
+		userIDs[i] = uuid.New().String()
+		suffix := userIDs[i][:8]
+		err := db.Exec(
+			"INSERT INTO users (id, email, username, password) VALUES (?, ?, ?, ?)",
+			userIDs[i], fmt.Sprintf("ledger-%s@test.local", suffix), fmt.Sprintf("ledger-%s", suffix), "x",
+		).Error
+		require.NoError(t, err)
+	}
+
+	t.Cleanup(func() {
+		// Wallets and transactions are removed by ON DELETE CASCADE
+		db.Exec("DELETE FROM users WHERE id IN ?", userIDs)
+	})
+	return userIDs
+}
+
+// assertLedgerConsistent checks that every wallet balance equals the sum of its
+// transactions and that no balance went negative.
+func assertLedgerConsistent(t *testing.T, db *gorm.DB, userIDs []string) int {
+	total := 0
+	for _, userID := range userIDs {
+		var balance, ledgerSum int
+		require.NoError(t, db.Raw("SELECT balance FROM wallets WHERE user_id = ?", userID).Scan(&balance).Error)
+		require.NoError(t, db.Raw("SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE user_id = ?", userID).Scan(&ledgerSum).Error)
+
+		assert.Equal(t, ledgerSum, balance, "ledger sum must match balance for user %s", userID)
+		assert.GreaterOrEqual(t, balance, 0, "balance must never be negative for user %s", userID)
+		total += balance
+	}
+	return total
+}
+
+func TestApplyEntries_ConcurrentTransfersKeepLedgerConsistent(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewWalletRepository(db)
+
+	const users = 5
+	const initialBalance = 1000
+	userIDs := createTestUsers(t, db, users)
+
+	for _, userID := range userIDs {
+		_, err := repo.ApplyEntries([]entity.LedgerEntry{
+			{UserID: userID, Type: entity.TransactionTypeEarn, Amount: initialBalance},
+		})
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 20; worker++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 25; i++ {
+				from := userIDs[rng.Intn(users)]
+				to := userIDs[rng.Intn(users)]
+				if from == to {
+					continue
+				}
+				amount := rng.Intn(400) + 1
+				_, err := repo.ApplyEntries([]entity.LedgerEntry{
+					{UserID: from, Type: entity.TransactionTypeDonation, Amount: -amount},
+					{UserID: to, Type: entity.TransactionTypeEarn, Amount: amount},
+				})
+				if err != nil && err != ErrInsufficientBalance {
+					t.Errorf("unexpected transfer error: %v", err)
+				}
+			}
+		}(int64(worker))
+	}
+	wg.Wait()
+
+	total := assertLedgerConsistent(t, db, userIDs)
+	assert.Equal(t, users*initialBalance, total, "transfers must not create or destroy coins")
+}
+
+func TestApplyEntries_ConcurrentDebitsNeverOverdraw(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewWalletRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	payer, payee := userIDs[0], userIDs[1]
+
+	_, err := repo.ApplyEntries([]entity.LedgerEntry{
+		{UserID: payer, Type: entity.TransactionTypeEarn, Amount: 100},
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, rejected := 0, 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.ApplyEntries([]entity.LedgerEntry{
+				{UserID: payer, Type: entity.TransactionTypeDonation, Amount: -10},
+				{UserID: payee, Type: entity.TransactionTypeEarn, Amount: 10},
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if err == ErrInsufficientBalance {
+				rejected++
+			} else {
+				t.Errorf("unexpected debit error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, succeeded)
+	assert.Equal(t, 40, rejected)
+
+	payerWallet, err := repo.GetOrCreateWallet(payer)
+	require.NoError(t, err)
+	assert.Equal(t, 0, payerWallet.Balance)
+
+	assertLedgerConsistent(t, db, userIDs)
+}
+
+func TestApplyEntries_FailedEntryRollsBackWholeTransfer(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewWalletRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	payer, payee := userIDs[0], userIDs[1]
+
+	_, err := repo.ApplyEntries([]entity.LedgerEntry{
+		{UserID: payer, Type: entity.TransactionTypeDonation, Amount: -50},
+		{UserID: payee, Type: entity.TransactionTypeEarn, Amount: 50},
+	})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+	payeeWallet, err := repo.GetOrCreateWallet(payee)
+	require.NoError(t, err)
+	assert.Equal(t, 0, payeeWallet.Balance, "credit must be rolled back with the failed debit")
+
+	assertLedgerConsistent(t, db, userIDs)
+}
+
+func TestPurchasePost_ConcurrentDuplicatePurchase(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewWalletRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	buyer, creator := userIDs[0], userIDs[1]
+
+	postID := uuid.New().String()
+	require.NoError(t, db.Exec(
+		"INSERT INTO posts (id, creator_id, title, type, media_url, price, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		postID, creator, "Paid post", "photo", "", 30, "approved",
+	).Error)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM posts WHERE id = ?", postID)
+	})
+
+	_, err := repo.ApplyEntries([]entity.LedgerEntry{
+		{UserID: buyer, Type: entity.TransactionTypeEarn, Amount: 300},
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.PurchasePost(postID, []entity.LedgerEntry{
+				{UserID: buyer, PostID: postID, Type: entity.TransactionTypePurchase, Amount: -30},
+				{UserID: creator, PostID: postID, Type: entity.TransactionTypeEarn, Amount: 30},
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if err != ErrAlreadyPurchased {
+				t.Errorf("unexpected purchase error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+
+	var purchases int
+	require.NoError(t, db.Raw("SELECT purchases FROM posts WHERE id = ?", postID).Scan(&purchases).Error)
+	assert.Equal(t, 1, purchases)
+
+	total := assertLedgerConsistent(t, db, userIDs)
+	assert.Equal(t, 300, total)
+}
+
+func TestRefund_ConcurrentRefundsReverseOnce(t *testing.T) {
+	db := setupLedgerTestDB(t)
+	repo := NewWalletRepository(db)
+
+	userIDs := createTestUsers(t, db, 2)
+	donor, creator := userIDs[0], userIDs[1]
+
+	_, err := repo.ApplyEntries([]entity.LedgerEntry{
+		{UserID: donor, Type: entity.TransactionTypeEarn, Amount: 100},
+	})
+	require.NoError(t, err)
+
+	donation, err := repo.ApplyEntries([]entity.LedgerEntry{
+		{UserID: donor, Type: entity.TransactionTypeDonation, Amount: -40},
+		{UserID: creator, Type: entity.TransactionTypeEarn, Amount: 40},
+	})
+	require.NoError(t, err)
+	require.Len(t, donation, 2)
+	assert.Equal(t, donation[0].TransferID, donation[1].TransferID)
+
+	transfer, err := repo.GetTransfer(donation[0].TransferID)
+	require.NoError(t, err)
+	require.Len(t, transfer, 2)
+
+	entries := make([]entity.LedgerEntry, len(transfer))
+	for i, transaction := range transfer {
+		entries[i] = entity.LedgerEntry{
+			UserID:                transaction.UserID,
+			Type:                  entity.TransactionTypeRefund,
+			Amount:                -transaction.Amount,
+			RefundedTransactionID: transaction.ID,
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Refund(entries)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if err != ErrAlreadyRefunded {
+				t.Errorf("unexpected refund error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+
+	donorWallet, err := repo.GetOrCreateWallet(donor)
+	require.NoError(t, err)
+	assert.Equal(t, 100, donorWallet.Balance)
+
+	creatorWallet, err := repo.GetOrCreateWallet(creator)
+	require.NoError(t, err)
+	assert.Equal(t, 0, creatorWallet.Balance)
+
+	total := assertLedgerConsistent(t, db, userIDs)
+	assert.Equal(t, 100, total)
+}
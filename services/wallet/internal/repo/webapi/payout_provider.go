@@ -0,0 +1,61 @@
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// PayoutProvider sends approved payouts to the creator outside the platform
+// (bank transfer, payment processor, ...). Send returns the provider's own
+// reference for the transfer so it can be reconciled later.
+type PayoutProvider interface {
+	Send(ctx context.Context, payout *entity.Payout) (string, error)
+}
+
+// LocalPayoutProvider pretends every payout was sent. It is used for local
+// development and tests until a real processor is wired in.
+type LocalPayoutProvider struct {
+	logger *logger.Logger
+
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func NewLocalPayoutProvider(logger *logger.Logger) *LocalPayoutProvider {
+	return &LocalPayoutProvider{logger: logger}
+}
+
+func (p *LocalPayoutProvider) Send(ctx context.Context, payout *entity.Payout) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return "", p.err
+	}
+
+	p.sent = append(p.sent, payout.ID)
+	reference := fmt.Sprintf("local-%s", uuid.New().String())
+	p.logger.Info("[LOCAL PAYOUT] Sent %d to creator %s (payout_id=%s, reference=%s)", payout.Amount, payout.CreatorID, payout.ID, reference)
+	return reference, nil
+}
+
+// FailWith makes every following Send return err; pass nil to recover.
+func (p *LocalPayoutProvider) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Sent returns the IDs of the payouts sent so far.
+func (p *LocalPayoutProvider) Sent() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.sent...)
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+)
+
+type FeeUseCase interface {
+	Split(creatorID string, amount int) (*entity.FeeSplit, error)
+	EarningEntries(payerID, creatorID, postID string, payerType entity.TransactionType, amount int) ([]entity.LedgerEntry, error)
+	GetCreatorFee(creatorID string) (percent int, overridden bool, err error)
+	SetCreatorFee(creatorID string, percent int) error
+	ClearCreatorFee(creatorID string) error
+}
+
+type feeUseCase struct {
+	feeRepo           persistent.FeeRepository
+	defaultFeePercent int
+	logger            *logger.Logger
+}
+
+func NewFeeUseCase(feeRepo persistent.FeeRepository, defaultFeePercent int, logger *logger.Logger) FeeUseCase {
+	if !validFeePercent(defaultFeePercent) {
+		logger.Error("Invalid platform fee %d%%, falling back to 0%%", defaultFeePercent)
+		defaultFeePercent = 0
+	}
+	return &feeUseCase{
+		feeRepo:           feeRepo,
+		defaultFeePercent: defaultFeePercent,
+		logger:            logger,
+	}
+}
+
+// Split applies the creator's fee override, or the platform default, to an
+// earning.
+func (uc *feeUseCase) Split(creatorID string, amount int) (*entity.FeeSplit, error) {
+	percent, _, err := uc.GetCreatorFee(creatorID)
+	if err != nil {
+		return nil, err
+	}
+	split := splitEarning(amount, percent)
+	return &split, nil
+}
+
+// EarningEntries builds the ledger entries for a payment to a creator: the
+// payer is debited the full amount, the creator is credited the net and the
+// platform wallet the fee. The creator entry is kept even when the fee takes
+// everything so the earning stays attributable to them. Every flow that pays
+// creators goes through here.
+func (uc *feeUseCase) EarningEntries(payerID, creatorID, postID string, payerType entity.TransactionType, amount int) ([]entity.LedgerEntry, error) {
+	split, err := uc.Split(creatorID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []entity.LedgerEntry{
+		{UserID: payerID, PostID: postID, Type: payerType, Amount: -split.Gross},
+		{UserID: creatorID, PostID: postID, Type: entity.TransactionTypeEarn, Amount: split.Net},
+	}
+	if split.Fee > 0 {
+		entries = append(entries, entity.LedgerEntry{UserID: entity.PlatformUserID, PostID: postID, Type: entity.TransactionTypeFee, Amount: split.Fee})
+	}
+	return entries, nil
+}
+
+func (uc *feeUseCase) GetCreatorFee(creatorID string) (int, bool, error) {
+	percent, found, err := uc.feeRepo.GetCreatorFeePercent(creatorID)
+	if err != nil {
+		uc.logger.Error("Failed to get creator fee: %v", err)
+		return 0, false, fmt.Errorf("failed to get creator fee: %w", err)
+	}
+	if !found {
+		return uc.defaultFeePercent, false, nil
+	}
+	return percent, true, nil
+}
+
+func (uc *feeUseCase) SetCreatorFee(creatorID string, percent int) error {
+	if !validFeePercent(percent) {
+		return fmt.Errorf("fee percent must be between 0 and 100")
+	}
+	if err := uc.feeRepo.SetCreatorFeePercent(creatorID, percent); err != nil {
+		if errors.Is(err, persistent.ErrCreatorNotFound) {
+			return fmt.Errorf("creator not found")
+		}
+		uc.logger.Error("Failed to set creator fee: %v", err)
+		return fmt.Errorf("failed to set creator fee: %w", err)
+	}
+	return nil
+}
+
+func (uc *feeUseCase) ClearCreatorFee(creatorID string) error {
+	if err := uc.feeRepo.DeleteCreatorFeePercent(creatorID); err != nil {
+		uc.logger.Error("Failed to clear creator fee: %v", err)
+		return fmt.Errorf("failed to clear creator fee: %w", err)
+	}
+	return nil
+}
+
+// splitEarning rounds the fee down, so fractions always go to the creator.
+func splitEarning(amount, percent int) entity.FeeSplit {
+	fee := amount * percent / 100
+	return entity.FeeSplit{
+		Gross:      amount,
+		Net:        amount - fee,
+		Fee:        fee,
+		FeePercent: percent,
+	}
+}
+
+func validFeePercent(percent int) bool {
+	return percent >= 0 && percent <= 100
+}
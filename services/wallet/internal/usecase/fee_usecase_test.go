@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeeRepository struct {
+	overrides map[string]int
+}
+
+func (r *fakeFeeRepository) GetCreatorFeePercent(creatorID string) (int, bool, error) {
+	percent, ok := r.overrides[creatorID]
+	return percent, ok, nil
+}
+
+func (r *fakeFeeRepository) SetCreatorFeePercent(creatorID string, percent int) error {
+	if r.overrides == nil {
+		r.overrides = map[string]int{}
+	}
+	r.overrides[creatorID] = percent
+	return nil
+}
+
+func (r *fakeFeeRepository) DeleteCreatorFeePercent(creatorID string) error {
+	delete(r.overrides, creatorID)
+	return nil
+}
+
+func TestSplitEarning(t *testing.T) {
+	assert.Equal(t, entity.FeeSplit{Gross: 100, Net: 80, Fee: 20, FeePercent: 20}, splitEarning(100, 20))
+	// Fractions of a coin stay with the creator
+	assert.Equal(t, entity.FeeSplit{Gross: 9, Net: 9, Fee: 0, FeePercent: 10}, splitEarning(9, 10))
+	assert.Equal(t, entity.FeeSplit{Gross: 15, Net: 14, Fee: 1, FeePercent: 10}, splitEarning(15, 10))
+	assert.Equal(t, entity.FeeSplit{Gross: 50, Net: 50, Fee: 0, FeePercent: 0}, splitEarning(50, 0))
+}
+
+func TestEarningEntries(t *testing.T) {
+	uc := NewFeeUseCase(&fakeFeeRepository{}, 20, logger.New())
+
+	entries, err := uc.EarningEntries("viewer", "creator", "post", entity.TransactionTypeDonation, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.LedgerEntry{
+		{UserID: "viewer", PostID: "post", Type: entity.TransactionTypeDonation, Amount: -100},
+		{UserID: "creator", PostID: "post", Type: entity.TransactionTypeEarn, Amount: 80},
+		{UserID: entity.PlatformUserID, PostID: "post", Type: entity.TransactionTypeFee, Amount: 20},
+	}, entries)
+
+	sum := 0
+	for _, entry := range entries {
+		sum += entry.Amount
+	}
+	assert.Zero(t, sum, "a split must not create or destroy money")
+
+	// An override of zero drops the platform entry entirely
+	require.NoError(t, uc.SetCreatorFee("creator", 0))
+	entries, err = uc.EarningEntries("viewer", "creator", "post", entity.TransactionTypePurchase, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.LedgerEntry{
+		{UserID: "viewer", PostID: "post", Type: entity.TransactionTypePurchase, Amount: -100},
+		{UserID: "creator", PostID: "post", Type: entity.TransactionTypeEarn, Amount: 100},
+	}, entries)
+
+	require.NoError(t, uc.ClearCreatorFee("creator"))
+	percent, overridden, err := uc.GetCreatorFee("creator")
+	require.NoError(t, err)
+	assert.Equal(t, 20, percent)
+	assert.False(t, overridden)
+
+	assert.EqualError(t, uc.SetCreatorFee("creator", 101), "fee percent must be between 0 and 100")
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+)
+
+// IdempotencyWindow is how long a completed response is replayed for retries
+// that carry the same Idempotency-Key.
+const IdempotencyWindow = 24 * time.Hour
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyUseCase interface {
+	Begin(userID, key, requestHash string) (*entity.IdempotencyRecord, error)
+	Complete(userID, key, transactionID string, statusCode int, responseBody []byte) error
+	Release(userID, key string)
+}
+
+type idempotencyUseCase struct {
+	idempotencyRepo persistent.IdempotencyRepository
+	logger          *logger.Logger
+}
+
+func NewIdempotencyUseCase(idempotencyRepo persistent.IdempotencyRepository, logger *logger.Logger) IdempotencyUseCase {
+	return &idempotencyUseCase{
+		idempotencyRepo: idempotencyRepo,
+		logger:          logger,
+	}
+}
+
+// Begin reserves the key for a new request and returns nil, or returns the
+// completed record whose response should be replayed to the client.
+func (uc *idempotencyUseCase) Begin(userID, key, requestHash string) (*entity.IdempotencyRecord, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("idempotency key is too long")
+	}
+
+	record, created, err := uc.idempotencyRepo.Reserve(userID, key, requestHash, time.Now().Add(-IdempotencyWindow))
+	if err != nil {
+		uc.logger.Error("Failed to reserve idempotency key: %v", err)
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if created {
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, fmt.Errorf("idempotency key was used for a different request")
+	}
+	if !record.IsCompleted() {
+		return nil, fmt.Errorf("request with this idempotency key is still in progress")
+	}
+
+	uc.logger.Info("Replaying response for idempotency key %s (user %s, transaction %s)", key, userID, record.TransactionID)
+	return record, nil
+}
+
+func (uc *idempotencyUseCase) Complete(userID, key, transactionID string, statusCode int, responseBody []byte) error {
+	if err := uc.idempotencyRepo.Complete(userID, key, transactionID, statusCode, string(responseBody)); err != nil {
+		uc.logger.Error("Failed to store idempotent response: %v", err)
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release frees a reserved key after the request failed, so the client can retry.
+func (uc *idempotencyUseCase) Release(userID, key string) {
+	if err := uc.idempotencyRepo.Release(userID, key); err != nil {
+		uc.logger.Error("Failed to release idempotency key %s: %v", key, err)
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+	"lick-scroll/services/wallet/internal/repo/webapi"
+)
+
+// payoutSendTimeout bounds a single call to the payout provider.
+const payoutSendTimeout = 30 * time.Second
+
+type PayoutUseCase interface {
+	RequestPayout(creatorID string, amount int) (*entity.Payout, error)
+	GetPayouts(creatorID string, limit, offset int) ([]*entity.Payout, error)
+	GetPendingPayouts(limit, offset int) ([]*entity.Payout, error)
+	ApprovePayout(moderatorID, payoutID string) (*entity.Payout, error)
+	RejectPayout(moderatorID, payoutID, reason string) (*entity.Payout, error)
+}
+
+type payoutUseCase struct {
+	payoutRepo persistent.PayoutRepository
+	provider   webapi.PayoutProvider
+	logger     *logger.Logger
+}
+
+func NewPayoutUseCase(payoutRepo persistent.PayoutRepository, provider webapi.PayoutProvider, logger *logger.Logger) PayoutUseCase {
+	return &payoutUseCase{
+		payoutRepo: payoutRepo,
+		provider:   provider,
+		logger:     logger,
+	}
+}
+
+func (uc *payoutUseCase) RequestPayout(creatorID string, amount int) (*entity.Payout, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	payout, err := uc.payoutRepo.Request(creatorID, amount)
+	if err != nil {
+		if errors.Is(err, persistent.ErrInsufficientBalance) {
+			return nil, fmt.Errorf("insufficient balance")
+		}
+		uc.logger.Error("Failed to request payout: %v", err)
+		return nil, fmt.Errorf("failed to request payout: %w", err)
+	}
+	return payout, nil
+}
+
+func (uc *payoutUseCase) GetPayouts(creatorID string, limit, offset int) ([]*entity.Payout, error) {
+	payouts, err := uc.payoutRepo.ListByCreator(creatorID, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to get payouts: %v", err)
+		return nil, fmt.Errorf("failed to get payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+func (uc *payoutUseCase) GetPendingPayouts(limit, offset int) ([]*entity.Payout, error) {
+	payouts, err := uc.payoutRepo.ListByStatus(entity.PayoutStatusPending, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to get pending payouts: %v", err)
+		return nil, fmt.Errorf("failed to get pending payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+// ApprovePayout claims the payout and hands it to the provider. If the
+// provider fails the payout is marked failed and the held funds go back to
+// the creator's balance.
+func (uc *payoutUseCase) ApprovePayout(moderatorID, payoutID string) (*entity.Payout, error) {
+	if _, err := uc.payoutRepo.Get(payoutID); err != nil {
+		return nil, fmt.Errorf("payout not found")
+	}
+
+	payout, err := uc.payoutRepo.StartProcessing(payoutID, moderatorID)
+	if err != nil {
+		if errors.Is(err, persistent.ErrPayoutChanged) {
+			return nil, fmt.Errorf("payout is not pending")
+		}
+		uc.logger.Error("Failed to start payout: %v", err)
+		return nil, fmt.Errorf("failed to start payout: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), payoutSendTimeout)
+	defer cancel()
+
+	reference, sendErr := uc.provider.Send(ctx, payout)
+	if sendErr != nil {
+		uc.logger.Error("Payout provider failed for payout %s: %v", payoutID, sendErr)
+		if _, err := uc.payoutRepo.Release(payoutID, entity.PayoutStatusProcessing, entity.PayoutStatusFailed, "", sendErr.Error()); err != nil {
+			uc.logger.Error("Failed to release funds of failed payout %s: %v", payoutID, err)
+		}
+		return nil, fmt.Errorf("payout provider failed")
+	}
+
+	payout, err = uc.payoutRepo.Complete(payoutID, reference)
+	if err != nil {
+		// The money was sent; the payout stays processing until reconciled
+		uc.logger.Error("Failed to complete payout %s (provider reference %s): %v", payoutID, reference, err)
+		return nil, fmt.Errorf("failed to complete payout: %w", err)
+	}
+
+	uc.logger.Info("Moderator %s approved payout %s of %d to creator %s", moderatorID, payout.ID, payout.Amount, payout.CreatorID)
+	return payout, nil
+}
+
+func (uc *payoutUseCase) RejectPayout(moderatorID, payoutID, reason string) (*entity.Payout, error) {
+	if _, err := uc.payoutRepo.Get(payoutID); err != nil {
+		return nil, fmt.Errorf("payout not found")
+	}
+
+	payout, err := uc.payoutRepo.Release(payoutID, entity.PayoutStatusPending, entity.PayoutStatusRejected, moderatorID, reason)
+	if err != nil {
+		if errors.Is(err, persistent.ErrPayoutChanged) {
+			return nil, fmt.Errorf("payout is not pending")
+		}
+		uc.logger.Error("Failed to reject payout: %v", err)
+		return nil, fmt.Errorf("failed to reject payout: %w", err)
+	}
+
+	uc.logger.Info("Moderator %s rejected payout %s: %s", moderatorID, payout.ID, reason)
+	return payout, nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+	"lick-scroll/services/wallet/internal/repo/webapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePayoutRepository keeps payouts in memory and tracks spendable and held
+// funds per creator the way the real repository moves them.
+type fakePayoutRepository struct {
+	payouts  map[string]*entity.Payout
+	balances map[string]int
+	held     map[string]int
+}
+
+func newFakePayoutRepository(balances map[string]int) *fakePayoutRepository {
+	return &fakePayoutRepository{
+		payouts:  map[string]*entity.Payout{},
+		balances: balances,
+		held:     map[string]int{},
+	}
+}
+
+func (r *fakePayoutRepository) Request(creatorID string, amount int) (*entity.Payout, error) {
+	if r.balances[creatorID] < amount {
+		return nil, persistent.ErrInsufficientBalance
+	}
+	r.balances[creatorID] -= amount
+	r.held[creatorID] += amount
+
+	payout := &entity.Payout{ID: "payout-" + creatorID, CreatorID: creatorID, Amount: amount, Status: entity.PayoutStatusPending}
+	r.payouts[payout.ID] = payout
+	return payout, nil
+}
+
+func (r *fakePayoutRepository) Get(payoutID string) (*entity.Payout, error) {
+	payout, ok := r.payouts[payoutID]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return payout, nil
+}
+
+func (r *fakePayoutRepository) ListByCreator(creatorID string, limit, offset int) ([]*entity.Payout, error) {
+	return nil, nil
+}
+
+func (r *fakePayoutRepository) ListByStatus(status entity.PayoutStatus, limit, offset int) ([]*entity.Payout, error) {
+	return nil, nil
+}
+
+func (r *fakePayoutRepository) StartProcessing(payoutID, reviewerID string) (*entity.Payout, error) {
+	payout := r.payouts[payoutID]
+	if payout.Status != entity.PayoutStatusPending {
+		return nil, persistent.ErrPayoutChanged
+	}
+	payout.Status = entity.PayoutStatusProcessing
+	payout.ReviewerID = reviewerID
+	return payout, nil
+}
+
+func (r *fakePayoutRepository) Complete(payoutID, providerReference string) (*entity.Payout, error) {
+	payout := r.payouts[payoutID]
+	if payout.Status != entity.PayoutStatusProcessing {
+		return nil, persistent.ErrPayoutChanged
+	}
+	payout.Status = entity.PayoutStatusCompleted
+	payout.ProviderReference = providerReference
+	r.held[payout.CreatorID] -= payout.Amount
+	return payout, nil
+}
+
+func (r *fakePayoutRepository) Release(payoutID string, from, to entity.PayoutStatus, reviewerID, reason string) (*entity.Payout, error) {
+	payout := r.payouts[payoutID]
+	if payout.Status != from {
+		return nil, persistent.ErrPayoutChanged
+	}
+	payout.Status = to
+	payout.Reason = reason
+	r.held[payout.CreatorID] -= payout.Amount
+	r.balances[payout.CreatorID] += payout.Amount
+	return payout, nil
+}
+
+func TestApprovePayout(t *testing.T) {
+	repo := newFakePayoutRepository(map[string]int{"creator": 500})
+	provider := webapi.NewLocalPayoutProvider(logger.New())
+	uc := NewPayoutUseCase(repo, provider, logger.New())
+
+	_, err := uc.RequestPayout("creator", 800)
+	assert.EqualError(t, err, "insufficient balance")
+
+	payout, err := uc.RequestPayout("creator", 300)
+	require.NoError(t, err)
+	assert.Equal(t, 200, repo.balances["creator"])
+	assert.Equal(t, 300, repo.held["creator"])
+
+	payout, err = uc.ApprovePayout("moderator", payout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PayoutStatusCompleted, payout.Status)
+	assert.NotEmpty(t, payout.ProviderReference)
+	assert.Equal(t, []string{payout.ID}, provider.Sent())
+	assert.Equal(t, 200, repo.balances["creator"])
+	assert.Zero(t, repo.held["creator"])
+
+	_, err = uc.ApprovePayout("moderator", payout.ID)
+	assert.EqualError(t, err, "payout is not pending")
+	_, err = uc.RejectPayout("moderator", payout.ID, "too late")
+	assert.EqualError(t, err, "payout is not pending")
+	assert.Len(t, provider.Sent(), 1)
+
+	_, err = uc.ApprovePayout("moderator", "missing")
+	assert.EqualError(t, err, "payout not found")
+}
+
+func TestApprovePayout_ProviderFailureReleasesFunds(t *testing.T) {
+	repo := newFakePayoutRepository(map[string]int{"creator": 500})
+	provider := webapi.NewLocalPayoutProvider(logger.New())
+	provider.FailWith(errors.New("bank unavailable"))
+	uc := NewPayoutUseCase(repo, provider, logger.New())
+
+	payout, err := uc.RequestPayout("creator", 300)
+	require.NoError(t, err)
+
+	_, err = uc.ApprovePayout("moderator", payout.ID)
+	assert.EqualError(t, err, "payout provider failed")
+
+	assert.Equal(t, entity.PayoutStatusFailed, repo.payouts[payout.ID].Status)
+	assert.Equal(t, "bank unavailable", repo.payouts[payout.ID].Reason)
+	assert.Equal(t, 500, repo.balances["creator"])
+	assert.Zero(t, repo.held["creator"])
+}
+
+func TestRejectPayout(t *testing.T) {
+	repo := newFakePayoutRepository(map[string]int{"creator": 500})
+	uc := NewPayoutUseCase(repo, webapi.NewLocalPayoutProvider(logger.New()), logger.New())
+
+	payout, err := uc.RequestPayout("creator", 300)
+	require.NoError(t, err)
+
+	payout, err = uc.RejectPayout("moderator", payout.ID, "suspicious activity")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PayoutStatusRejected, payout.Status)
+	assert.Equal(t, 500, repo.balances["creator"])
+	assert.Zero(t, repo.held["creator"])
+
+	_, err = uc.ApprovePayout("moderator", payout.ID)
+	assert.EqualError(t, err, "payout is not pending")
+}
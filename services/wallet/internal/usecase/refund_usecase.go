@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/queue"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+)
+
+// refundableTypes lists the transaction types a moderator may reverse.
+var refundableTypes = map[entity.TransactionType]bool{
+	entity.TransactionTypeDonation: true,
+}
+
+type RefundUseCase interface {
+	RefundTransaction(moderatorID, transactionID string) ([]*entity.Transaction, error)
+}
+
+type refundUseCase struct {
+	walletRepo  persistent.WalletRepository
+	queueClient *queue.Client
+	logger      *logger.Logger
+}
+
+func NewRefundUseCase(walletRepo persistent.WalletRepository, queueClient *queue.Client, logger *logger.Logger) RefundUseCase {
+	return &refundUseCase{
+		walletRepo:  walletRepo,
+		queueClient: queueClient,
+		logger:      logger,
+	}
+}
+
+// RefundTransaction reverses the whole transfer the given transaction belongs
+// to, so refunding either the viewer's donation or the creator's earning
+// restores both balances. Every reversing row is a refund transaction linked
+// to the row it reverses.
+func (uc *refundUseCase) RefundTransaction(moderatorID, transactionID string) ([]*entity.Transaction, error) {
+	transaction, err := uc.walletRepo.GetTransaction(transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	// Rows written before transfers were recorded can't be matched to their counterpart
+	if transaction.TransferID == "" {
+		return nil, fmt.Errorf("transaction cannot be refunded")
+	}
+
+	transfer, err := uc.walletRepo.GetTransfer(transaction.TransferID)
+	if err != nil {
+		uc.logger.Error("Failed to get transfer: %v", err)
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	original := findRefundable(transfer)
+	if original == nil {
+		return nil, fmt.Errorf("transaction cannot be refunded")
+	}
+
+	entries := make([]entity.LedgerEntry, len(transfer))
+	for i, t := range transfer {
+		entries[i] = entity.LedgerEntry{
+			UserID:                t.UserID,
+			PostID:                t.PostID,
+			Type:                  entity.TransactionTypeRefund,
+			Amount:                -t.Amount,
+			RefundedTransactionID: t.ID,
+		}
+	}
+
+	refunds, err := uc.walletRepo.Refund(entries)
+	if err != nil {
+		switch {
+		case errors.Is(err, persistent.ErrAlreadyRefunded):
+			return nil, fmt.Errorf("transaction already refunded")
+		case errors.Is(err, persistent.ErrInsufficientBalance):
+			return nil, fmt.Errorf("insufficient balance to reverse transaction")
+		}
+		uc.logger.Error("Failed to process refund: %v", err)
+		return nil, fmt.Errorf("failed to process refund: %w", err)
+	}
+
+	uc.logger.Info("Moderator %s refunded %s transaction %s", moderatorID, original.Type, original.ID)
+
+	if uc.queueClient != nil {
+		for _, refund := range refunds {
+			// The platform account returns its fee but has nobody to notify
+			if refund.UserID == entity.PlatformUserID {
+				continue
+			}
+			go uc.publishRefundNotification(refund, original)
+		}
+	}
+
+	return refunds, nil
+}
+
+func (uc *refundUseCase) publishRefundNotification(refund, original *entity.Transaction) {
+	task := map[string]interface{}{
+		"type":                  "refund",
+		"user_id":               refund.UserID,
+		"transaction_id":        original.ID,
+		"transaction_type":      string(original.Type),
+		"refund_transaction_id": refund.ID,
+		"post_id":               refund.PostID,
+		"amount":                refund.Amount,
+		"priority":              5,
+	}
+
+	uc.logger.Info("[NOTIFICATION QUEUE] Publishing refund task to RabbitMQ: transaction_id=%s, user_id=%s", original.ID, refund.UserID)
+	if err := uc.queueClient.PublishNotificationTask(task); err != nil {
+		uc.logger.Error("[NOTIFICATION QUEUE] Failed to publish refund task to RabbitMQ: %v (transaction_id=%s, user_id=%s)", err, original.ID, refund.UserID)
+	}
+}
+
+// findRefundable returns the row that makes a transfer refundable, or nil.
+func findRefundable(transfer []*entity.Transaction) *entity.Transaction {
+	for _, transaction := range transfer {
+		if refundableTypes[transaction.Type] {
+			return transaction
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/pkg/timeline"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// renewalBatchSize bounds how many due subscriptions are loaded at once.
+const renewalBatchSize = 100
+
+type SubscriptionUseCase interface {
+	SubscribeToTier(viewerID, tierID string) (*entity.Subscription, error)
+	RenewDueSubscriptions(now time.Time) (renewed, lapsed int, err error)
+}
+
+type subscriptionUseCase struct {
+	subscriptionRepo persistent.SubscriptionRepository
+	feeUseCase       FeeUseCase
+	redisClient      *redis.Client
+	logger           *logger.Logger
+}
+
+func NewSubscriptionUseCase(subscriptionRepo persistent.SubscriptionRepository, feeUseCase FeeUseCase, redisClient *redis.Client, logger *logger.Logger) SubscriptionUseCase {
+	return &subscriptionUseCase{
+		subscriptionRepo: subscriptionRepo,
+		feeUseCase:       feeUseCase,
+		redisClient:      redisClient,
+		logger:           logger,
+	}
+}
+
+func (uc *subscriptionUseCase) SubscribeToTier(viewerID, tierID string) (*entity.Subscription, error) {
+	tier, err := uc.subscriptionRepo.GetTier(tierID)
+	if err != nil || tier.Deleted {
+		return nil, fmt.Errorf("subscription tier not found")
+	}
+
+	if tier.CreatorID == viewerID {
+		return nil, fmt.Errorf("cannot subscribe to yourself")
+	}
+
+	entries, err := uc.subscriptionEntries(viewerID, tier)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.subscriptionRepo.Subscribe(viewerID, tier, time.Now().AddDate(0, 1, 0), entries)
+	if err != nil {
+		switch {
+		case errors.Is(err, persistent.ErrInsufficientBalance):
+			return nil, fmt.Errorf("insufficient balance")
+		case errors.Is(err, persistent.ErrAlreadySubscribed):
+			return nil, fmt.Errorf("already subscribed")
+		}
+		uc.logger.Error("Failed to process subscription: %v", err)
+		return nil, fmt.Errorf("failed to process subscription: %w", err)
+	}
+
+	// The timeline was built without this creator's subscriber-only posts;
+	// dropping it makes the feed rebuild it on the next read
+	uc.redisClient.Del(context.Background(), timeline.Key(viewerID))
+
+	return subscription, nil
+}
+
+// RenewDueSubscriptions charges every paid subscription whose period ended by
+// now for the next period at the tier's current price. Subscriptions whose
+// viewer can't pay, or whose tier was deleted, lapse to follow-only; those the
+// viewer cancelled are removed.
+func (uc *subscriptionUseCase) RenewDueSubscriptions(now time.Time) (renewed, lapsed int, err error) {
+	for {
+		due, err := uc.subscriptionRepo.ListDue(now, renewalBatchSize)
+		if err != nil {
+			uc.logger.Error("Failed to list due subscriptions: %v", err)
+			return renewed, lapsed, fmt.Errorf("failed to list due subscriptions: %w", err)
+		}
+		if len(due) == 0 {
+			return renewed, lapsed, nil
+		}
+
+		progress := false
+		for _, subscription := range due {
+			switch uc.renew(subscription, now) {
+			case renewalRenewed:
+				renewed++
+				progress = true
+			case renewalLapsed:
+				lapsed++
+				progress = true
+			case renewalSkipped:
+				progress = true
+			}
+		}
+
+		// Whatever is left failed with unexpected errors; retry on the next run
+		if !progress {
+			return renewed, lapsed, nil
+		}
+	}
+}
+
+type renewalOutcome int
+
+const (
+	renewalFailed renewalOutcome = iota
+	renewalRenewed
+	renewalLapsed
+	renewalSkipped
+)
+
+func (uc *subscriptionUseCase) renew(subscription *entity.Subscription, now time.Time) renewalOutcome {
+	if subscription.CancelAtPeriodEnd {
+		return uc.lapse(subscription, "cancelled by viewer")
+	}
+
+	tier, err := uc.subscriptionRepo.GetTier(subscription.TierID)
+	if err != nil || tier.Deleted {
+		return uc.lapse(subscription, "tier no longer exists")
+	}
+
+	entries, err := uc.subscriptionEntries(subscription.ViewerID, tier)
+	if err != nil {
+		uc.logger.Error("Failed to renew subscription %s: %v", subscription.ID, err)
+		return renewalFailed
+	}
+
+	periodEnd := nextPeriodEnd(*subscription.CurrentPeriodEnd, now)
+	err = uc.subscriptionRepo.Renew(subscription, periodEnd, entries)
+	switch {
+	case err == nil:
+		uc.logger.Info("Renewed subscription %s until %s", subscription.ID, periodEnd.Format(time.RFC3339))
+		return renewalRenewed
+	case errors.Is(err, persistent.ErrInsufficientBalance):
+		return uc.lapse(subscription, "insufficient balance")
+	case errors.Is(err, persistent.ErrSubscriptionChanged):
+		return renewalSkipped
+	}
+
+	uc.logger.Error("Failed to renew subscription %s: %v", subscription.ID, err)
+	return renewalFailed
+}
+
+func (uc *subscriptionUseCase) lapse(subscription *entity.Subscription, reason string) renewalOutcome {
+	if err := uc.subscriptionRepo.Lapse(subscription); err != nil {
+		if errors.Is(err, persistent.ErrSubscriptionChanged) {
+			return renewalSkipped
+		}
+		uc.logger.Error("Failed to lapse subscription %s: %v", subscription.ID, err)
+		return renewalFailed
+	}
+
+	uc.logger.Info("Subscription %s lapsed: %s", subscription.ID, reason)
+	uc.redisClient.Del(context.Background(), timeline.Key(subscription.ViewerID))
+	return renewalLapsed
+}
+
+func (uc *subscriptionUseCase) subscriptionEntries(viewerID string, tier *entity.SubscriptionTier) ([]entity.LedgerEntry, error) {
+	return uc.feeUseCase.EarningEntries(viewerID, tier.CreatorID, "", entity.TransactionTypeSubscription, tier.Price)
+}
+
+// nextPeriodEnd extends a monthly period from its previous end. Periods that
+// ended long ago (e.g. the job was down) restart from now instead of charging
+// for the months that were missed.
+func nextPeriodEnd(previousEnd, now time.Time) time.Time {
+	next := previousEnd.AddDate(0, 1, 0)
+	if !next.After(now) {
+		next = now.AddDate(0, 1, 0)
+	}
+	return next
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubscriptionRepository keeps subscriptions in memory and debits viewers
+// from a balance map, mirroring the conditional updates of the real repository.
+type fakeSubscriptionRepository struct {
+	tiers         map[string]*entity.SubscriptionTier
+	subscriptions map[string]*entity.Subscription
+	balances      map[string]int
+}
+
+func (r *fakeSubscriptionRepository) GetTier(tierID string) (*entity.SubscriptionTier, error) {
+	tier, ok := r.tiers[tierID]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return tier, nil
+}
+
+func (r *fakeSubscriptionRepository) Subscribe(viewerID string, tier *entity.SubscriptionTier, periodEnd time.Time, entries []entity.LedgerEntry) (*entity.Subscription, error) {
+	return nil, assert.AnError
+}
+
+func (r *fakeSubscriptionRepository) ListDue(now time.Time, limit int) ([]*entity.Subscription, error) {
+	var due []*entity.Subscription
+	for _, subscription := range r.subscriptions {
+		if subscription.Type == entity.SubscriptionTypePaid && !subscription.CurrentPeriodEnd.After(now) {
+			copied := *subscription
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeSubscriptionRepository) Renew(subscription *entity.Subscription, periodEnd time.Time, entries []entity.LedgerEntry) error {
+	stored := r.subscriptions[subscription.ID]
+	if stored.Type != entity.SubscriptionTypePaid || stored.CancelAtPeriodEnd || !stored.CurrentPeriodEnd.Equal(*subscription.CurrentPeriodEnd) {
+		return persistent.ErrSubscriptionChanged
+	}
+	for _, entry := range entries {
+		if r.balances[entry.UserID]+entry.Amount < 0 {
+			return persistent.ErrInsufficientBalance
+		}
+	}
+	for _, entry := range entries {
+		r.balances[entry.UserID] += entry.Amount
+	}
+	stored.CurrentPeriodEnd = &periodEnd
+	return nil
+}
+
+func (r *fakeSubscriptionRepository) Lapse(subscription *entity.Subscription) error {
+	if subscription.CancelAtPeriodEnd {
+		delete(r.subscriptions, subscription.ID)
+		return nil
+	}
+	r.subscriptions[subscription.ID].Type = entity.SubscriptionTypeLapsed
+	return nil
+}
+
+func (r *fakeSubscriptionRepository) HasActiveSubscription(viewerID, creatorID string) (bool, error) {
+	return false, nil
+}
+
+func TestRenewDueSubscriptions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	periodEnd := now.Add(-time.Hour)
+	future := now.AddDate(0, 0, 10)
+
+	repo := &fakeSubscriptionRepository{
+		tiers: map[string]*entity.SubscriptionTier{
+			"gold":    {ID: "gold", CreatorID: "creator", Price: 100},
+			"retired": {ID: "retired", CreatorID: "creator", Price: 50, Deleted: true},
+		},
+		subscriptions: map[string]*entity.Subscription{
+			"renews":    {ID: "renews", ViewerID: "rich", CreatorID: "creator", TierID: "gold", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &periodEnd},
+			"broke":     {ID: "broke", ViewerID: "poor", CreatorID: "creator", TierID: "gold", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &periodEnd},
+			"no-tier":   {ID: "no-tier", ViewerID: "rich", CreatorID: "creator", TierID: "retired", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &periodEnd},
+			"cancelled": {ID: "cancelled", ViewerID: "rich", CreatorID: "other", TierID: "gold", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &periodEnd, CancelAtPeriodEnd: true},
+			"not-due":   {ID: "not-due", ViewerID: "poor", CreatorID: "creator", TierID: "gold", Type: entity.SubscriptionTypePaid, CurrentPeriodEnd: &future},
+			"free-only": {ID: "free-only", ViewerID: "poor", CreatorID: "creator", Type: entity.SubscriptionTypeFree},
+		},
+		balances: map[string]int{"rich": 150, "poor": 20},
+	}
+
+	// Nothing listens on this address; cache invalidation failures are ignored
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	feeUseCase := NewFeeUseCase(&fakeFeeRepository{}, 10, logger.New())
+	uc := NewSubscriptionUseCase(repo, feeUseCase, redisClient, logger.New())
+
+	renewed, lapsed, err := uc.RenewDueSubscriptions(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed)
+	assert.Equal(t, 3, lapsed)
+
+	assert.Equal(t, entity.SubscriptionTypePaid, repo.subscriptions["renews"].Type)
+	assert.Equal(t, periodEnd.AddDate(0, 1, 0), *repo.subscriptions["renews"].CurrentPeriodEnd)
+	assert.Equal(t, entity.SubscriptionTypeLapsed, repo.subscriptions["broke"].Type)
+	assert.Equal(t, entity.SubscriptionTypeLapsed, repo.subscriptions["no-tier"].Type)
+	assert.Equal(t, entity.SubscriptionTypePaid, repo.subscriptions["not-due"].Type)
+	assert.NotContains(t, repo.subscriptions, "cancelled", "cancelled subscriptions are removed, not charged")
+
+	assert.Equal(t, 50, repo.balances["rich"])
+	assert.Equal(t, 20, repo.balances["poor"])
+	assert.Equal(t, 90, repo.balances["creator"])
+	assert.Equal(t, 10, repo.balances[entity.PlatformUserID])
+
+	// A second run finds nothing left to charge
+	renewed, lapsed, err = uc.RenewDueSubscriptions(now)
+	require.NoError(t, err)
+	assert.Zero(t, renewed)
+	assert.Zero(t, lapsed)
+}
+
+func TestNextPeriodEnd(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	onTime := now.Add(-time.Minute)
+	assert.Equal(t, onTime.AddDate(0, 1, 0), nextPeriodEnd(onTime, now))
+
+	// A period missed entirely is not charged retroactively
+	longAgo := now.AddDate(0, -3, 0)
+	assert.Equal(t, now.AddDate(0, 1, 0), nextPeriodEnd(longAgo, now))
+}
@@ -1,34 +1,35 @@
 package usecase
 
 import (
-	"context"
+	"errors"
 	"fmt"
 
 	"lick-scroll/pkg/logger"
 	"lick-scroll/services/wallet/internal/entity"
 	"lick-scroll/services/wallet/internal/repo/persistent"
-
-	"github.com/redis/go-redis/v9"
 )
 
 type WalletUseCase interface {
 	GetWallet(userID string) (*entity.Wallet, error)
-	TopUp(userID string, amount int) (*entity.Wallet, error)
-	DonateToPost(userID, postID string, amount int) (*entity.Wallet, error)
+	TopUp(userID string, amount int) (*entity.Wallet, *entity.Transaction, error)
+	DonateToPost(userID, postID string, amount int) (*entity.Wallet, *entity.Transaction, error)
+	PurchasePost(userID, postID string) (*entity.Wallet, error)
 	GetTransactions(userID string, limit, offset int) ([]*entity.Transaction, error)
 }
 
 type walletUseCase struct {
-	walletRepo  persistent.WalletRepository
-	redisClient *redis.Client
-	logger      *logger.Logger
+	walletRepo       persistent.WalletRepository
+	subscriptionRepo persistent.SubscriptionRepository
+	feeUseCase       FeeUseCase
+	logger           *logger.Logger
 }
 
-func NewWalletUseCase(walletRepo persistent.WalletRepository, redisClient *redis.Client, logger *logger.Logger) WalletUseCase {
+func NewWalletUseCase(walletRepo persistent.WalletRepository, subscriptionRepo persistent.SubscriptionRepository, feeUseCase FeeUseCase, logger *logger.Logger) WalletUseCase {
 	return &walletUseCase{
-		walletRepo:  walletRepo,
-		redisClient: redisClient,
-		logger:      logger,
+		walletRepo:       walletRepo,
+		subscriptionRepo: subscriptionRepo,
+		feeUseCase:       feeUseCase,
+		logger:           logger,
 	}
 }
 
@@ -41,99 +42,108 @@ func (uc *walletUseCase) GetWallet(userID string) (*entity.Wallet, error) {
 	return wallet, nil
 }
 
-func (uc *walletUseCase) TopUp(userID string, amount int) (*entity.Wallet, error) {
-	wallet, err := uc.walletRepo.GetOrCreateWallet(userID)
+func (uc *walletUseCase) TopUp(userID string, amount int) (*entity.Wallet, *entity.Transaction, error) {
+	entries := []entity.LedgerEntry{
+		{UserID: userID, Type: entity.TransactionTypeEarn, Amount: amount},
+	}
+	transactions, err := uc.walletRepo.ApplyEntries(entries)
 	if err != nil {
-		uc.logger.Error("Failed to get wallet: %v", err)
-		return nil, fmt.Errorf("failed to get wallet: %w", err)
+		uc.logger.Error("Failed to top up wallet: %v", err)
+		return nil, nil, fmt.Errorf("failed to top up wallet: %w", err)
 	}
 
-	balanceBefore := wallet.Balance
-	wallet.Balance += amount
-	if err := uc.walletRepo.UpdateWallet(wallet); err != nil {
-		uc.logger.Error("Failed to update wallet: %v", err)
-		return nil, fmt.Errorf("failed to top up wallet: %w", err)
+	wallet, err := uc.GetWallet(userID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return wallet, findTransaction(transactions, userID), nil
+}
 
-	transaction := &entity.Transaction{
-		UserID:        userID,
-		Type:          entity.TransactionTypeEarn,
-		Amount:        amount,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  wallet.Balance,
+func (uc *walletUseCase) DonateToPost(userID, postID string, amount int) (*entity.Wallet, *entity.Transaction, error) {
+	post, err := uc.walletRepo.GetPost(postID)
+	if err != nil || post.Status != "approved" {
+		return nil, nil, fmt.Errorf("post not found")
 	}
-	if err := uc.walletRepo.CreateTransaction(transaction); err != nil {
-		uc.logger.Error("Failed to create transaction: %v", err)
+
+	if post.CreatorID == userID {
+		return nil, nil, fmt.Errorf("cannot donate to your own post")
 	}
 
-	return wallet, nil
-}
+	// Like purchases, donations only go to posts the viewer can see
+	if post.SubscriberOnly {
+		subscribed, err := uc.subscriptionRepo.HasActiveSubscription(userID, post.CreatorID)
+		if err != nil || !subscribed {
+			return nil, nil, fmt.Errorf("post not found")
+		}
+	}
 
-func (uc *walletUseCase) DonateToPost(userID, postID string, amount int) (*entity.Wallet, error) {
-	ctx := context.Background()
-	postKey := fmt.Sprintf("post:%s", postID)
-	creatorID, err := uc.redisClient.HGet(ctx, postKey, "creator_id").Result()
+	entries, err := uc.feeUseCase.EarningEntries(userID, post.CreatorID, postID, entity.TransactionTypeDonation, amount)
 	if err != nil {
-		return nil, fmt.Errorf("post not found")
+		return nil, nil, err
 	}
-
-	if creatorID == userID {
-		return nil, fmt.Errorf("cannot donate to your own post")
+	transactions, err := uc.walletRepo.ApplyEntries(entries)
+	if err != nil {
+		if errors.Is(err, persistent.ErrInsufficientBalance) {
+			return nil, nil, fmt.Errorf("insufficient balance")
+		}
+		uc.logger.Error("Failed to process donation: %v", err)
+		return nil, nil, fmt.Errorf("failed to process donation: %w", err)
 	}
 
-	wallet, err := uc.walletRepo.GetOrCreateWallet(userID)
+	wallet, err := uc.GetWallet(userID)
 	if err != nil {
-		uc.logger.Error("Failed to get wallet: %v", err)
-		return nil, fmt.Errorf("failed to get wallet: %w", err)
+		return nil, nil, err
+	}
+	return wallet, findTransaction(transactions, userID), nil
+}
+
+func (uc *walletUseCase) PurchasePost(userID, postID string) (*entity.Wallet, error) {
+	post, err := uc.walletRepo.GetPost(postID)
+	if err != nil || post.Status != "approved" {
+		return nil, fmt.Errorf("post not found")
+	}
+
+	if post.CreatorID == userID {
+		return nil, fmt.Errorf("cannot purchase your own post")
 	}
 
-	if wallet.Balance < amount {
-		return nil, fmt.Errorf("insufficient balance")
+	// Subscriber-only posts are hidden from everyone else, so they can't be bought either
+	if post.SubscriberOnly {
+		subscribed, err := uc.subscriptionRepo.HasActiveSubscription(userID, post.CreatorID)
+		if err != nil || !subscribed {
+			return nil, fmt.Errorf("post not found")
+		}
 	}
 
-	balanceBefore := wallet.Balance
-	wallet.Balance -= amount
-	if err := uc.walletRepo.UpdateWallet(wallet); err != nil {
-		uc.logger.Error("Failed to update wallet: %v", err)
-		return nil, fmt.Errorf("failed to process donation: %w", err)
+	if post.Price <= 0 {
+		return nil, fmt.Errorf("post is not for sale")
 	}
 
-	donorTransaction := &entity.Transaction{
-		UserID:        userID,
-		PostID:        postID,
-		Type:          entity.TransactionTypeDonation,
-		Amount:        -amount,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  wallet.Balance,
+	purchased, err := uc.walletRepo.HasPurchased(userID, postID)
+	if err != nil {
+		uc.logger.Error("Failed to check purchase: %v", err)
+		return nil, fmt.Errorf("failed to check purchase: %w", err)
 	}
-	if err := uc.walletRepo.CreateTransaction(donorTransaction); err != nil {
-		uc.logger.Error("Failed to create transaction: %v", err)
+	if purchased {
+		return nil, fmt.Errorf("post already purchased")
 	}
 
-	creatorWallet, err := uc.walletRepo.GetOrCreateWallet(creatorID)
+	entries, err := uc.feeUseCase.EarningEntries(userID, post.CreatorID, postID, entity.TransactionTypePurchase, post.Price)
 	if err != nil {
-		uc.logger.Error("Failed to get creator wallet: %v", err)
-	} else {
-		creatorBalanceBefore := creatorWallet.Balance
-		creatorWallet.Balance += amount
-		if err := uc.walletRepo.UpdateWallet(creatorWallet); err != nil {
-			uc.logger.Error("Failed to update creator wallet: %v", err)
-		} else {
-			creatorTransaction := &entity.Transaction{
-				UserID:        creatorID,
-				PostID:        postID,
-				Type:          entity.TransactionTypeEarn,
-				Amount:        amount,
-				BalanceBefore: creatorBalanceBefore,
-				BalanceAfter:  creatorWallet.Balance,
-			}
-			if err := uc.walletRepo.CreateTransaction(creatorTransaction); err != nil {
-				uc.logger.Error("Failed to create creator transaction: %v", err)
-			}
+		return nil, err
+	}
+	if _, err := uc.walletRepo.PurchasePost(postID, entries); err != nil {
+		switch {
+		case errors.Is(err, persistent.ErrInsufficientBalance):
+			return nil, fmt.Errorf("insufficient balance")
+		case errors.Is(err, persistent.ErrAlreadyPurchased):
+			return nil, fmt.Errorf("post already purchased")
 		}
+		uc.logger.Error("Failed to process purchase: %v", err)
+		return nil, fmt.Errorf("failed to process purchase: %w", err)
 	}
 
-	return wallet, nil
+	return uc.GetWallet(userID)
 }
 
 func (uc *walletUseCase) GetTransactions(userID string, limit, offset int) ([]*entity.Transaction, error) {
@@ -144,3 +154,13 @@ func (uc *walletUseCase) GetTransactions(userID string, limit, offset int) ([]*e
 	}
 	return transactions, nil
 }
+
+// findTransaction picks the ledger row belonging to the given user.
+func findTransaction(transactions []*entity.Transaction, userID string) *entity.Transaction {
+	for _, transaction := range transactions {
+		if transaction.UserID == userID {
+			return transaction
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"testing"
+
+	"lick-scroll/pkg/logger"
+	"lick-scroll/services/wallet/internal/entity"
+	"lick-scroll/services/wallet/internal/repo/persistent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeWalletRepository knows a set of posts and records the transfers it is
+// asked to apply.
+type fakeWalletRepository struct {
+	persistent.WalletRepository
+	posts     map[string]*entity.Post
+	transfers [][]entity.LedgerEntry
+}
+
+func (r *fakeWalletRepository) GetPost(postID string) (*entity.Post, error) {
+	post, ok := r.posts[postID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+func (r *fakeWalletRepository) ApplyEntries(entries []entity.LedgerEntry) ([]*entity.Transaction, error) {
+	r.transfers = append(r.transfers, entries)
+	transactions := make([]*entity.Transaction, len(entries))
+	for i, entry := range entries {
+		transactions[i] = &entity.Transaction{UserID: entry.UserID, PostID: entry.PostID, Type: entry.Type, Amount: entry.Amount}
+	}
+	return transactions, nil
+}
+
+func (r *fakeWalletRepository) GetOrCreateWallet(userID string) (*entity.Wallet, error) {
+	return &entity.Wallet{UserID: userID}, nil
+}
+
+func TestDonateToPost(t *testing.T) {
+	walletRepo := &fakeWalletRepository{posts: map[string]*entity.Post{
+		"approved":  {ID: "approved", CreatorID: "creator", Status: "approved"},
+		"pending":   {ID: "pending", CreatorID: "creator", Status: "pending"},
+		"exclusive": {ID: "exclusive", CreatorID: "creator", Status: "approved", SubscriberOnly: true},
+	}}
+	uc := NewWalletUseCase(walletRepo, &fakeSubscriptionRepository{}, NewFeeUseCase(&fakeFeeRepository{}, 0, logger.New()), logger.New())
+
+	for _, postID := range []string{"missing", "pending", "exclusive"} {
+		_, _, err := uc.DonateToPost("viewer", postID, 100)
+		assert.EqualError(t, err, "post not found", postID)
+	}
+
+	_, _, err := uc.DonateToPost("creator", "approved", 100)
+	assert.EqualError(t, err, "cannot donate to your own post")
+	assert.Empty(t, walletRepo.transfers)
+
+	_, transaction, err := uc.DonateToPost("viewer", "approved", 100)
+	require.NoError(t, err)
+	assert.Equal(t, -100, transaction.Amount)
+	require.Len(t, walletRepo.transfers, 1)
+	assert.Equal(t, "creator", walletRepo.transfers[0][1].UserID, "the creator comes from the post row")
+}